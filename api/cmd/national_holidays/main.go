@@ -0,0 +1,223 @@
+// national_holidays は services で使う国民の祝日・休日の一覧 (national_holidays.go) を生成する
+//
+// 「国民の祝日に関する法律」の規定 (ハッピーマンデー、振替休日、国民の休日) と、
+// 2019年の御即位・2020年と2021年の東京オリンピック・パラリンピックに伴う特例をもとに算出する。
+// 春分日・秋分日は天文計算に基づく近似式で求めるため、前年2月の官報で公表される日付と
+// 異なる場合は specialHolidays で上書きする。法改正があった場合はこのファイルを修正して再生成する。
+//
+// 使い方: go generate ./services
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	outputFile = "national_holidays.go"
+	firstYear  = 2000
+	lastYear   = 2050
+)
+
+// holiday 祝日の名称と日付
+type holiday struct {
+	date time.Time
+	name string
+}
+
+// specialHolidays 特別法による祝日の追加・移動 (名称が空の日は祝日でなくなる)
+var specialHolidays = map[string]string{
+	"2019-05-01": "休日 (天皇の即位の日)",
+	"2019-10-22": "休日 (即位礼正殿の儀の行われる日)",
+	// 東京オリンピック・パラリンピック特別措置法
+	"2020-07-23": "海の日",
+	"2020-07-24": "スポーツの日",
+	"2020-08-10": "山の日",
+	"2021-07-22": "海の日",
+	"2021-07-23": "スポーツの日",
+	"2021-08-08": "山の日",
+}
+
+func main() {
+	var holidays []holiday
+	for year := firstYear; year <= lastYear; year++ {
+		holidays = append(holidays, holidaysOf(year)...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by go run ../cmd/national_holidays; DO NOT EDIT.\n\n")
+	buf.WriteString("package services\n\n")
+	fmt.Fprintf(&buf, "// nationalHolidays 国民の祝日・休日 (%d年〜%d年、振替休日・国民の休日を含む)\n", firstYear, lastYear)
+	buf.WriteString("var nationalHolidays = map[string]string{\n")
+	for _, h := range holidays {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", h.date.Format("2006-01-02"), h.name)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format %s: %v", outputFile, err)
+	}
+	if err := os.WriteFile(outputFile, src, 0o644); err != nil {
+		log.Fatalf("failed to write %s: %v", outputFile, err)
+	}
+	log.Printf("Generated %s (%d holidays)", outputFile, len(holidays))
+}
+
+// holidaysOf 指定した年の祝日に振替休日と国民の休日を加えたもの (日付順)
+func holidaysOf(year int) []holiday {
+	named := nationalHolidaysOf(year)
+
+	// 国民の休日: 前日と翌日が祝日である日 (日曜日を除く)
+	var citizens []holiday
+	for key := range named {
+		d := parseDate(key)
+		next := d.AddDate(0, 0, 2)
+		between := d.AddDate(0, 0, 1)
+		if _, ok := named[dateKey(next)]; !ok {
+			continue
+		}
+		if _, ok := named[dateKey(between)]; ok || between.Weekday() == time.Sunday {
+			continue
+		}
+		citizens = append(citizens, holiday{between, "国民の休日"})
+	}
+	all := make(map[string]string, len(named))
+	for key, name := range named {
+		all[key] = name
+	}
+	for _, h := range citizens {
+		all[dateKey(h.date)] = h.name
+	}
+
+	// 振替休日: 祝日が日曜日にあたるときは、その日後の最も近い祝日でない日
+	// (2006年までは翌日の月曜日のみ)
+	var substitutes []holiday
+	for key := range named {
+		d := parseDate(key)
+		if d.Weekday() != time.Sunday {
+			continue
+		}
+		sub := d.AddDate(0, 0, 1)
+		if year >= 2007 {
+			for {
+				if _, ok := all[dateKey(sub)]; !ok {
+					break
+				}
+				sub = sub.AddDate(0, 0, 1)
+			}
+		} else if _, ok := all[dateKey(sub)]; ok {
+			continue
+		}
+		substitutes = append(substitutes, holiday{sub, "振替休日"})
+	}
+	for _, h := range substitutes {
+		all[dateKey(h.date)] = h.name
+	}
+
+	holidays := make([]holiday, 0, len(all))
+	for key, name := range all {
+		holidays = append(holidays, holiday{parseDate(key), name})
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].date.Before(holidays[j].date) })
+	return holidays
+}
+
+// nationalHolidaysOf 指定した年の「国民の祝日」(振替休日・国民の休日を除く)
+func nationalHolidaysOf(year int) map[string]string {
+	h := make(map[string]string)
+	add := func(month time.Month, day int, name string) {
+		h[dateKey(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))] = name
+	}
+
+	add(time.January, 1, "元日")
+	add(time.January, nthMonday(year, time.January, 2), "成人の日")
+	add(time.February, 11, "建国記念の日")
+	if year >= 2020 {
+		add(time.February, 23, "天皇誕生日")
+	}
+	add(time.March, vernalEquinoxDay(year), "春分の日")
+	if year >= 2007 {
+		add(time.April, 29, "昭和の日")
+		add(time.May, 4, "みどりの日")
+	} else {
+		add(time.April, 29, "みどりの日")
+	}
+	add(time.May, 3, "憲法記念日")
+	add(time.May, 5, "こどもの日")
+	if year >= 2003 {
+		add(time.July, nthMonday(year, time.July, 3), "海の日")
+		add(time.September, nthMonday(year, time.September, 3), "敬老の日")
+	} else {
+		add(time.July, 20, "海の日")
+		add(time.September, 15, "敬老の日")
+	}
+	if year >= 2016 {
+		add(time.August, 11, "山の日")
+	}
+	add(time.September, autumnalEquinoxDay(year), "秋分の日")
+	if year >= 2020 {
+		add(time.October, nthMonday(year, time.October, 2), "スポーツの日")
+	} else {
+		add(time.October, nthMonday(year, time.October, 2), "体育の日")
+	}
+	add(time.November, 3, "文化の日")
+	add(time.November, 23, "勤労感謝の日")
+	if year <= 2018 {
+		add(time.December, 23, "天皇誕生日")
+	}
+
+	// 特例で移動した祝日は元の日付から取り除く
+	for key, name := range specialHolidays {
+		if parseDate(key).Year() != year {
+			continue
+		}
+		for k, n := range h {
+			if n == name && k != key {
+				delete(h, k)
+			}
+		}
+		h[key] = name
+	}
+	return h
+}
+
+// nthMonday 指定した月の第n月曜日の日
+func nthMonday(year int, month time.Month, n int) int {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(time.Monday) - int(first.Weekday()) + 7) % 7
+	return 1 + offset + (n-1)*7
+}
+
+// vernalEquinoxDay 春分日 (1980年〜2099年の近似式)
+func vernalEquinoxDay(year int) int {
+	return equinoxDay(year, 20.8431)
+}
+
+// autumnalEquinoxDay 秋分日 (1980年〜2099年の近似式)
+func autumnalEquinoxDay(year int) int {
+	return equinoxDay(year, 23.2488)
+}
+
+func equinoxDay(year int, base float64) int {
+	y := float64(year - 1980)
+	return int(math.Floor(base + 0.242194*y - math.Floor(y/4)))
+}
+
+func dateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func parseDate(key string) time.Time {
+	t, err := time.Parse("2006-01-02", key)
+	if err != nil {
+		log.Fatalf("invalid date %s: %v", key, err)
+	}
+	return t
+}
@@ -33,6 +33,18 @@ func main() {
 		log.Fatalf("seedInsuranceRates failed: %v", err)
 	}
 
+	if err := seed.SeedEmploymentInsuranceRates(db.DB); err != nil {
+		log.Fatalf("SeedEmploymentInsuranceRates failed: %v", err)
+	}
+
+	if err := seed.SeedWithholdingTaxRates(db.DB); err != nil {
+		log.Fatalf("SeedWithholdingTaxRates failed: %v", err)
+	}
+
+	if err := seed.SeedBonusWithholdingTaxRates(db.DB); err != nil {
+		log.Fatalf("SeedBonusWithholdingTaxRates failed: %v", err)
+	}
+
 	if err := seed.SeedAccounts(db.DB); err != nil {
 		log.Fatalf("SeedAccounts failed: %v", err)
 	}
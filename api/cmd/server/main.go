@@ -36,9 +36,39 @@ func main() {
 		&models.PensionInsuranceRate{},
 		&models.AllowanceType{},
 		&models.EmployeeAllowance{},
+		&models.NotificationJob{},
+		&models.WithholdingTaxRate{},
+		&models.EmploymentInsuranceRate{},
+		&models.ResidentTaxNotice{},
+		&models.ResidentTaxInstallment{},
+		&models.StandardRemuneration{},
+		&models.BonusPayment{},
+		&models.BonusWithholdingTaxRate{},
+		&models.PayrollRun{},
+		&models.Payslip{},
+		&models.PayslipItem{},
+		&models.CompanyRemitter{},
+		&models.YearEndAdjustment{},
+		&models.YearEndDependent{},
+		&models.WageRate{},
+		&models.RecurringAllowance{},
+		&models.PaidLeaveGrant{},
+		&models.PaidLeaveUsage{},
+		&models.LeaveRequest{},
+		&models.ShiftPattern{},
+		&models.ShiftAssignment{},
+		&models.CompanyHoliday{},
+		&models.FlextimeSetting{},
+		&models.FlextimeCarryOver{},
 	); err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
+	if err := services.LinkLegacyWorkRecords(db.DB); err != nil {
+		log.Fatalf("failed to link legacy work records: %v", err)
+	}
+
+	// 退勤打刻忘れ通知などのジョブを実行するスケジューラーを起動
+	services.StartNotificationScheduler()
 
 	routes.Run(cfg)
 }
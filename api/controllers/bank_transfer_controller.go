@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+	"gorm.io/gorm"
+)
+
+// BankAccountInput 従業員の給与の振込先口座
+type BankAccountInput struct {
+	BankCode          string `json:"bank_code" binding:"required"`
+	BranchCode        string `json:"branch_code" binding:"required"`
+	AccountType       string `json:"account_type" binding:"required,oneof=ordinary checking savings"`
+	AccountNumber     string `json:"account_number" binding:"required"`
+	AccountHolderKana string `json:"account_holder_kana" binding:"required"`
+}
+
+// CompanyRemitterInput 給与振込の振込元 (委託者) の情報
+type CompanyRemitterInput struct {
+	RequesterCode string `json:"requester_code" binding:"required"`
+	RequesterName string `json:"requester_name" binding:"required"`
+	BankCode      string `json:"bank_code" binding:"required"`
+	BankName      string `json:"bank_name"`
+	BranchCode    string `json:"branch_code" binding:"required"`
+	BranchName    string `json:"branch_name"`
+	AccountType   string `json:"account_type" binding:"required,oneof=ordinary checking savings"`
+	AccountNumber string `json:"account_number" binding:"required"`
+}
+
+func formatBankAccount(emp models.Employee) gin.H {
+	return gin.H{
+		"employee_id":         emp.ID,
+		"bank_code":           emp.BankCode,
+		"branch_code":         emp.BranchCode,
+		"account_type":        emp.AccountType,
+		"account_number":      emp.AccountNumber,
+		"account_holder_kana": emp.AccountHolderKana,
+	}
+}
+
+// GetEmployeeBankAccount 従業員の振込先口座を取得（管理者専用）
+func GetEmployeeBankAccount(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var emp models.Employee
+	if err := db.DB.First(&emp, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatBankAccount(emp))
+}
+
+// UpdateEmployeeBankAccount 従業員の振込先口座を登録（管理者専用）
+func UpdateEmployeeBankAccount(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input BankAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var emp models.Employee
+	if err := db.DB.First(&emp, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+
+	emp.BankCode = input.BankCode
+	emp.BranchCode = input.BranchCode
+	emp.AccountType = input.AccountType
+	emp.AccountNumber = input.AccountNumber
+	emp.AccountHolderKana = input.AccountHolderKana
+	if err := db.DB.Save(&emp).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatBankAccount(emp))
+}
+
+// GetCompanyRemitter 会社の給与振込の振込元を取得（管理者専用）
+func GetCompanyRemitter(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var remitter models.CompanyRemitter
+	if err := db.DB.Where("company_id = ?", companyID).First(&remitter).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "remitter not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, remitter)
+}
+
+// UpdateCompanyRemitter 会社の給与振込の振込元を登録・更新（管理者専用）
+func UpdateCompanyRemitter(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input CompanyRemitterInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var remitter models.CompanyRemitter
+	err = db.DB.Where("company_id = ?", companyID).First(&remitter).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	remitter.CompanyID = companyID
+	remitter.RequesterCode = input.RequesterCode
+	remitter.RequesterName = input.RequesterName
+	remitter.BankCode = input.BankCode
+	remitter.BankName = input.BankName
+	remitter.BranchCode = input.BranchCode
+	remitter.BranchName = input.BranchName
+	remitter.AccountType = input.AccountType
+	remitter.AccountNumber = input.AccountNumber
+	if err := db.DB.Save(&remitter).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, remitter)
+}
+
+// ExportBankTransfer 指定した年・月の差引支給額を全銀フォーマットの給与振込データで出力（管理者専用）
+// pay_date は振込指定日 ("2006-01-02" 形式)。振込元・振込先に不備があれば従業員ごとの理由を 422 で返す
+func ExportBankTransfer(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	payDate, err := time.ParseInLocation("2006-01-02", c.Query("pay_date"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pay_date format"})
+		return
+	}
+
+	body, err := services.ExportBankTransferFile(db.DB, companyID, year, month, payDate)
+	var invalid *services.BankTransferValidationError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "details": invalid})
+		return
+	} else if errors.Is(err, services.ErrPayrollRunNotLocked) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="zengin_%04d%02d.txt"`, year, month))
+	c.Data(http.StatusOK, "text/plain; charset=Shift_JIS", body)
+}
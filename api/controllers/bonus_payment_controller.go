@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+// BonusPaymentInput 賞与の入力 (paid_on は "2006-01-02" 形式)
+type BonusPaymentInput struct {
+	EmployeeID uint   `json:"employee_id" binding:"required"`
+	PaidOn     string `json:"paid_on" binding:"required"`
+	Amount     int    `json:"amount" binding:"min=0"`
+	Note       string `json:"note"`
+}
+
+// findBonusPayment 会社に所属する従業員の賞与を取得
+func findBonusPayment(c *gin.Context) (models.BonusPayment, bool) {
+	var bonus models.BonusPayment
+	if err := db.DB.First(&bonus, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bonus payment not found"})
+		return bonus, false
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return bonus, false
+	}
+
+	if err := helpers.CheckEmployeeAccess(bonus.EmployeeID, companyID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return bonus, false
+	}
+
+	return bonus, true
+}
+
+// CreateBonusPayment 賞与の支給を登録（管理者専用）
+func CreateBonusPayment(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	var input BonusPaymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	paidOn, err := time.ParseInLocation("2006-01-02", input.PaidOn, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid paid_on format"})
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := helpers.CheckEmployeeAccess(input.EmployeeID, companyID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	bonus := models.BonusPayment{
+		EmployeeID: input.EmployeeID,
+		PaidOn:     paidOn,
+		Amount:     input.Amount,
+		Note:       input.Note,
+	}
+	if err := db.DB.Create(&bonus).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, bonus)
+}
+
+func GetBonusPayments(c *gin.Context) {
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.DB.
+		Joins("JOIN employees ON employees.id = bonus_payments.employee_id").
+		Where("employees.company_id = ?", companyID)
+
+	// 従業員IDで絞り込み
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		query = query.Where("bonus_payments.employee_id = ?", employeeID)
+	}
+
+	// 支給年で絞り込み
+	if year := c.Query("year"); year != "" {
+		query = query.Where("EXTRACT(YEAR FROM bonus_payments.paid_on) = ?", year)
+	}
+
+	bonuses := []models.BonusPayment{}
+	if err := query.Order("bonus_payments.paid_on DESC").Find(&bonuses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bonuses)
+}
+
+func GetBonusPayment(c *gin.Context) {
+	bonus, ok := findBonusPayment(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, bonus)
+}
+
+// DeleteBonusPayment 賞与の支給を削除（管理者専用）
+func DeleteBonusPayment(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	bonus, ok := findBonusPayment(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DB.Delete(&bonus).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bonus payment deleted"})
+}
+
+// CalculateBonusPayment 賞与から控除する社会保険料・源泉所得税と手取り額を計算
+func CalculateBonusPayment(c *gin.Context) {
+	bonus, ok := findBonusPayment(c)
+	if !ok {
+		return
+	}
+
+	resp, err := services.CalculateBonus(db.DB, bonus.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+// CompanyHolidayInput 会社カレンダーに指定する日 (同じ日の指定は置き換える)
+type CompanyHolidayInput struct {
+	Date string                    `json:"date" binding:"required"`
+	Kind models.CompanyHolidayKind `json:"kind" binding:"required,oneof=closed legal_holiday workday"`
+	Name string                    `json:"name"`
+}
+
+// currentCompany ログイン中のアカウントが所属する会社を取得
+func currentCompany(c *gin.Context) (models.Company, bool) {
+	var company models.Company
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return company, false
+	}
+	if err := db.DB.First(&company, companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "company not found"})
+		return company, false
+	}
+	return company, true
+}
+
+// GetCompanyCalendar 会社の指定した年・月のカレンダー (所定労働日・法定休日・祝日) と所定労働日数を取得
+func GetCompanyCalendar(c *gin.Context) {
+	company, ok := currentCompany(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := services.GetCompanyCalendar(db.DB, company, year, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// GetCompanyHolidays 会社カレンダーに指定した年の休日・出勤日の一覧を取得
+func GetCompanyHolidays(c *gin.Context) {
+	company, ok := currentCompany(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	holidays := []models.CompanyHoliday{}
+	if err := db.DB.Where("company_id = ? AND date >= ? AND date < ?", company.ID, from, from.AddDate(1, 0, 0)).
+		Order("date ASC").
+		Find(&holidays).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+// SaveCompanyHoliday 会社カレンダーに休日・出勤日を指定（管理者専用）
+// その週の勤務記録の休日労働・時間外労働を再集計する
+func SaveCompanyHoliday(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	company, ok := currentCompany(c)
+	if !ok {
+		return
+	}
+
+	var input CompanyHolidayInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := time.ParseInLocation("2006-01-02", input.Date, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+		return
+	}
+
+	holiday := models.CompanyHoliday{Date: date, Kind: input.Kind, Name: input.Name}
+	if err := services.SaveCompanyHoliday(db.DB, company, &holiday); err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, holiday)
+}
+
+// DeleteCompanyHoliday 会社カレンダーの休日・出勤日の指定を削除（管理者専用）
+func DeleteCompanyHoliday(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	company, ok := currentCompany(c)
+	if !ok {
+		return
+	}
+
+	var holiday models.CompanyHoliday
+	if err := db.DB.Where("id = ? AND company_id = ?", c.Param("id"), company.ID).First(&holiday).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "company holiday not found"})
+		return
+	}
+
+	if err := services.DeleteCompanyHoliday(db.DB, company, holiday); err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "company holiday deleted"})
+}
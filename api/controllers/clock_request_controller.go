@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/t2469/attendance-system.git/db"
 	"github.com/t2469/attendance-system.git/helpers"
@@ -181,21 +182,16 @@ func ApproveClockRequest(c *gin.Context) {
 		return
 	}
 
-	// 打刻の内容を申請内容で更新
-	clock.Type = req.Type
-	clock.Timestamp = req.Time
-	if err := db.DB.Save(&clock).Error; err != nil {
+	// 打刻の内容を申請内容で更新し、修正前後の勤務の WorkRecord を再集計
+	if err := services.ApplyClockCorrection(&clock, req.Type, req.Time); err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update clock"})
 		return
 	}
 
-	// 該当日の WorkRecord を再集計
-	day := clock.Timestamp.In(time.Local).Truncate(24 * time.Hour)
-	if err := services.UpsertWorkRecord(req.EmployeeID, day); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update work record"})
-		return
-	}
-
 	// 申請ステータスを更新
 	now := time.Now()
 	req.Status = models.Approved
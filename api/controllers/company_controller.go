@@ -3,10 +3,37 @@ package controllers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
 	"github.com/t2469/attendance-system.git/models"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// CompanySettingsInput 会社ごとの勤怠設定 (指定された項目のみ更新する)
+type CompanySettingsInput struct {
+	BusinessDayStartHour           *int     `json:"business_day_start_hour" binding:"omitempty,min=0,max=23"`
+	MaxShiftHours                  *int     `json:"max_shift_hours" binding:"omitempty,min=1,max=48"`
+	LegalDailyMinutes              *int     `json:"legal_daily_minutes" binding:"omitempty,min=1,max=1440"`
+	LegalWeeklyMinutes             *int     `json:"legal_weekly_minutes" binding:"omitempty,min=1,max=10080"`
+	WeekStartWeekday               *int     `json:"week_start_weekday" binding:"omitempty,min=0,max=6"`
+	LegalHolidayWeekday            *int     `json:"legal_holiday_weekday" binding:"omitempty,min=0,max=6"`
+	RestWeekdays                   *[]int   `json:"rest_weekdays" binding:"omitempty,dive,min=0,max=6"`
+	WorkOnNationalHolidays         *bool    `json:"work_on_national_holidays"`
+	LateNightStartHour             *int     `json:"late_night_start_hour" binding:"omitempty,min=0,max=23"`
+	LateNightEndHour               *int     `json:"late_night_end_hour" binding:"omitempty,min=0,max=23"`
+	ScheduledMonthlyHours          *float64 `json:"scheduled_monthly_hours" binding:"omitempty,gt=0"`
+	OvertimePremiumRate            *float64 `json:"overtime_premium_rate" binding:"omitempty,min=0"`
+	ExcessOvertimePremiumRate      *float64 `json:"excess_overtime_premium_rate" binding:"omitempty,min=0"`
+	ExcessOvertimeThresholdMinutes *int     `json:"excess_overtime_threshold_minutes" binding:"omitempty,min=0"`
+	LateNightPremiumRate           *float64 `json:"late_night_premium_rate" binding:"omitempty,min=0"`
+	HolidayPremiumRate             *float64 `json:"holiday_premium_rate" binding:"omitempty,min=0"`
+	WorkMinutesRoundingUnit        *int     `json:"work_minutes_rounding_unit" binding:"omitempty,min=1,max=60"`
+	WageRoundingMode               *string  `json:"wage_rounding_mode" binding:"omitempty,oneof=round floor ceil"`
+	IndustryClass                  *string  `json:"industry_class" binding:"omitempty,oneof=general agriculture construction"`
+}
+
 func CreateCompany(c *gin.Context) {
 	var company models.Company
 	if err := c.ShouldBind(&company); err != nil {
@@ -21,3 +48,128 @@ func CreateCompany(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, company)
 }
+
+// GetCurrentCompany ログイン中のアカウントが所属する会社を取得
+func GetCurrentCompany(c *gin.Context) {
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company models.Company
+	if err := db.DB.First(&company, companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "company not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
+
+// UpdateCompanySettings ログイン中のアカウントが所属する会社の設定を更新（管理者専用）
+func UpdateCompanySettings(c *gin.Context) {
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	var input CompanySettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company models.Company
+	if err := db.DB.First(&company, companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "company not found"})
+		return
+	}
+
+	if input.BusinessDayStartHour != nil {
+		company.BusinessDayStartHour = *input.BusinessDayStartHour
+	}
+	if input.MaxShiftHours != nil {
+		company.MaxShiftHours = *input.MaxShiftHours
+	}
+	if input.LegalDailyMinutes != nil {
+		company.LegalDailyMinutes = *input.LegalDailyMinutes
+	}
+	if input.LegalWeeklyMinutes != nil {
+		company.LegalWeeklyMinutes = *input.LegalWeeklyMinutes
+	}
+	if input.WeekStartWeekday != nil {
+		company.WeekStartWeekday = *input.WeekStartWeekday
+	}
+	if input.LegalHolidayWeekday != nil {
+		company.LegalHolidayWeekday = *input.LegalHolidayWeekday
+	}
+	if input.RestWeekdays != nil {
+		company.RestWeekdays = formatWeekdays(*input.RestWeekdays)
+	}
+	if input.WorkOnNationalHolidays != nil {
+		company.WorkOnNationalHolidays = *input.WorkOnNationalHolidays
+	}
+	if input.LateNightStartHour != nil {
+		company.LateNightStartHour = *input.LateNightStartHour
+	}
+	if input.LateNightEndHour != nil {
+		company.LateNightEndHour = *input.LateNightEndHour
+	}
+	if input.ScheduledMonthlyHours != nil {
+		company.ScheduledMonthlyHours = *input.ScheduledMonthlyHours
+	}
+	if input.OvertimePremiumRate != nil {
+		company.OvertimePremiumRate = *input.OvertimePremiumRate
+	}
+	if input.ExcessOvertimePremiumRate != nil {
+		company.ExcessOvertimePremiumRate = *input.ExcessOvertimePremiumRate
+	}
+	if input.ExcessOvertimeThresholdMinutes != nil {
+		company.ExcessOvertimeThresholdMinutes = *input.ExcessOvertimeThresholdMinutes
+	}
+	if input.LateNightPremiumRate != nil {
+		company.LateNightPremiumRate = *input.LateNightPremiumRate
+	}
+	if input.HolidayPremiumRate != nil {
+		company.HolidayPremiumRate = *input.HolidayPremiumRate
+	}
+	if input.WorkMinutesRoundingUnit != nil {
+		company.WorkMinutesRoundingUnit = *input.WorkMinutesRoundingUnit
+	}
+	if input.WageRoundingMode != nil {
+		company.WageRoundingMode = *input.WageRoundingMode
+	}
+	if input.IndustryClass != nil {
+		company.IndustryClass = *input.IndustryClass
+	}
+
+	if err := db.DB.Save(&company).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
+
+// formatWeekdays 曜日の一覧を重複を除いて昇順のカンマ区切りにする
+func formatWeekdays(weekdays []int) string {
+	seen := make(map[int]bool)
+	var sorted []int
+	for _, w := range weekdays {
+		if !seen[w] {
+			seen[w] = true
+			sorted = append(sorted, w)
+		}
+	}
+	sort.Ints(sorted)
+
+	parts := make([]string, len(sorted))
+	for i, w := range sorted {
+		parts[i] = strconv.Itoa(w)
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -46,6 +47,10 @@ func CreateEmployeeAllowance(c *gin.Context) {
 	}
 
 	if err := db.DB.Create(&ea).Error; err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -150,6 +155,10 @@ func UpdateEmployeeAllowance(c *gin.Context) {
 	}
 
 	if err := db.DB.Save(&ea).Error; err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -194,6 +203,10 @@ func DeleteEmployeeAllowance(c *gin.Context) {
 	}
 
 	if err := db.DB.Delete(&ea).Error; err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -17,10 +17,22 @@ func formatEmployee(emp models.Employee) gin.H {
 		"name":           emp.Name,
 		"line_linked":    emp.LineUserID != nil && *emp.LineUserID != "",
 		"monthly_salary": emp.MonthlySalary,
+		"pay_type":       emp.PayType,
 		"date_of_birth":  emp.DateOfBirth.In(time.Local).Format("2006/1/2"),
+		"dependents":     emp.Dependents,
+		"tax_table":      emp.TaxTable,
+		"hire_date":      formatHireDate(emp.HireDate),
 	}
 }
 
+// formatHireDate 入社日 (未登録の場合は nil)
+func formatHireDate(hireDate *time.Time) any {
+	if hireDate == nil {
+		return nil
+	}
+	return hireDate.In(time.Local).Format("2006/1/2")
+}
+
 func GetEmployees(c *gin.Context) {
 	companyID, exists := c.Get("company_id")
 	if !exists {
@@ -169,6 +181,46 @@ func CalculateEmployeePension(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// CalculateEmployeeEmploymentInsurance 計算対象の年月の賃金総額と雇用保険料率で保険料を計算
+func CalculateEmployeeEmploymentInsurance(c *gin.Context) {
+	id := c.Param("id")
+	employeeID, err := strconv.Atoi(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return
+	}
+
+	yearParam := c.Query("year")
+	if yearParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "year parameter is required"})
+		return
+	}
+	year, err := strconv.Atoi(yearParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+		return
+	}
+
+	monthParam := c.Query("month")
+	if monthParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month parameter is required"})
+		return
+	}
+	month, err := strconv.Atoi(monthParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid month"})
+		return
+	}
+
+	resp, err := services.CalculateEmploymentInsurance(db.DB, uint(employeeID), year, month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 func CalculateEmployeePayroll(c *gin.Context) {
 	id := c.Param("id")
 	employeeID, err := strconv.Atoi(id)
@@ -187,13 +239,16 @@ func CalculateEmployeePayroll(c *gin.Context) {
 	year, err := strconv.Atoi(yearParam)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+		return
 	}
 	month, err := strconv.Atoi(monthParam)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid month"})
+		return
 	}
 
-	resp, err := services.CalculatePayroll(db.DB, uint(employeeID), year, month)
+	// 給与計算が締められている月は確定した給与明細の内容を返す
+	resp, err := services.PayrollForMonth(db.DB, uint(employeeID), year, month)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
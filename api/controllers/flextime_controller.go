@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+	"gorm.io/gorm"
+)
+
+// WorkingTimeInput 従業員の労働時間制 (flextime の場合は flextime の設定が必要)
+type WorkingTimeInput struct {
+	WorkingTimeSystem string                `json:"working_time_system" binding:"required,oneof=standard flextime"`
+	Flextime          *FlextimeSettingInput `json:"flextime"`
+}
+
+// FlextimeSettingInput フレックスタイム制の設定 (コアタイムの時刻は "10:00" 形式、省略した場合はコアタイムなし)
+type FlextimeSettingInput struct {
+	StartYear            int    `json:"start_year" binding:"required"`
+	StartMonth           int    `json:"start_month" binding:"required,min=1,max=12"`
+	SettlementMonths     int    `json:"settlement_months" binding:"required,min=1,max=3"`
+	StandardDailyMinutes int    `json:"standard_daily_minutes" binding:"required,min=1,max=1440"`
+	CoreStartTime        string `json:"core_start_time"`
+	CoreEndTime          string `json:"core_end_time"`
+	MaxCarryOverMinutes  int    `json:"max_carry_over_minutes" binding:"min=0"`
+}
+
+// formatWorkingTime 従業員の労働時間制とフレックスタイム制の設定 (設定がなければ flextime は null)
+func formatWorkingTime(emp models.Employee, setting *models.FlextimeSetting) gin.H {
+	return gin.H{
+		"employee_id":         emp.ID,
+		"working_time_system": emp.WorkingTimeSystem,
+		"flextime":            setting,
+	}
+}
+
+// GetWorkingTime 従業員の労働時間制とフレックスタイム制の設定を取得
+func GetWorkingTime(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var emp models.Employee
+	if err := db.DB.First(&emp, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+
+	var setting *models.FlextimeSetting
+	var found models.FlextimeSetting
+	err := db.DB.Where("employee_id = ?", emp.ID).First(&found).Error
+	if err == nil {
+		setting = &found
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatWorkingTime(emp, setting))
+}
+
+// UpdateWorkingTime 従業員の労働時間制とフレックスタイム制の設定を登録（管理者専用）
+// 適用期間の勤務記録の時間外労働を再計算するため、給与計算が締められた月に影響する場合は変更できない
+func UpdateWorkingTime(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input WorkingTimeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.WorkingTimeSystem == models.WorkingTimeFlextime && input.Flextime == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "flextime settings are required"})
+		return
+	}
+
+	var emp models.Employee
+	if err := db.DB.First(&emp, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+
+	var setting *models.FlextimeSetting
+	if input.Flextime != nil {
+		setting = &models.FlextimeSetting{
+			StartYear:            input.Flextime.StartYear,
+			StartMonth:           input.Flextime.StartMonth,
+			SettlementMonths:     input.Flextime.SettlementMonths,
+			StandardDailyMinutes: input.Flextime.StandardDailyMinutes,
+			CoreStartTime:        input.Flextime.CoreStartTime,
+			CoreEndTime:          input.Flextime.CoreEndTime,
+			MaxCarryOverMinutes:  input.Flextime.MaxCarryOverMinutes,
+		}
+	}
+
+	if err := services.SaveWorkingTimeSystem(db.DB, &emp, input.WorkingTimeSystem, setting); err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatWorkingTime(emp, setting))
+}
+
+// GetFlextimeSettlement フレックスタイム制の従業員の指定した年・月を含む清算期間の清算結果を取得
+func GetFlextimeSettlement(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := services.CalculateFlextimeSettlement(db.DB, employeeID, year, month)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+// LeaveReqInput 休暇の申請内容 (日付は "2006-01-02" 形式、時間単位の場合は minutes に取得時間を指定)
+type LeaveReqInput struct {
+	EmployeeID uint   `json:"employee_id" binding:"required"`
+	Type       string `json:"type" binding:"required,oneof=paid_full_day paid_half_day paid_hourly special"`
+	Date       string `json:"date" binding:"required"`
+	Minutes    int    `json:"minutes" binding:"min=0"`
+	Reason     string `json:"reason"`
+}
+
+type LeaveReqOutput struct {
+	models.LeaveRequest
+	EmployeeName string `json:"employee_name"`
+}
+
+// CreateLeaveRequest 休暇を申請する
+func CreateLeaveRequest(c *gin.Context) {
+	var input LeaveReqInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// トークンから会社IDを取得
+	compID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	// リクエストされた社員IDが、その会社に属しているかチェック
+	if err := helpers.CheckEmployeeAccess(input.EmployeeID, compID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", input.Date, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+		return
+	}
+
+	// LeaveRequestを作成（StatusはデフォルトでPending）
+	req := models.LeaveRequest{
+		EmployeeID: input.EmployeeID,
+		Type:       models.LeaveType(input.Type),
+		Date:       date,
+		Minutes:    input.Minutes,
+		Status:     models.Pending,
+		Reason:     input.Reason,
+	}
+
+	if err := db.DB.Create(&req).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, req)
+}
+
+// GetLeaveRequests 会社の休暇の申請を取得 (employee_id・status で絞り込み可)
+func GetLeaveRequests(c *gin.Context) {
+	compID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	employeeID := c.Query("employee_id")
+	status := c.Query("status")
+
+	req := []LeaveReqOutput{}
+
+	query := db.DB.
+		Table("leave_requests").
+		Select("leave_requests.*, employees.name as employee_name").
+		Joins("JOIN employees ON employees.id = leave_requests.employee_id").
+		Where("employees.company_id = ?", compID)
+
+	if employeeID != "" {
+		query = query.Where("leave_requests.employee_id = ?", employeeID)
+	}
+	if status != "" {
+		query = query.Where("leave_requests.status = ?", status)
+	}
+
+	if err := query.Order("leave_requests.created_at DESC").Scan(&req).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, req)
+}
+
+// ApproveLeaveRequest は休暇の申請を承認し、有給休暇の残日数を差し引いて休暇の勤務記録を作成する（管理者専用）
+func ApproveLeaveRequest(c *gin.Context) {
+	req, accountID, ok := reviewableLeaveRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := services.ApproveLeaveRequest(db.DB, &req, accountID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrLeaveRequestReviewed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrPayrollPeriodLocked),
+			errors.Is(err, services.ErrInsufficientPaidLeave),
+			errors.Is(err, services.ErrPaidLeaveAlreadyTaken),
+			errors.Is(err, services.ErrHourlyPaidLeaveLimit),
+			errors.Is(err, services.ErrLeaveAlreadyTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "request approved",
+		"request": req,
+	})
+}
+
+// RejectLeaveRequest は休暇の申請を却下する（管理者専用）
+func RejectLeaveRequest(c *gin.Context) {
+	req, accountID, ok := reviewableLeaveRequest(c)
+	if !ok {
+		return
+	}
+
+	// 却下処理（有給休暇の残日数や勤務記録は変更せず、申請状態のみ更新）
+	now := time.Now()
+	req.Status = models.Rejected
+	req.ReviewedBy = &accountID
+	req.ReviewedAt = &now
+
+	result := db.DB.Model(&req).Where("status = ?", models.Pending).
+		Select("status", "reviewed_by", "reviewed_at").
+		Updates(&req)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update request"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "request already reviewed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "request rejected",
+		"request": req,
+	})
+}
+
+// reviewableLeaveRequest 管理者であることと、未処理の申請がログイン中の会社の従業員のものであることを確認する
+func reviewableLeaveRequest(c *gin.Context) (models.LeaveRequest, uint, bool) {
+	var req models.LeaveRequest
+
+	reqID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request id"})
+		return req, 0, false
+	}
+
+	// JWTトークンから認証情報を取得
+	accountID, err := helpers.GetAccountID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return req, 0, false
+	}
+	if !helpers.RequireAdmin(c) {
+		return req, 0, false
+	}
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return req, 0, false
+	}
+
+	if err := db.DB.First(&req, reqID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "request not found"})
+		return req, 0, false
+	}
+
+	// 既にレビュー済みならエラー
+	if req.Status != models.Pending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "request already reviewed"})
+		return req, 0, false
+	}
+
+	// 対象の従業員が同一会社に属しているか確認
+	if err := helpers.CheckEmployeeAccess(req.EmployeeID, companyID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to review request for this employee"})
+		return req, 0, false
+	}
+
+	return req, accountID, true
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
@@ -172,13 +173,15 @@ func clock(e webhook.MessageEvent, _ []string, clockType models.TimeClockType, c
 	now := time.Now()
 	var okList []string
 	var ngList []string
+	var ngReasons []string
 
 	for _, emp := range employees {
-		_, err := services.RecordTimeClock(emp.ID, clockType, now)
+		_, err := services.RecordTimeClock(emp.ID, clockType, now, false, nil)
 		info := fmt.Sprintf("%sさん", emp.Name)
 		if err != nil {
 			log.Println(err)
 			ngList = append(ngList, info)
+			ngReasons = append(ngReasons, clockErrorReason(err))
 		} else {
 			okList = append(okList, info)
 		}
@@ -191,6 +194,9 @@ func clock(e webhook.MessageEvent, _ []string, clockType models.TimeClockType, c
 			sb.WriteString(fmt.Sprintf("%sの%s打刻を行いました！", okList[0], clockName))
 		} else {
 			sb.WriteString(fmt.Sprintf("%sの%s打刻に失敗しました...", ngList[0], clockName))
+			if ngReasons[0] != "" {
+				sb.WriteString("\n" + ngReasons[0])
+			}
 		}
 	} else {
 		// 複数人いる場合のみリスト表示する
@@ -202,7 +208,10 @@ func clock(e webhook.MessageEvent, _ []string, clockType models.TimeClockType, c
 		}
 		if len(ngList) > 0 {
 			sb.WriteString(fmt.Sprintf("以下の従業員の%s打刻に失敗しました。\n", clockName))
-			for _, info := range ngList {
+			for i, info := range ngList {
+				if ngReasons[i] != "" {
+					info += " (" + ngReasons[i] + ")"
+				}
 				sb.WriteString("・" + info + "\n")
 			}
 		}
@@ -210,3 +219,18 @@ func clock(e webhook.MessageEvent, _ []string, clockType models.TimeClockType, c
 
 	services.Reply(e.ReplyToken, sb.String())
 }
+
+// clockErrorReason 勤務状態と合わない打刻だった場合にその理由を返す
+func clockErrorReason(err error) string {
+	if errors.Is(err, models.ErrPayrollPeriodLocked) {
+		return "給与計算が締められた期間です"
+	}
+	var transErr *services.ClockTransitionError
+	if !errors.As(err, &transErr) {
+		return ""
+	}
+	if transErr.Next != nil {
+		return "後の打刻と矛盾しています"
+	}
+	return fmt.Sprintf("現在%sです", transErr.State.Label())
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+	"gorm.io/gorm"
+)
+
+// EmploymentInput 従業員の入社日と週の所定労働日数・時間 (入社日は "2006-01-02" 形式)
+type EmploymentInput struct {
+	HireDate          string `json:"hire_date" binding:"required"`
+	WeeklyWorkDays    int    `json:"weekly_work_days" binding:"required,min=1,max=7"`
+	WeeklyWorkMinutes int    `json:"weekly_work_minutes" binding:"required,min=1"`
+}
+
+// PaidLeaveUsageInput 有給休暇を取得した日と日数 (1 または 0.5)
+type PaidLeaveUsageInput struct {
+	Date string  `json:"date" binding:"required"`
+	Days float64 `json:"days" binding:"required"`
+}
+
+func formatEmployment(emp models.Employee) gin.H {
+	return gin.H{
+		"employee_id":         emp.ID,
+		"hire_date":           formatHireDate(emp.HireDate),
+		"weekly_work_days":    emp.WeeklyWorkDays,
+		"weekly_work_minutes": emp.WeeklyWorkMinutes,
+	}
+}
+
+// GetEmployment 従業員の入社日と週の所定労働日数・時間を取得
+func GetEmployment(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var emp models.Employee
+	if err := db.DB.First(&emp, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatEmployment(emp))
+}
+
+// UpdateEmployment 従業員の入社日と週の所定労働日数・時間を登録（管理者専用）
+func UpdateEmployment(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input EmploymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hireDate, err := time.ParseInLocation("2006-01-02", input.HireDate, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hire_date format"})
+		return
+	}
+
+	var emp models.Employee
+	if err := db.DB.First(&emp, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+
+	emp.HireDate = &hireDate
+	emp.WeeklyWorkDays = input.WeeklyWorkDays
+	emp.WeeklyWorkMinutes = input.WeeklyWorkMinutes
+	if err := db.DB.Save(&emp).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, formatEmployment(emp))
+}
+
+// GetPaidLeaveLedger 従業員の年次有給休暇管理簿を取得 (date を省略した場合は今日時点)
+func GetPaidLeaveLedger(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	asOf := time.Now()
+	if date := c.Query("date"); date != "" {
+		var err error
+		asOf, err = time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+			return
+		}
+	}
+
+	ledger, err := services.GetPaidLeaveLedger(db.DB, employeeID, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ledger)
+}
+
+// CreatePaidLeaveUsage 従業員の有給休暇の取得を記録（管理者専用）
+func CreatePaidLeaveUsage(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input PaidLeaveUsageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := time.ParseInLocation("2006-01-02", input.Date, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+		return
+	}
+
+	usages, err := services.UsePaidLeave(db.DB, employeeID, date, input.Days)
+	if err != nil {
+		paidLeaveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, usages)
+}
+
+// paidLeaveError 残日数が足りない・既に取得している場合は 409 を返す
+func paidLeaveError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInsufficientPaidLeave), errors.Is(err, services.ErrPaidLeaveAlreadyTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+// CalculateCompanyPayroll 会社の全従業員の給与と、事業主負担分を含む合計を計算（管理者専用）
+func CalculateCompanyPayroll(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	resp, err := services.CalculateCompanyPayroll(db.DB, companyID, year, month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
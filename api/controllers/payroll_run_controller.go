@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+type PayrollRunInput struct {
+	Year  int `json:"year" binding:"required"`
+	Month int `json:"month" binding:"required,min=1,max=12"`
+}
+
+// findPayrollRun ログイン中の会社の給与計算を取得（管理者専用）
+func findPayrollRun(c *gin.Context) (models.PayrollRun, bool) {
+	var run models.PayrollRun
+
+	if !helpers.RequireAdmin(c) {
+		return run, false
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return run, false
+	}
+
+	if err := db.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&run).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payroll run not found"})
+		return run, false
+	}
+
+	return run, true
+}
+
+// payrollRunError 給与計算の状態に関するエラーは 409 として返す
+func payrollRunError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPayrollRunLocked),
+		errors.Is(err, services.ErrPayrollRunNotLocked),
+		errors.Is(err, services.ErrPayrollRunNotComputed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// OpenPayrollRun 会社・月の給与計算を開始（管理者専用）
+func OpenPayrollRun(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input PayrollRunInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	run, err := services.OpenPayrollRun(db.DB, companyID, input.Year, input.Month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+func GetPayrollRuns(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	runs := []models.PayrollRun{}
+	if err := db.DB.Where("company_id = ?", companyID).Order("year DESC, month DESC").Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// GetPayrollRun 給与明細を含めて給与計算を取得
+func GetPayrollRun(c *gin.Context) {
+	run, ok := findPayrollRun(c)
+	if !ok {
+		return
+	}
+
+	run, err := services.GetPayrollRun(db.DB, run.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// ComputePayrollRun 全従業員の給与を計算して給与明細を作り直す
+func ComputePayrollRun(c *gin.Context) {
+	run, ok := findPayrollRun(c)
+	if !ok {
+		return
+	}
+
+	run, err := services.ComputePayrollRun(db.DB, run.ID)
+	if err != nil {
+		payrollRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// LockPayrollRun 給与計算を締めて給与明細を確定
+func LockPayrollRun(c *gin.Context) {
+	run, ok := findPayrollRun(c)
+	if !ok {
+		return
+	}
+
+	accountID, err := helpers.GetAccountID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	run, err = services.LockPayrollRun(db.DB, run.ID, accountID)
+	if err != nil {
+		payrollRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// ReopenPayrollRun 給与計算の締めを解除
+func ReopenPayrollRun(c *gin.Context) {
+	run, ok := findPayrollRun(c)
+	if !ok {
+		return
+	}
+
+	run, err := services.ReopenPayrollRun(db.DB, run.ID)
+	if err != nil {
+		payrollRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
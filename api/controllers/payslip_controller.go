@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+// DownloadEmployeePayslip 従業員の給与明細をPDFで取得
+func DownloadEmployeePayslip(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	body, err := services.RenderPayslipPDF(db.DB, employeeID, year, month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.PayslipFileName(employeeID, year, month)))
+	c.Data(http.StatusOK, "application/pdf", body)
+}
+
+// DownloadCompanyPayslips 会社の全従業員の給与明細PDFをZIPでまとめて取得（管理者専用）
+func DownloadCompanyPayslips(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	body, err := services.RenderCompanyPayslipsZIP(db.DB, companyID, year, month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="payslips_%04d%02d.zip"`, year, month))
+	c.Data(http.StatusOK, "application/zip", body)
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// recurringAllowanceEmployeeAllowed 継続手当の従業員がログイン中の会社の従業員かを確認する
+func recurringAllowanceEmployeeAllowed(c *gin.Context, employeeID, companyID uint) bool {
+	var emp models.Employee
+	if err := db.DB.First(&emp, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return false
+	}
+	if emp.CompanyID != companyID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to manage allowances for this employee"})
+		return false
+	}
+	return true
+}
+
+// recurringAllowanceSaveError 締められた月を含む場合は 409、それ以外は 400 を返す
+func recurringAllowanceSaveError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrPayrollPeriodLocked) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// findRecurringAllowance ログイン中の会社の従業員の継続手当を取得
+func findRecurringAllowance(c *gin.Context) (models.RecurringAllowance, bool) {
+	var ra models.RecurringAllowance
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return ra, false
+	}
+
+	err = db.DB.
+		Joins("JOIN employees ON employees.id = recurring_allowances.employee_id").
+		Where("recurring_allowances.id = ? AND employees.company_id = ?", c.Param("id"), companyID).
+		Preload("AllowanceType").
+		First(&ra).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recurring allowance not found"})
+		return ra, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return ra, false
+	}
+	return ra, true
+}
+
+// CreateRecurringAllowance 開始月から毎月支給する手当を登録（管理者専用）
+func CreateRecurringAllowance(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	var ra models.RecurringAllowance
+	if err := c.ShouldBindJSON(&ra); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if !recurringAllowanceEmployeeAllowed(c, ra.EmployeeID, companyID) {
+		return
+	}
+
+	ra.ID = 0
+	if err := db.DB.Omit("AllowanceType").Create(&ra).Error; err != nil {
+		recurringAllowanceSaveError(c, err)
+		return
+	}
+	db.DB.First(&ra.AllowanceType, ra.AllowanceTypeID)
+
+	c.JSON(http.StatusCreated, ra)
+}
+
+// GetRecurringAllowances 会社の従業員の継続手当の一覧を取得 (employee_id で絞り込み可)
+func GetRecurringAllowances(c *gin.Context) {
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.DB.
+		Joins("JOIN employees ON employees.id = recurring_allowances.employee_id").
+		Where("employees.company_id = ?", companyID)
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		query = query.Where("recurring_allowances.employee_id = ?", employeeID)
+	}
+
+	allowances := []models.RecurringAllowance{}
+	if err := query.
+		Preload("AllowanceType").
+		Order("recurring_allowances.employee_id ASC, recurring_allowances.from_year ASC, recurring_allowances.from_month ASC").
+		Find(&allowances).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, allowances)
+}
+
+func GetRecurringAllowance(c *gin.Context) {
+	ra, ok := findRecurringAllowance(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, ra)
+}
+
+// UpdateRecurringAllowance 継続手当を更新（管理者専用）
+// 締められた月を含む継続手当は、終了月の設定 (その月以降の支給の停止) のみ行える
+func UpdateRecurringAllowance(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	ra, ok := findRecurringAllowance(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&ra); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if !recurringAllowanceEmployeeAllowed(c, ra.EmployeeID, companyID) {
+		return
+	}
+
+	if err := db.DB.Omit("AllowanceType").Save(&ra).Error; err != nil {
+		recurringAllowanceSaveError(c, err)
+		return
+	}
+	db.DB.First(&ra.AllowanceType, ra.AllowanceTypeID)
+
+	c.JSON(http.StatusOK, ra)
+}
+
+// DeleteRecurringAllowance 継続手当を削除（管理者専用）
+func DeleteRecurringAllowance(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	ra, ok := findRecurringAllowance(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DB.Delete(&ra).Error; err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recurring allowance deleted"})
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+// ResidentTaxNoticeInput 特別徴収税額の決定通知の入力
+// 6月分と7月以降の月額を省略した場合は年税額から算出する
+type ResidentTaxNoticeInput struct {
+	EmployeeID    uint   `json:"employee_id" binding:"required"`
+	FiscalYear    int    `json:"fiscal_year" binding:"required"`
+	Municipality  string `json:"municipality"`
+	AnnualAmount  int    `json:"annual_amount" binding:"min=0"`
+	JuneAmount    int    `json:"june_amount" binding:"min=0"`
+	MonthlyAmount int    `json:"monthly_amount" binding:"min=0"`
+}
+
+// findResidentTaxNotice 会社に所属する従業員の通知書を取得
+func findResidentTaxNotice(c *gin.Context) (models.ResidentTaxNotice, bool) {
+	var notice models.ResidentTaxNotice
+	if err := db.DB.First(&notice, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resident tax notice not found"})
+		return notice, false
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return notice, false
+	}
+
+	if err := helpers.CheckEmployeeAccess(notice.EmployeeID, companyID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return notice, false
+	}
+
+	return notice, true
+}
+
+// CreateResidentTaxNotice 特別徴収税額の決定通知を登録（管理者専用）
+func CreateResidentTaxNotice(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	var input ResidentTaxNoticeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := helpers.CheckEmployeeAccess(input.EmployeeID, companyID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	notice := models.ResidentTaxNotice{
+		EmployeeID:    input.EmployeeID,
+		FiscalYear:    input.FiscalYear,
+		Municipality:  input.Municipality,
+		AnnualAmount:  input.AnnualAmount,
+		JuneAmount:    input.JuneAmount,
+		MonthlyAmount: input.MonthlyAmount,
+	}
+	if err := services.SaveResidentTaxNotice(db.DB, &notice); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, notice)
+}
+
+func GetResidentTaxNotices(c *gin.Context) {
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.DB.
+		Joins("JOIN employees ON employees.id = resident_tax_notices.employee_id").
+		Where("employees.company_id = ?", companyID)
+
+	// 従業員IDで絞り込み
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		query = query.Where("resident_tax_notices.employee_id = ?", employeeID)
+	}
+
+	// 年度で絞り込み
+	if fiscalYear := c.Query("fiscal_year"); fiscalYear != "" {
+		query = query.Where("resident_tax_notices.fiscal_year = ?", fiscalYear)
+	}
+
+	notices := []models.ResidentTaxNotice{}
+	if err := query.Order("resident_tax_notices.fiscal_year DESC").Find(&notices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notices)
+}
+
+func GetResidentTaxNotice(c *gin.Context) {
+	notice, ok := findResidentTaxNotice(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DB.Where("notice_id = ?", notice.ID).Order("year ASC, month ASC").Find(&notice.Installments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notice)
+}
+
+// UpdateResidentTaxNotice 特別徴収税額の決定通知を更新し、各月の控除額を作り直す（管理者専用）
+func UpdateResidentTaxNotice(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	notice, ok := findResidentTaxNotice(c)
+	if !ok {
+		return
+	}
+
+	var input ResidentTaxNoticeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 通知書の対象従業員は変更できない
+	if input.EmployeeID != notice.EmployeeID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "employee_id cannot be changed"})
+		return
+	}
+
+	notice.FiscalYear = input.FiscalYear
+	notice.Municipality = input.Municipality
+	notice.AnnualAmount = input.AnnualAmount
+	notice.JuneAmount = input.JuneAmount
+	notice.MonthlyAmount = input.MonthlyAmount
+
+	if err := services.SaveResidentTaxNotice(db.DB, &notice); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notice)
+}
+
+// DeleteResidentTaxNotice 特別徴収税額の決定通知と各月の控除額を削除（管理者専用）
+func DeleteResidentTaxNotice(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+
+	notice, ok := findResidentTaxNotice(c)
+	if !ok {
+		return
+	}
+
+	if err := db.DB.Select("Installments").Delete(&notice).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resident tax notice deleted"})
+}
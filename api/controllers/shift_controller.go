@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+	"gorm.io/gorm"
+)
+
+// ShiftPatternInput 勤務の型 (時刻は "09:00" 形式)
+type ShiftPatternInput struct {
+	Name         string `json:"name" binding:"required"`
+	StartTime    string `json:"start_time" binding:"required"`
+	EndTime      string `json:"end_time" binding:"required"`
+	BreakMinutes int    `json:"break_minutes" binding:"min=0"`
+}
+
+// MonthlyShiftsInput 月の勤務予定 (employee_ids の従業員は assignments に含まれなくても予定を置き換える)
+type MonthlyShiftsInput struct {
+	EmployeeIDs []uint                 `json:"employee_ids"`
+	Assignments []ShiftAssignmentInput `json:"assignments" binding:"dive"`
+}
+
+// ShiftAssignmentInput 1日の勤務予定 (shift_pattern_id が null の場合は休日)
+type ShiftAssignmentInput struct {
+	EmployeeID     uint   `json:"employee_id" binding:"required"`
+	Date           string `json:"date" binding:"required"`
+	ShiftPatternID *uint  `json:"shift_pattern_id"`
+}
+
+// findShiftPattern ログイン中の会社の勤務の型を取得
+func findShiftPattern(c *gin.Context) (models.ShiftPattern, bool) {
+	var pattern models.ShiftPattern
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return pattern, false
+	}
+
+	if err := db.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&pattern).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "shift pattern not found"})
+		return pattern, false
+	}
+	return pattern, true
+}
+
+// CreateShiftPattern 勤務の型を登録（管理者専用）
+func CreateShiftPattern(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input ShiftPatternInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pattern := models.ShiftPattern{
+		CompanyID:    companyID,
+		Name:         input.Name,
+		StartTime:    input.StartTime,
+		EndTime:      input.EndTime,
+		BreakMinutes: input.BreakMinutes,
+	}
+	if err := db.DB.Create(&pattern).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pattern)
+}
+
+// GetShiftPatterns 会社の勤務の型の一覧を取得
+func GetShiftPatterns(c *gin.Context) {
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	patterns := []models.ShiftPattern{}
+	if err := db.DB.Where("company_id = ?", companyID).Order("start_time ASC, id ASC").Find(&patterns).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, patterns)
+}
+
+// UpdateShiftPattern 勤務の型を更新（管理者専用）
+// 登録済みの勤務記録の遅刻・早退は、その月の勤務予定を登録し直したときに判定し直す
+func UpdateShiftPattern(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	pattern, ok := findShiftPattern(c)
+	if !ok {
+		return
+	}
+
+	var input ShiftPatternInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pattern.Name = input.Name
+	pattern.StartTime = input.StartTime
+	pattern.EndTime = input.EndTime
+	pattern.BreakMinutes = input.BreakMinutes
+	if err := db.DB.Save(&pattern).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pattern)
+}
+
+// DeleteShiftPattern 勤務の型を削除（管理者専用、勤務予定で使われている場合は削除できない）
+func DeleteShiftPattern(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	pattern, ok := findShiftPattern(c)
+	if !ok {
+		return
+	}
+
+	var count int64
+	if err := db.DB.Model(&models.ShiftAssignment{}).Where("shift_pattern_id = ?", pattern.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "shift pattern is in use"})
+		return
+	}
+
+	if err := db.DB.Delete(&pattern).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "shift pattern deleted"})
+}
+
+// GetShifts 会社の指定した年・月の勤務予定を取得 (employee_id で絞り込み可)
+func GetShifts(c *gin.Context) {
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	var employeeID uint
+	if id := c.Query("employee_id"); id != "" {
+		parsed, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee_id"})
+			return
+		}
+		employeeID = uint(parsed)
+	}
+
+	assignments, err := services.GetMonthlyShifts(db.DB, companyID, employeeID, year, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+// ReplaceShifts 指定した年・月の勤務予定を従業員ごとにまとめて登録（管理者専用）
+func ReplaceShifts(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	var input MonthlyShiftsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignments := make([]models.ShiftAssignment, 0, len(input.Assignments))
+	for _, a := range input.Assignments {
+		date, err := time.ParseInLocation("2006-01-02", a.Date, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format: " + a.Date})
+			return
+		}
+		assignments = append(assignments, models.ShiftAssignment{
+			EmployeeID:     a.EmployeeID,
+			Date:           date,
+			ShiftPatternID: a.ShiftPatternID,
+		})
+	}
+
+	if err := services.ReplaceMonthlyShifts(db.DB, companyID, year, month, input.EmployeeIDs, assignments); err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := services.GetMonthlyShifts(db.DB, companyID, 0, year, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetEmployeeSchedule 従業員の指定した年・月の勤務予定と実績 (遅刻・早退・欠勤・予定外の勤務) を取得
+func GetEmployeeSchedule(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	days, err := services.MonthlySchedule(db.DB, employeeID, year, month)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, days)
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+)
+
+// GetStandardRemunerations 従業員の標準報酬月額の履歴を取得
+func GetStandardRemunerations(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	histories := []models.StandardRemuneration{}
+	if err := db.DB.Where("employee_id = ?", employeeID).
+		Order("from_year DESC, from_month DESC").
+		Find(&histories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, histories)
+}
+
+// RunEmployeeTeijiKettei 従業員の4月〜6月の報酬から定時決定を行う
+func RunEmployeeTeijiKettei(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	sr, err := services.RunTeijiKettei(db.DB, employeeID, year)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sr)
+}
+
+// RunCompanyTeijiKettei 会社の全従業員について定時決定を行う
+// 対象外の従業員と決定できなかった従業員は、従業員ごとの結果に理由を返す
+func RunCompanyTeijiKettei(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	companyID, err := helpers.GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	results, err := services.RunCompanyTeijiKettei(db.DB, companyID, year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// DetectEmployeeZuijiKaitei 固定的賃金が変動した月を指定して随時改定の要否を判定
+func DetectEmployeeZuijiKaitei(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	result, err := services.DetectZuijiKaitei(db.DB, employeeID, year, month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ApplyEmployeeZuijiKaitei 随時改定の対象であれば標準報酬月額を改定する
+func ApplyEmployeeZuijiKaitei(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+	month, ok := helpers.QueryMonth(c)
+	if !ok {
+		return
+	}
+
+	result, sr, err := services.ApplyZuijiKaitei(db.DB, employeeID, year, month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if sr == nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "zuiji kaitei is not applicable", "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": result, "standard_remuneration": sr})
+}
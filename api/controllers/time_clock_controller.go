@@ -2,9 +2,7 @@ package controllers
 
 import (
 	"errors"
-	"fmt"
 	"github.com/t2469/attendance-system.git/services"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -22,6 +20,7 @@ type CreateTimeClockInput struct {
 	Timestamp  *time.Time           `json:"timestamp"`
 	Notify     bool                 `json:"notify"` // 通知するかのフラグ
 	NotifyAt   string               `json:"notify_at" binding:"omitempty"`
+	Force      bool                 `json:"force"` // 勤務状態の検証をスキップするか (管理者のみ)
 }
 
 func formatTimeClock(tc models.TimeClock) gin.H {
@@ -33,6 +32,20 @@ func formatTimeClock(tc models.TimeClock) gin.H {
 	}
 }
 
+// formatClockTransitionError 不正な打刻のエラーをクライアントが判別できる形式に変換
+func formatClockTransitionError(err *services.ClockTransitionError) gin.H {
+	resp := gin.H{
+		"error": err.Error(),
+		"code":  "invalid_clock_transition",
+		"state": err.State,
+		"type":  err.Type,
+	}
+	if err.Next != nil {
+		resp["next_type"] = *err.Next
+	}
+	return resp
+}
+
 func CreateTimeClock(c *gin.Context) {
 	var input CreateTimeClockInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -51,65 +64,44 @@ func CreateTimeClock(c *gin.Context) {
 		return
 	}
 
+	// 状態の検証をスキップできるのは管理者のみ
+	if input.Force && !helpers.RequireAdmin(c) {
+		return
+	}
+
 	eventTime := time.Now()
 	if input.Timestamp != nil {
 		eventTime = *input.Timestamp
 	}
 
-	timeClock, err := services.RecordTimeClock(input.EmployeeID, input.Type, eventTime)
+	// 打刻を登録する前に通知時刻の形式を確認しておく
+	if input.Notify && input.Type == models.ClockIn {
+		if _, err := services.NextNotifyTime(eventTime, input.NotifyAt); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var notifyAt *string
+	if input.Notify {
+		notifyAt = &input.NotifyAt
+	}
+
+	timeClock, err := services.RecordTimeClock(input.EmployeeID, input.Type, eventTime, input.Force, notifyAt)
 	if err != nil {
+		var transErr *services.ClockTransitionError
+		if errors.As(err, &transErr) {
+			c.JSON(http.StatusConflict, formatClockTransitionError(transErr))
+			return
+		}
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	if input.Notify && input.Type == models.ClockIn {
-		go func(empId uint, notifyAt string, clockIn time.Time) {
-			now := time.Now().In(time.Local)
-
-			t, err := time.Parse("15:04", notifyAt)
-			if err != nil {
-				log.Println("invalid notify_at format:", err)
-				return
-			}
-			notifyTime := time.Date(
-				now.Year(), now.Month(), now.Day(),
-				t.Hour(), t.Minute(), 0, 0,
-				now.Location(),
-			)
-
-			if notifyTime.Before(now) {
-				return
-			}
-
-			wait := time.Until(notifyTime)
-			if wait > 0 {
-				time.Sleep(wait)
-			}
-
-			var cnt int64
-			db.DB.Model(&models.TimeClock{}).Where("employee_id = ? AND type = ? AND timestamp > ?", empId, models.ClockOut, clockIn).Count(&cnt)
-
-			if cnt == 0 {
-				var emp models.Employee
-				if err := db.DB.First(&emp, empId).Error; err != nil {
-					log.Println(err)
-					return
-				}
-				if emp.LineUserID != nil {
-					msg := fmt.Sprintf(
-						"%sさん\n退勤を忘れていませんか？\n出勤時刻: %s\n現在時刻: %s",
-						emp.Name,
-						clockIn.In(time.Local).Format("15:04"),
-						time.Now().In(time.Local).Format("15:04"),
-					)
-					if err := services.SendMessage(*emp.LineUserID, msg); err != nil {
-						log.Println(err)
-					}
-				}
-			}
-		}(input.EmployeeID, input.NotifyAt, eventTime)
-	}
-
 	c.JSON(http.StatusCreated, formatTimeClock(timeClock))
 }
 
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+	"gorm.io/gorm"
+)
+
+// WageRateInput 支払形態と月給・日給・時給の額、適用開始月 (支払形態を省略した場合は従業員の支払形態)
+// 適用開始月を将来の月にすると、昇給などをあらかじめ登録しておける
+type WageRateInput struct {
+	PayType   string `json:"pay_type" binding:"omitempty,oneof=monthly daily hourly"`
+	Rate      int    `json:"rate" binding:"required,min=1"`
+	FromYear  int    `json:"from_year" binding:"required"`
+	FromMonth int    `json:"from_month" binding:"required,min=1,max=12"`
+}
+
+// GetWageRates 従業員の給与の履歴 (適用開始前のものを含む) を取得
+func GetWageRates(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	rates := []models.WageRate{}
+	if err := db.DB.Where("employee_id = ?", employeeID).
+		Order("from_year DESC, from_month DESC").
+		Find(&rates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// CreateWageRate 従業員の給与を登録 (同じ適用開始月があれば上書き)（管理者専用）
+func CreateWageRate(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input WageRateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.PayType == "" {
+		var emp models.Employee
+		if err := db.DB.First(&emp, employeeID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		input.PayType = emp.PayType
+	}
+
+	rate := models.WageRate{
+		EmployeeID: employeeID,
+		PayType:    input.PayType,
+		Rate:       input.Rate,
+		FromYear:   input.FromYear,
+		FromMonth:  input.FromMonth,
+	}
+	if err := services.SaveWageRate(db.DB, &rate); err != nil {
+		if errors.Is(err, models.ErrPayrollPeriodLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rate)
+}
+
+// DeleteWageRate 適用開始前の給与の履歴を取り消す（管理者専用）
+func DeleteWageRate(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	rateID, err := strconv.ParseUint(c.Param("rate_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid wage rate id"})
+		return
+	}
+
+	err = services.DeleteScheduledWageRate(db.DB, employeeID, uint(rateID), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wage rate not found"})
+		return
+	} else if errors.Is(err, services.ErrWageRateInEffect) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "wage rate deleted"})
+}
@@ -10,15 +10,23 @@ import (
 )
 
 type WorkRecordResponse struct {
-	ID           uint      `json:"id"`
-	EmployeeID   uint      `json:"employee_id"`
-	Date         time.Time `json:"date"`
-	ClockIn      time.Time `json:"clock_in"`
-	ClockOut     time.Time `json:"clock_out"`
-	BreakMinutes int64     `json:"break_minutes"`
-	WorkMinutes  int64     `json:"work_minutes"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uint      `json:"id"`
+	EmployeeID        uint      `json:"employee_id"`
+	Date              time.Time `json:"date"`
+	ClockIn           time.Time `json:"clock_in"`
+	ClockOut          time.Time `json:"clock_out"`
+	BreakMinutes      int64     `json:"break_minutes"`
+	WorkMinutes       int64     `json:"work_minutes"`
+	OvertimeMinutes   int64     `json:"overtime_minutes"`
+	LateNightMinutes  int64     `json:"late_night_minutes"`
+	HolidayMinutes    int64     `json:"holiday_minutes"`
+	LeaveType         string    `json:"leave_type,omitempty"`
+	LeaveMinutes      int64     `json:"leave_minutes"`
+	LateMinutes       int64     `json:"late_minutes"`
+	EarlyLeaveMinutes int64     `json:"early_leave_minutes"`
+	Unscheduled       bool      `json:"unscheduled"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 func GetWorkRecords(c *gin.Context) {
@@ -69,15 +77,23 @@ func GetWorkRecords(c *gin.Context) {
 	var response []WorkRecordResponse
 	for _, r := range records {
 		response = append(response, WorkRecordResponse{
-			ID:           r.ID,
-			EmployeeID:   r.EmployeeID,
-			Date:         r.Date,
-			ClockIn:      r.ClockIn,
-			ClockOut:     r.ClockOut,
-			BreakMinutes: r.BreakMinutes,
-			WorkMinutes:  r.WorkMinutes,
-			CreatedAt:    r.CreatedAt,
-			UpdatedAt:    r.UpdatedAt,
+			ID:                r.ID,
+			EmployeeID:        r.EmployeeID,
+			Date:              r.Date,
+			ClockIn:           r.ClockIn,
+			ClockOut:          r.ClockOut,
+			BreakMinutes:      r.BreakMinutes,
+			WorkMinutes:       r.WorkMinutes,
+			OvertimeMinutes:   r.OvertimeMinutes,
+			LateNightMinutes:  r.LateNightMinutes,
+			HolidayMinutes:    r.HolidayMinutes,
+			LeaveType:         r.LeaveType,
+			LeaveMinutes:      r.LeaveMinutes,
+			LateMinutes:       r.LateMinutes,
+			EarlyLeaveMinutes: r.EarlyLeaveMinutes,
+			Unscheduled:       r.Unscheduled,
+			CreatedAt:         r.CreatedAt,
+			UpdatedAt:         r.UpdatedAt,
 		})
 	}
 
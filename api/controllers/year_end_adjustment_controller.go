@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/helpers"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/services"
+	"gorm.io/gorm"
+)
+
+// YearEndDeclarationInput 年末調整の申告内容 (日付は "2006-01-02" 形式)
+type YearEndDeclarationInput struct {
+	LifeInsuranceNew     int                     `json:"life_insurance_new" binding:"min=0"`
+	LifeInsuranceOld     int                     `json:"life_insurance_old" binding:"min=0"`
+	CareMedicalInsurance int                     `json:"care_medical_insurance" binding:"min=0"`
+	PensionInsuranceNew  int                     `json:"pension_insurance_new" binding:"min=0"`
+	PensionInsuranceOld  int                     `json:"pension_insurance_old" binding:"min=0"`
+	EarthquakeInsurance  int                     `json:"earthquake_insurance" binding:"min=0"`
+	OldLongTermInsurance int                     `json:"old_long_term_insurance" binding:"min=0"`
+	OtherSocialInsurance int                     `json:"other_social_insurance" binding:"min=0"`
+	HasSpouse            bool                    `json:"has_spouse"`
+	SpouseName           string                  `json:"spouse_name"`
+	SpouseDateOfBirth    string                  `json:"spouse_date_of_birth"`
+	SpouseIncome         int                     `json:"spouse_income" binding:"min=0"`
+	HousingLoanCredit    int                     `json:"housing_loan_credit" binding:"min=0"`
+	Dependents           []YearEndDependentInput `json:"dependents" binding:"dive"`
+
+	// 前職分 (前の勤務先の源泉徴収票の支払金額・社会保険料等の金額・源泉徴収税額)
+	PreviousEmployerPay             int `json:"previous_employer_pay" binding:"min=0"`
+	PreviousEmployerSocialInsurance int `json:"previous_employer_social_insurance" binding:"min=0"`
+	PreviousEmployerWithheldTax     int `json:"previous_employer_withheld_tax" binding:"min=0"`
+}
+
+type YearEndDependentInput struct {
+	Name             string `json:"name" binding:"required"`
+	DateOfBirth      string `json:"date_of_birth" binding:"required"`
+	Income           int    `json:"income" binding:"min=0"`
+	CohabitingParent bool   `json:"cohabiting_parent"`
+}
+
+// yearEndAdjustmentError 年末調整の保存・計算のエラーを返す (12月の給与計算が締められていれば 409、年末調整の対象外であれば 422)
+func yearEndAdjustmentError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrPayrollPeriodLocked) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrYearEndAdjustmentNotEligible) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// GetYearEndAdjustment 従業員の年末調整の申告内容と計算結果を取得
+func GetYearEndAdjustment(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	adj, err := services.GetYearEndAdjustment(db.DB, employeeID, year)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "year-end adjustment not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, adj)
+}
+
+// SaveYearEndDeclaration 従業員の年末調整の申告内容を登録（管理者専用）
+func SaveYearEndDeclaration(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	var input YearEndDeclarationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adj := models.YearEndAdjustment{
+		EmployeeID:           employeeID,
+		Year:                 year,
+		LifeInsuranceNew:     input.LifeInsuranceNew,
+		LifeInsuranceOld:     input.LifeInsuranceOld,
+		CareMedicalInsurance: input.CareMedicalInsurance,
+		PensionInsuranceNew:  input.PensionInsuranceNew,
+		PensionInsuranceOld:  input.PensionInsuranceOld,
+		EarthquakeInsurance:  input.EarthquakeInsurance,
+		OldLongTermInsurance: input.OldLongTermInsurance,
+		OtherSocialInsurance: input.OtherSocialInsurance,
+		HasSpouse:            input.HasSpouse,
+		SpouseName:           input.SpouseName,
+		SpouseIncome:         input.SpouseIncome,
+		HousingLoanCredit:    input.HousingLoanCredit,
+		Dependents:           make([]models.YearEndDependent, 0, len(input.Dependents)),
+
+		PreviousEmployerPay:             input.PreviousEmployerPay,
+		PreviousEmployerSocialInsurance: input.PreviousEmployerSocialInsurance,
+		PreviousEmployerWithheldTax:     input.PreviousEmployerWithheldTax,
+	}
+
+	if input.SpouseDateOfBirth != "" {
+		dob, err := time.ParseInLocation("2006-01-02", input.SpouseDateOfBirth, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid spouse_date_of_birth format"})
+			return
+		}
+		adj.SpouseDateOfBirth = &dob
+	}
+
+	for _, d := range input.Dependents {
+		dob, err := time.ParseInLocation("2006-01-02", d.DateOfBirth, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date_of_birth format: " + d.Name})
+			return
+		}
+		adj.Dependents = append(adj.Dependents, models.YearEndDependent{
+			Name:             d.Name,
+			DateOfBirth:      dob,
+			Income:           d.Income,
+			CohabitingParent: d.CohabitingParent,
+		})
+	}
+
+	if err := services.SaveYearEndDeclaration(db.DB, &adj); err != nil {
+		yearEndAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adj)
+}
+
+// CalculateYearEndAdjustment 従業員の年税額と12月の給与で精算する過不足額を計算（管理者専用）
+func CalculateYearEndAdjustment(c *gin.Context) {
+	if !helpers.RequireAdmin(c) {
+		return
+	}
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	adj, err := services.CalculateYearEndAdjustment(db.DB, employeeID, year)
+	if err != nil {
+		yearEndAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adj)
+}
+
+// GetWithholdingSlip 従業員の源泉徴収票の記載内容を取得
+func GetWithholdingSlip(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	slip, err := services.BuildWithholdingSlip(db.DB, employeeID, year)
+	if err != nil {
+		withholdingSlipError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, slip)
+}
+
+// DownloadWithholdingSlip 従業員の源泉徴収票をPDFで取得
+func DownloadWithholdingSlip(c *gin.Context) {
+	employeeID, ok := helpers.EmployeeIDParam(c)
+	if !ok {
+		return
+	}
+	year, ok := helpers.QueryInt(c, "year")
+	if !ok {
+		return
+	}
+
+	body, err := services.RenderWithholdingSlipPDF(db.DB, employeeID, year)
+	if err != nil {
+		withholdingSlipError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.WithholdingSlipFileName(employeeID, year)))
+	c.Data(http.StatusOK, "application/pdf", body)
+}
+
+// withholdingSlipError 年末調整がない・計算されていない場合は 404 を返す
+func withholdingSlipError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, services.ErrYearEndAdjustmentNotCalculated) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
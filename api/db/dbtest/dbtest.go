@@ -0,0 +1,211 @@
+// Package dbtest PostgreSQLに接続せずに、発行されたSQLを記録し、指定した結果を返す gorm.DB をテスト用に提供する
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Query 発行されたSQLと引数
+type Query struct {
+	SQL  string
+	Args []any
+}
+
+// Result クエリに返す結果 (更新系のSQLには RowsAffected を返す)
+type Result struct {
+	Columns      []string
+	Rows         [][]any
+	RowsAffected int64
+}
+
+type responder struct {
+	match  func(Query) bool
+	result Result
+}
+
+// Recorder 発行されたSQLを記録し、登録した結果を返す
+type Recorder struct {
+	mu         sync.Mutex
+	queries    []Query
+	responders []responder
+}
+
+// Open 記録用のDBを作成する
+func Open(t testing.TB) (*gorm.DB, *Recorder) {
+	t.Helper()
+	rec := &Recorder{}
+	sqlDB := sql.OpenDB(connector{rec})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	return db, rec
+}
+
+// On SQLに contains を含むクエリに result を返す (先に登録したものを優先する)
+// 登録のないクエリは0行、更新系は0件を返す
+func (r *Recorder) On(contains string, result Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responders = append(r.responders, responder{func(q Query) bool { return strings.Contains(q.SQL, contains) }, result})
+}
+
+// OnQuery match が true を返すクエリに result を返す (引数によって結果を変える場合に使う)
+func (r *Recorder) OnQuery(match func(Query) bool, result Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responders = append(r.responders, responder{match, result})
+}
+
+// Queries 発行されたSQL
+func (r *Recorder) Queries() []Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Query(nil), r.queries...)
+}
+
+// Find SQLに contains を含む最初のクエリ
+func (r *Recorder) Find(contains string) (Query, bool) {
+	for _, q := range r.Queries() {
+		if strings.Contains(q.SQL, contains) {
+			return q, true
+		}
+	}
+	return Query{}, false
+}
+
+// Inserted INSERT 文の列名と値 (INSERT 以外は nil)
+func (q Query) Inserted() map[string]any {
+	m := insertColumns.FindStringSubmatch(q.SQL)
+	if m == nil {
+		return nil
+	}
+	values := make(map[string]any)
+	for i, col := range strings.Split(m[1], ",") {
+		if i < len(q.Args) {
+			values[strings.Trim(col, `" `)] = q.Args[i]
+		}
+	}
+	return values
+}
+
+// Updated UPDATE 文で設定した列名と値 (UPDATE 以外は nil)
+func (q Query) Updated() map[string]any {
+	if !strings.HasPrefix(q.SQL, "UPDATE ") {
+		return nil
+	}
+	values := make(map[string]any)
+	for _, m := range updateColumns.FindAllStringSubmatch(q.SQL, -1) {
+		if i, err := strconv.Atoi(m[2]); err == nil && i <= len(q.Args) {
+			values[m[1]] = q.Args[i-1]
+		}
+	}
+	return values
+}
+
+var (
+	insertColumns = regexp.MustCompile(`^INSERT INTO "\w+" \(([^)]*)\)`)
+	updateColumns = regexp.MustCompile(`"(\w+)"=\$(\d+)`)
+)
+
+func (r *Recorder) record(query string, args []driver.NamedValue) Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	values := make([]any, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	q := Query{SQL: query, Args: values}
+	r.queries = append(r.queries, q)
+	for _, res := range r.responders {
+		if res.match(q) {
+			return res.result
+		}
+	}
+	return Result{}
+}
+
+type connector struct{ rec *Recorder }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return conn{c.rec}, nil }
+func (c connector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("dbtest: use sql.OpenDB with the connector")
+}
+
+type conn struct{ rec *Recorder }
+
+func (c conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("dbtest: prepared statements are not supported")
+}
+func (c conn) Close() error              { return nil }
+func (c conn) Begin() (driver.Tx, error) { return tx{}, nil }
+
+func (c conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) { return tx{}, nil }
+
+func (c conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res := c.rec.record(query, args)
+	return &rows{columns: res.Columns, values: res.Rows}, nil
+}
+
+func (c conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res := c.rec.record(query, args)
+	return driver.RowsAffected(res.RowsAffected), nil
+}
+
+// CheckNamedValue 引数をそのまま記録する (ポインタなどの変換は行わない)
+func (c conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if v, ok := nv.Value.(driver.Valuer); ok {
+		value, err := v.Value()
+		if err != nil {
+			return err
+		}
+		nv.Value = value
+	}
+	return nil
+}
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type rows struct {
+	columns []string
+	values  [][]any
+	next    int
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	for i, v := range r.values[r.next] {
+		dest[i] = v
+	}
+	r.next++
+	return nil
+}
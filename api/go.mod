@@ -10,6 +10,7 @@ require (
 	github.com/line/line-bot-sdk-go/v8 v8.12.1
 	github.com/xuri/excelize/v2 v2.9.0
 	golang.org/x/crypto v0.36.0
+	golang.org/x/text v0.23.0
 	gorm.io/driver/postgres v1.5.11
 	gorm.io/gorm v1.25.12
 )
@@ -49,7 +50,6 @@ require (
 	golang.org/x/net v0.37.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
-	golang.org/x/text v0.23.0 // indirect
 	google.golang.org/protobuf v1.36.5 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmployeeIDParam パスの従業員IDを取得し、ログイン中の会社に所属しているかを確認する
+// 確認できない場合はエラーのレスポンスを返し、false を返す
+func EmployeeIDParam(c *gin.Context) (uint, bool) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee id"})
+		return 0, false
+	}
+
+	companyID, err := GetCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	if err := CheckEmployeeAccess(uint(employeeID), companyID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	return uint(employeeID), true
+}
+
+// QueryInt 必須のクエリパラメータを整数として取得
+func QueryInt(c *gin.Context, name string) (int, bool) {
+	param := c.Query(name)
+	if param == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " parameter is required"})
+		return 0, false
+	}
+	v, err := strconv.Atoi(param)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return v, true
+}
+
+// QueryMonth 必須のクエリパラメータ month を1〜12の整数として取得
+func QueryMonth(c *gin.Context) (int, bool) {
+	month, ok := QueryInt(c, "month")
+	if !ok {
+		return 0, false
+	}
+	if month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid month"})
+		return 0, false
+	}
+	return month, true
+}
+
+// RequireAdmin 管理者以外はエラーのレスポンスを返し、false を返す
+func RequireAdmin(c *gin.Context) bool {
+	isAdmin, err := GetIsAdmin(c)
+	if err != nil || !isAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		return false
+	}
+	return true
+}
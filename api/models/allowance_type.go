@@ -1,14 +1,45 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// AllowanceType 手当の種類
+// 所得税・社会保険・割増賃金の算定でそれぞれ手当を含めるかを種類ごとに設定する
 type AllowanceType struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	CompanyID      uint      `json:"company_id"`
-	Name           string    `gorm:"not null" json:"name"`
-	Type           string    `gorm:"not null;check:type IN ('commission','fixed')" json:"type"`
-	Description    string    `json:"description"`
-	CommissionRate *float64  `json:"commission_rate,omitempty"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uint     `gorm:"primaryKey" json:"id"`
+	CompanyID      uint     `json:"company_id"`
+	Name           string   `gorm:"not null" json:"name"`
+	Type           string   `gorm:"not null;check:type IN ('commission','fixed')" json:"type"`
+	Description    string   `json:"description"`
+	CommissionRate *float64 `json:"commission_rate,omitempty"`
+
+	NonTaxable                      bool `gorm:"not null;default:false" json:"non_taxable"`                        // 所得税の非課税 (通勤手当など)
+	NonTaxableLimit                 int  `gorm:"not null;default:0" json:"non_taxable_limit"`                      // 非課税とする月額の上限 (0の場合は全額非課税)
+	ExcludeFromStandardRemuneration bool `gorm:"not null;default:false" json:"exclude_from_standard_remuneration"` // 社会保険の報酬に含めない (慶弔見舞金などの恩恵的な給付)
+	IncludeInOvertimeBase           bool `gorm:"not null;default:false" json:"include_in_overtime_base"`           // 割増賃金の算定基礎に含める (役職手当など。通勤・住宅・家族手当などは含めない)
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (at *AllowanceType) BeforeCreate(tx *gorm.DB) error {
+	return at.validate()
+}
+
+func (at *AllowanceType) BeforeUpdate(tx *gorm.DB) error {
+	return at.validate()
+}
+
+func (at *AllowanceType) validate() error {
+	if at.NonTaxableLimit < 0 {
+		return errors.New("non_taxable_limit must not be negative")
+	}
+	if !at.NonTaxable && at.NonTaxableLimit > 0 {
+		return errors.New("non_taxable_limit requires non_taxable")
+	}
+	return nil
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+)
+
+const (
+	AccountTypeOrdinary = "ordinary" // 普通預金
+	AccountTypeChecking = "checking" // 当座預金
+	AccountTypeSavings  = "savings"  // 貯蓄預金
+)
+
+var (
+	bankCodePattern      = regexp.MustCompile(`^[0-9]{4}$`)
+	branchCodePattern    = regexp.MustCompile(`^[0-9]{3}$`)
+	accountNumberPattern = regexp.MustCompile(`^[0-9]{1,7}$`)
+)
+
+// validateBankAccount 金融機関コード・支店コード・預金種目・口座番号の形式を確認する (未設定の項目は確認しない)
+func validateBankAccount(bankCode, branchCode, accountType, accountNumber string) error {
+	if bankCode != "" && !bankCodePattern.MatchString(bankCode) {
+		return errors.New("bank code must be 4 digits")
+	}
+	if branchCode != "" && !branchCodePattern.MatchString(branchCode) {
+		return errors.New("branch code must be 3 digits")
+	}
+	switch accountType {
+	case "", AccountTypeOrdinary, AccountTypeChecking, AccountTypeSavings:
+	default:
+		return errors.New("invalid account type: " + accountType)
+	}
+	if accountNumber != "" && !accountNumberPattern.MatchString(accountNumber) {
+		return errors.New("account number must be up to 7 digits")
+	}
+	return nil
+}
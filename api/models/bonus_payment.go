@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BonusPayment 従業員に支給する賞与
+type BonusPayment struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID uint      `gorm:"not null;index" json:"employee_id"`
+	PaidOn     time.Time `gorm:"type:date;not null" json:"paid_on"` // 支給日 (保険料・税額の計算に用いる年月)
+	Amount     int       `gorm:"not null" json:"amount"`            // 賞与の総支給額
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (b *BonusPayment) BeforeCreate(tx *gorm.DB) error {
+	return b.validate()
+}
+
+func (b *BonusPayment) BeforeUpdate(tx *gorm.DB) error {
+	return b.validate()
+}
+
+func (b *BonusPayment) validate() error {
+	if b.Amount < 0 {
+		return errors.New("amount must not be negative")
+	}
+	if b.PaidOn.IsZero() {
+		return errors.New("paid_on is required")
+	}
+	return nil
+}
@@ -1,12 +1,55 @@
 package models
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 賃金の1円未満の端数処理
+const (
+	WageRoundingRound = "round" // 四捨五入
+	WageRoundingFloor = "floor" // 切り捨て
+	WageRoundingCeil  = "ceil"  // 切り上げ
+)
 
 type Company struct {
-	ID           uint       `gorm:"primaryKey" json:"id"`
-	Name         string     `json:"name"`
-	PrefectureID uint       `json:"prefecture_id"`
-	Prefecture   Prefecture `json:"prefecture" gorm:"foreignKey:PrefectureID"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID                             uint       `gorm:"primaryKey" json:"id"`
+	Name                           string     `json:"name"`
+	PrefectureID                   uint       `json:"prefecture_id"`
+	Prefecture                     Prefecture `json:"prefecture" gorm:"foreignKey:PrefectureID"`
+	BusinessDayStartHour           int        `gorm:"not null;default:0" json:"business_day_start_hour"`                   // 日付の切り替え時刻 (5なら翌4:59までの勤務を前日扱い)
+	MaxShiftHours                  int        `gorm:"not null;default:24" json:"max_shift_hours"`                          // 1回の勤務として扱う最大時間
+	LegalDailyMinutes              int        `gorm:"not null;default:480" json:"legal_daily_minutes"`                     // 法定労働時間 (1日)
+	LegalWeeklyMinutes             int        `gorm:"not null;default:2400" json:"legal_weekly_minutes"`                   // 法定労働時間 (1週)
+	WeekStartWeekday               int        `gorm:"not null;default:0" json:"week_start_weekday"`                        // 週の起算曜日 (0:日曜)
+	LegalHolidayWeekday            int        `gorm:"not null;default:0" json:"legal_holiday_weekday"`                     // 法定休日の曜日 (0:日曜)
+	RestWeekdays                   string     `gorm:"type:varchar(20);not null;default:'0,6'" json:"rest_weekdays"`        // 所定休日の曜日 (カンマ区切り、法定休日の曜日を含む)
+	WorkOnNationalHolidays         bool       `gorm:"not null;default:false" json:"work_on_national_holidays"`             // 国民の祝日・休日を出勤日とする
+	LateNightStartHour             int        `gorm:"not null;default:22" json:"late_night_start_hour"`                    // 深夜労働の開始時刻
+	LateNightEndHour               int        `gorm:"not null;default:5" json:"late_night_end_hour"`                       // 深夜労働の終了時刻
+	ScheduledMonthlyHours          float64    `gorm:"not null;default:160" json:"scheduled_monthly_hours"`                 // 月平均所定労働時間 (時間単価の算出に使用)
+	OvertimePremiumRate            float64    `gorm:"not null;default:0.25" json:"overtime_premium_rate"`                  // 時間外労働の割増率
+	ExcessOvertimePremiumRate      float64    `gorm:"not null;default:0.5" json:"excess_overtime_premium_rate"`            // 月60時間超の時間外労働の割増率
+	ExcessOvertimeThresholdMinutes int        `gorm:"not null;default:3600" json:"excess_overtime_threshold_minutes"`      // 割増率が上がる月の時間外労働時間
+	LateNightPremiumRate           float64    `gorm:"not null;default:0.25" json:"late_night_premium_rate"`                // 深夜労働の割増率
+	HolidayPremiumRate             float64    `gorm:"not null;default:0.35" json:"holiday_premium_rate"`                   // 法定休日労働の割増率
+	WorkMinutesRoundingUnit        int        `gorm:"not null;default:1" json:"work_minutes_rounding_unit"`                // 月の合計労働時間の端数処理の単位 (分)。60なら30分未満切り捨て・30分以上切り上げ
+	WageRoundingMode               string     `gorm:"type:varchar(10);not null;default:'round'" json:"wage_rounding_mode"` // 時給・日給・割増賃金の1円未満の端数処理 (round/floor/ceil)
+	IndustryClass                  string     `gorm:"type:varchar(20);not null;default:'general'" json:"industry_class"`   // 雇用保険の事業の種類
+	CreatedAt                      time.Time  `json:"created_at"`
+	UpdatedAt                      time.Time  `json:"updated_at"`
+}
+
+// IsRestWeekday 指定した曜日が所定休日の曜日 (法定休日の曜日を含む) かどうか
+func (c Company) IsRestWeekday(w time.Weekday) bool {
+	if int(w) == c.LegalHolidayWeekday {
+		return true
+	}
+	for _, s := range strings.Split(c.RestWeekdays, ",") {
+		if d, err := strconv.Atoi(strings.TrimSpace(s)); err == nil && d == int(w) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CompanyHolidayKind 会社カレンダーで指定する日の種類
+type CompanyHolidayKind string
+
+const (
+	CompanyHolidayClosed  CompanyHolidayKind = "closed"        // 会社休日 (年末年始・夏季休業など)
+	CompanyHolidayLegal   CompanyHolidayKind = "legal_holiday" // 法定休日として指定する日 (その週は曜日による法定休日に代えて扱う)
+	CompanyHolidayWorkday CompanyHolidayKind = "workday"       // 出勤日 (祝日や所定休日の曜日を出勤日とする)
+)
+
+// CompanyHoliday 会社ごとに国民の祝日・所定休日の曜日に加えて指定する休日・出勤日
+type CompanyHoliday struct {
+	ID        uint               `gorm:"primaryKey" json:"id"`
+	CompanyID uint               `gorm:"not null;uniqueIndex:idx_company_holiday_date" json:"company_id"`
+	Date      time.Time          `gorm:"type:date;not null;uniqueIndex:idx_company_holiday_date" json:"date"`
+	Kind      CompanyHolidayKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Name      string             `json:"name"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+func (h *CompanyHoliday) BeforeCreate(tx *gorm.DB) error {
+	return h.validate()
+}
+
+func (h *CompanyHoliday) BeforeUpdate(tx *gorm.DB) error {
+	return h.validate()
+}
+
+func (h *CompanyHoliday) validate() error {
+	switch h.Kind {
+	case CompanyHolidayClosed, CompanyHolidayLegal, CompanyHolidayWorkday:
+		return nil
+	default:
+		return errors.New("invalid kind: " + string(h.Kind))
+	}
+}
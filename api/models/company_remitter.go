@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var requesterCodePattern = regexp.MustCompile(`^[0-9]{10}$`)
+
+// CompanyRemitter 給与振込の振込元 (委託者) の情報
+// 名称は全銀フォーマットに出力するため、カナで登録する
+type CompanyRemitter struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	CompanyID     uint      `gorm:"not null;uniqueIndex" json:"company_id"`
+	RequesterCode string    `gorm:"type:varchar(10);not null" json:"requester_code"` // 委託者コード (取引銀行から付与される10桁)
+	RequesterName string    `gorm:"not null" json:"requester_name"`                  // 委託者名 (カナ)
+	BankCode      string    `gorm:"type:varchar(4);not null" json:"bank_code"`
+	BankName      string    `json:"bank_name"` // 仕向銀行名 (カナ)
+	BranchCode    string    `gorm:"type:varchar(3);not null" json:"branch_code"`
+	BranchName    string    `json:"branch_name"` // 仕向支店名 (カナ)
+	AccountType   string    `gorm:"type:varchar(10);not null" json:"account_type"`
+	AccountNumber string    `gorm:"type:varchar(7);not null" json:"account_number"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (r *CompanyRemitter) BeforeCreate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *CompanyRemitter) BeforeUpdate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *CompanyRemitter) validate() error {
+	if !requesterCodePattern.MatchString(r.RequesterCode) {
+		return errors.New("requester code must be 10 digits")
+	}
+	if r.RequesterName == "" {
+		return errors.New("requester name is required")
+	}
+	if r.BankCode == "" || r.BranchCode == "" || r.AccountType == "" || r.AccountNumber == "" {
+		return errors.New("bank code, branch code, account type and account number are required")
+	}
+	return validateBankAccount(r.BankCode, r.BranchCode, r.AccountType, r.AccountNumber)
+}
@@ -1,17 +1,76 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Employee struct {
-	ID            uint                `gorm:"primaryKey" json:"id"`
-	CompanyID     uint                `json:"company_id"`
-	Company       Company             `json:"company" gorm:"foreignKey:CompanyID"`
-	Name          string              `json:"name"`
-	LineUserID    *string             `json:"line_user_id,omitempty"` // nilと""を区別したいためポインタ型にしてNULL対応(未連携ユーザーなど)
-	MonthlySalary int                 `json:"monthly_salary"`
-	DateOfBirth   time.Time           `json:"date_of_birth"`
-	CreatedAt     time.Time           `json:"created_at"`
-	UpdatedAt     time.Time           `json:"updated_at"`
-	TimeClocks    []TimeClock         `json:"time_clocks,omitempty" gorm:"foreignKey:EmployeeID"`
-	Allowances    []EmployeeAllowance `json:"allowances,omitempty" gorm:"foreignKey:EmployeeID"`
+	ID                  uint                 `gorm:"primaryKey" json:"id"`
+	CompanyID           uint                 `json:"company_id"`
+	Company             Company              `json:"company" gorm:"foreignKey:CompanyID"`
+	Name                string               `json:"name"`
+	LineUserID          *string              `json:"line_user_id,omitempty"`                                      // nilと""を区別したいためポインタ型にしてNULL対応(未連携ユーザーなど)
+	MonthlySalary       int                  `json:"monthly_salary"`                                              // 給与の履歴 (WageRate) がない月に適用する月給
+	PayType             string               `gorm:"type:varchar(10);not null;default:'monthly'" json:"pay_type"` // 支払形態 (monthly:月給, daily:日給, hourly:時給)
+	DateOfBirth         time.Time            `json:"date_of_birth"`
+	HireDate            *time.Time           `gorm:"type:date" json:"hire_date,omitempty"`                                    // 入社日 (年次有給休暇の付与日の起算日)
+	WeeklyWorkDays      int                  `gorm:"not null;default:5" json:"weekly_work_days"`                              // 週の所定労働日数
+	WeeklyWorkMinutes   int                  `gorm:"not null;default:2400" json:"weekly_work_minutes"`                        // 週の所定労働時間 (分)
+	WorkingTimeSystem   string               `gorm:"type:varchar(10);not null;default:'standard'" json:"working_time_system"` // 労働時間制 (standard:通常, flextime:フレックスタイム制)
+	Dependents          int                  `gorm:"not null;default:0" json:"dependents"`                                    // 源泉徴収税額の計算に用いる扶養親族等の数
+	TaxTable            string               `gorm:"type:varchar(10);not null;default:'kou'" json:"tax_table"`                // 源泉徴収税額表の適用欄 (kou:甲欄, otsu:乙欄)
+	BankCode            string               `gorm:"type:varchar(4)" json:"bank_code"`                                        // 給与の振込先の金融機関コード
+	BranchCode          string               `gorm:"type:varchar(3)" json:"branch_code"`
+	AccountType         string               `gorm:"type:varchar(10)" json:"account_type"` // ordinary:普通, checking:当座, savings:貯蓄
+	AccountNumber       string               `gorm:"type:varchar(7)" json:"account_number"`
+	AccountHolderKana   string               `json:"account_holder_kana"` // 受取人名 (カナ)
+	CreatedAt           time.Time            `json:"created_at"`
+	UpdatedAt           time.Time            `json:"updated_at"`
+	TimeClocks          []TimeClock          `json:"time_clocks,omitempty" gorm:"foreignKey:EmployeeID"`
+	Allowances          []EmployeeAllowance  `json:"allowances,omitempty" gorm:"foreignKey:EmployeeID"`
+	RecurringAllowances []RecurringAllowance `json:"recurring_allowances,omitempty" gorm:"foreignKey:EmployeeID"`
+}
+
+func (e *Employee) BeforeCreate(tx *gorm.DB) error {
+	return e.validate()
+}
+
+func (e *Employee) BeforeUpdate(tx *gorm.DB) error {
+	return e.validate()
+}
+
+func (e *Employee) validate() error {
+	switch e.PayType {
+	case "", PayTypeMonthly, PayTypeDaily, PayTypeHourly:
+	default:
+		return errors.New("invalid pay type: " + e.PayType)
+	}
+
+	switch e.TaxTable {
+	case "", TaxTableKou, TaxTableOtsu:
+	default:
+		return errors.New("invalid tax table: " + e.TaxTable)
+	}
+
+	if e.Dependents < 0 {
+		return errors.New("dependents must not be negative")
+	}
+
+	if e.WeeklyWorkDays < 0 || e.WeeklyWorkDays > 7 {
+		return errors.New("invalid weekly_work_days")
+	}
+	if e.WeeklyWorkMinutes < 0 {
+		return errors.New("weekly_work_minutes must not be negative")
+	}
+
+	switch e.WorkingTimeSystem {
+	case "", WorkingTimeStandard, WorkingTimeFlextime:
+	default:
+		return errors.New("invalid working time system: " + e.WorkingTimeSystem)
+	}
+
+	return validateBankAccount(e.BankCode, e.BranchCode, e.AccountType, e.AccountNumber)
 }
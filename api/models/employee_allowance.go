@@ -1,7 +1,13 @@
 package models
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// EmployeeAllowance 指定した年・月だけ支給する手当
+// 同じ種類の継続手当 (RecurringAllowance) がある月は、継続手当に代えてこちらを支給する
 type EmployeeAllowance struct {
 	ID              uint          `gorm:"primaryKey" json:"id"`
 	EmployeeID      uint          `json:"employee_id"`
@@ -14,3 +20,27 @@ type EmployeeAllowance struct {
 	UpdatedAt       time.Time     `json:"updated_at"`
 	AllowanceType   AllowanceType `json:"allowance_type" gorm:"foreignKey:AllowanceTypeID"`
 }
+
+// BeforeSave 変更前・変更後のいずれかの月の給与計算が締められていれば保存しない
+func (ea *EmployeeAllowance) BeforeSave(tx *gorm.DB) error {
+	if err := ea.checkStoredPeriodOpen(tx); err != nil {
+		return err
+	}
+	return checkPayrollPeriodOpen(tx, ea.EmployeeID, ea.Year, ea.Month)
+}
+
+func (ea *EmployeeAllowance) BeforeDelete(tx *gorm.DB) error {
+	return ea.checkStoredPeriodOpen(tx)
+}
+
+// checkStoredPeriodOpen 保存済みの手当が締められた月のものでないかを確認する
+func (ea *EmployeeAllowance) checkStoredPeriodOpen(tx *gorm.DB) error {
+	if ea.ID == 0 {
+		return nil
+	}
+	var stored EmployeeAllowance
+	if err := tx.Session(&gorm.Session{NewDB: true}).First(&stored, ea.ID).Error; err != nil {
+		return nil
+	}
+	return checkPayrollPeriodOpen(tx, stored.EmployeeID, stored.Year, stored.Month)
+}
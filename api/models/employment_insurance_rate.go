@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// 雇用保険の事業の種類
+const (
+	IndustryGeneral      = "general"      // 一般の事業
+	IndustryAgriculture  = "agriculture"  // 農林水産・清酒製造の事業
+	IndustryConstruction = "construction" // 建設の事業
+)
+
+// EmploymentInsuranceRate 雇用保険料率 (年度ごと・事業の種類ごと)
+type EmploymentInsuranceRate struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	IndustryClass string    `gorm:"type:varchar(20);not null" json:"industry_class"`
+	EmployeeRate  float64   `json:"employee_rate"` // 労働者負担の料率
+	EmployerRate  float64   `json:"employer_rate"` // 事業主負担の料率
+	FromYear      int       `json:"from_year"`
+	FromMonth     int       `json:"from_month"`
+	ToYear        int       `json:"to_year"`
+	ToMonth       int       `json:"to_month"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
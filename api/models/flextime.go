@@ -0,0 +1,116 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 労働時間制
+const (
+	WorkingTimeStandard = "standard" // 通常の労働時間制 (1日・1週の法定労働時間で時間外労働を判定)
+	WorkingTimeFlextime = "flextime" // フレックスタイム制 (清算期間の総労働時間で時間外労働を判定)
+)
+
+// FlextimeSetting フレックスタイム制の労使協定で定める従業員ごとの設定
+type FlextimeSetting struct {
+	ID                   uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID           uint      `gorm:"not null;uniqueIndex" json:"employee_id"`
+	StartYear            int       `gorm:"not null" json:"start_year"` // 最初の清算期間の起算月
+	StartMonth           int       `gorm:"not null" json:"start_month"`
+	SettlementMonths     int       `gorm:"not null;default:1" json:"settlement_months"`        // 清算期間の月数 (1〜3)
+	StandardDailyMinutes int       `gorm:"not null;default:480" json:"standard_daily_minutes"` // 標準となる1日の労働時間 (所定労働日数に掛けて総労働時間とする)
+	CoreStartTime        string    `gorm:"type:varchar(5)" json:"core_start_time"`             // コアタイムの開始時刻 ("10:00" 形式、空ならコアタイムなし)
+	CoreEndTime          string    `gorm:"type:varchar(5)" json:"core_end_time"`
+	MaxCarryOverMinutes  int       `gorm:"not null;default:0" json:"max_carry_over_minutes"` // 不足時間を次の清算期間に繰り越せる上限 (0なら繰り越さない)
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// FlextimeCarryOver 清算期間の最後の月の給与計算を締めた時点で確定した、次の清算期間に繰り越す不足時間
+type FlextimeCarryOver struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID uint      `gorm:"not null;uniqueIndex:idx_flextime_carry_over" json:"employee_id"`
+	StartYear  int       `gorm:"not null;uniqueIndex:idx_flextime_carry_over" json:"start_year"` // 繰り越し元の清算期間の最初の年・月
+	StartMonth int       `gorm:"not null;uniqueIndex:idx_flextime_carry_over" json:"start_month"`
+	EndYear    int       `gorm:"not null" json:"end_year"` // 繰り越し元の清算期間の最後の年・月
+	EndMonth   int       `gorm:"not null" json:"end_month"`
+	Minutes    int64     `gorm:"not null" json:"minutes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (s *FlextimeSetting) BeforeCreate(tx *gorm.DB) error {
+	return s.validate()
+}
+
+func (s *FlextimeSetting) BeforeUpdate(tx *gorm.DB) error {
+	return s.validate()
+}
+
+func (s *FlextimeSetting) validate() error {
+	if s.StartMonth < 1 || s.StartMonth > 12 {
+		return errors.New("invalid start_month")
+	}
+	if s.SettlementMonths < 1 || s.SettlementMonths > 3 {
+		return errors.New("settlement_months must be between 1 and 3")
+	}
+	if s.StandardDailyMinutes <= 0 {
+		return errors.New("standard_daily_minutes must be positive")
+	}
+	if s.MaxCarryOverMinutes < 0 {
+		return errors.New("max_carry_over_minutes must not be negative")
+	}
+
+	if s.CoreStartTime == "" && s.CoreEndTime == "" {
+		return nil
+	}
+	start, err := parseClockTime(s.CoreStartTime)
+	if err != nil {
+		return errors.New("invalid core_start_time: " + s.CoreStartTime)
+	}
+	end, err := parseClockTime(s.CoreEndTime)
+	if err != nil {
+		return errors.New("invalid core_end_time: " + s.CoreEndTime)
+	}
+	if end <= start {
+		return errors.New("core_end_time must be after core_start_time")
+	}
+	return nil
+}
+
+// startKey 最初の清算期間の起算月 (年×12+月-1)
+func (s *FlextimeSetting) startKey() int {
+	return s.StartYear*12 + s.StartMonth - 1
+}
+
+// Covers 指定日がフレックスタイム制の適用期間 (最初の清算期間の起算月以降) かどうか
+func (s *FlextimeSetting) Covers(date time.Time) bool {
+	d := date.In(time.Local)
+	return d.Year()*12+int(d.Month())-1 >= s.startKey()
+}
+
+// PeriodStart 指定した年・月を含む清算期間の最初の年・月 (適用期間より前の月は ok が false)
+func (s *FlextimeSetting) PeriodStart(year, month int) (int, int, bool) {
+	key := year*12 + month - 1
+	if key < s.startKey() {
+		return 0, 0, false
+	}
+	start := key - (key-s.startKey())%s.SettlementMonths
+	return start / 12, start%12 + 1, true
+}
+
+// CoreSpan 勤務日のコアタイムの開始・終了時刻 (コアタイムがなければ ok が false)
+func (s *FlextimeSetting) CoreSpan(date time.Time) (time.Time, time.Time, bool) {
+	if s.CoreStartTime == "" || s.CoreEndTime == "" {
+		return time.Time{}, time.Time{}, false
+	}
+	start, err1 := parseClockTime(s.CoreStartTime)
+	end, err2 := parseClockTime(s.CoreEndTime)
+	if err1 != nil || err2 != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	return day.Add(start), day.Add(end), true
+}
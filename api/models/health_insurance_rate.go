@@ -13,6 +13,8 @@ type HealthInsuranceRate struct {
 	HealthHalfNonCare   float64
 	HealthTotalWithCare float64
 	HealthHalfWithCare  float64
+	HealthRateNoCare    float64 // 介護保険第2号被保険者に該当しない場合の保険料率 (%)
+	HealthRateWithCare  float64 // 介護保険第2号被保険者に該当する場合の保険料率 (%)
 	FromYear            int
 	FromMonth           int
 	ToYear              int
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LeaveType string
+
+const (
+	LeavePaidFullDay LeaveType = "paid_full_day" // 年次有給休暇 (1日)
+	LeavePaidHalfDay LeaveType = "paid_half_day" // 年次有給休暇 (半日)
+	LeavePaidHourly  LeaveType = "paid_hourly"   // 年次有給休暇 (時間単位)
+	LeaveSpecial     LeaveType = "special"       // 特別休暇 (慶弔休暇など。有給休暇の残日数は減らさない)
+)
+
+// LeaveRequest 休暇の申請
+// 承認すると有給休暇の残日数から差し引き、休暇の勤務記録を作成する
+type LeaveRequest struct {
+	ID           uint          `gorm:"primaryKey" json:"id"`
+	EmployeeID   uint          `gorm:"not null;index" json:"employee_id"`
+	Type         LeaveType     `gorm:"type:varchar(20);not null" json:"type"`
+	Date         time.Time     `gorm:"type:date;not null" json:"date"`
+	Minutes      int           `gorm:"not null;default:0" json:"minutes"` // 時間単位の場合の取得時間 (分)
+	Status       RequestStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	Reason       string        `gorm:"type:text" json:"reason"`
+	ReviewedBy   *uint         `json:"reviewed_by,omitempty"`
+	Reviewer     *Account      `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
+	ReviewedAt   *time.Time    `json:"reviewed_at,omitempty"`
+	WorkRecordID *uint         `json:"work_record_id,omitempty"` // 承認により作成した休暇の勤務記録
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+func (r *LeaveRequest) BeforeCreate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *LeaveRequest) BeforeUpdate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *LeaveRequest) validate() error {
+	if !validStatuses[r.Status] {
+		return errors.New("invalid request status: " + string(r.Status))
+	}
+
+	switch r.Type {
+	case LeavePaidHourly:
+		if r.Minutes <= 0 || r.Minutes%60 != 0 {
+			return errors.New("hourly leave must be specified in whole hours")
+		}
+	case LeavePaidFullDay, LeavePaidHalfDay, LeaveSpecial:
+		if r.Minutes != 0 {
+			return errors.New("minutes can only be specified for hourly leave")
+		}
+	default:
+		return errors.New("invalid leave type: " + string(r.Type))
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationJobType string
+
+const (
+	ClockOutReminder NotificationJobType = "clock_out_reminder"
+)
+
+type NotificationJobStatus string
+
+const (
+	JobPending    NotificationJobStatus = "pending"
+	JobProcessing NotificationJobStatus = "processing"
+	JobDone       NotificationJobStatus = "done"
+	JobFailed     NotificationJobStatus = "failed"
+)
+
+// NotificationJob LINE通知などの遅延実行ジョブ (サーバー再起動後も実行されるようDBに永続化する)
+type NotificationJob struct {
+	ID          uint                  `gorm:"primaryKey" json:"id"`
+	EmployeeID  uint                  `gorm:"not null;index" json:"employee_id"`
+	Type        NotificationJobType   `gorm:"type:varchar(30);not null" json:"type"`
+	ClockInAt   time.Time             `gorm:"not null" json:"clock_in_at"`
+	RunAt       time.Time             `gorm:"not null;index" json:"run_at"`
+	Status      NotificationJobStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Attempts    int                   `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int                   `gorm:"not null;default:5" json:"max_attempts"`
+	LastError   string                `gorm:"type:text" json:"last_error,omitempty"`
+	LockedAt    *time.Time            `json:"locked_at,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+func (j *NotificationJob) BeforeCreate(tx *gorm.DB) error {
+	return j.validate()
+}
+
+func (j *NotificationJob) BeforeUpdate(tx *gorm.DB) error {
+	return j.validate()
+}
+
+func (j *NotificationJob) validate() error {
+	switch j.Type {
+	case ClockOutReminder:
+	default:
+		return errors.New("invalid notification job type: " + string(j.Type))
+	}
+
+	switch j.Status {
+	case JobPending, JobProcessing, JobDone, JobFailed:
+	default:
+		return errors.New("invalid notification job status: " + string(j.Status))
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PaidLeaveGrant 年次有給休暇の付与
+// 付与日から2年で時効となり、取得した日数は付与日の古いものから消化する
+type PaidLeaveGrant struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID uint      `gorm:"not null;uniqueIndex:idx_paid_leave_grant" json:"employee_id"`
+	GrantDate  time.Time `gorm:"type:date;not null;uniqueIndex:idx_paid_leave_grant" json:"grant_date"`
+	ExpiresOn  time.Time `gorm:"type:date;not null" json:"expires_on"` // 取得できる最後の日
+	Days       float64   `gorm:"not null" json:"days"`
+	UsedDays   float64   `gorm:"not null;default:0" json:"used_days"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Remaining 残日数
+func (g *PaidLeaveGrant) Remaining() float64 {
+	return g.Days - g.UsedDays
+}
+
+func (g *PaidLeaveGrant) BeforeSave(tx *gorm.DB) error {
+	if g.Days < 0 || g.UsedDays < 0 {
+		return errors.New("days must not be negative")
+	}
+	if g.UsedDays > g.Days {
+		return errors.New("used days exceed granted days")
+	}
+	if g.ExpiresOn.Before(g.GrantDate) {
+		return errors.New("expires_on must not be before grant_date")
+	}
+	return nil
+}
+
+// PaidLeaveUsage 年次有給休暇の取得
+// 1日の取得が複数の付与にまたがる場合は、付与ごとに分けて記録する (時間単位の取得は Minutes も分けて記録する)
+type PaidLeaveUsage struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID uint      `gorm:"not null;index" json:"employee_id"`
+	GrantID    uint      `gorm:"not null;index" json:"grant_id"`
+	Date       time.Time `gorm:"type:date;not null" json:"date"`
+	Days       float64   `gorm:"not null" json:"days"`                        // 1 (全日)、0.5 (半日) または時間単位の取得時間を日数に換算した値
+	Minutes    int       `gorm:"not null;default:0" json:"minutes,omitempty"` // 時間単位で取得した時間 (分)
+	CreatedAt  time.Time `json:"created_at"`
+}
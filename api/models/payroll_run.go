@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PayrollRunStatus string
+
+const (
+	PayrollRunOpen   PayrollRunStatus = "open"
+	PayrollRunLocked PayrollRunStatus = "locked"
+)
+
+// ErrPayrollPeriodLocked 給与計算が締められた月の勤務記録・手当は変更できない
+var ErrPayrollPeriodLocked = errors.New("payroll period is locked")
+
+// PayrollRun 会社・月ごとの給与計算 (締めると給与明細が確定し、対象月の勤務記録・手当を変更できなくなる)
+type PayrollRun struct {
+	ID                uint             `gorm:"primaryKey" json:"id"`
+	CompanyID         uint             `gorm:"not null;uniqueIndex:idx_payroll_run" json:"company_id"`
+	Year              int              `gorm:"not null;uniqueIndex:idx_payroll_run" json:"year"`
+	Month             int              `gorm:"not null;uniqueIndex:idx_payroll_run" json:"month"`
+	Status            PayrollRunStatus `gorm:"type:varchar(20);not null;default:'open'" json:"status"`
+	ComputedAt        *time.Time       `json:"computed_at,omitempty"`
+	LockedAt          *time.Time       `json:"locked_at,omitempty"`
+	LockedByAccountID *uint            `json:"locked_by_account_id,omitempty"`
+	Payslips          []Payslip        `json:"payslips,omitempty" gorm:"foreignKey:PayrollRunID;constraint:OnDelete:CASCADE"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+func (r *PayrollRun) BeforeCreate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *PayrollRun) BeforeUpdate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *PayrollRun) validate() error {
+	switch r.Status {
+	case PayrollRunOpen, PayrollRunLocked:
+	default:
+		return errors.New("invalid payroll run status: " + string(r.Status))
+	}
+
+	if r.Month < 1 || r.Month > 12 {
+		return errors.New("invalid month")
+	}
+
+	return nil
+}
+
+// Payslip 給与計算時点の従業員ごとの給与明細
+// Snapshot は計算結果のJSONをそのまま保存したもので、締めた後はこの内容を返す
+type Payslip struct {
+	ID              uint          `gorm:"primaryKey" json:"id"`
+	PayrollRunID    uint          `gorm:"not null;uniqueIndex:idx_payslip" json:"payroll_run_id"`
+	EmployeeID      uint          `gorm:"not null;uniqueIndex:idx_payslip" json:"employee_id"`
+	EmployeeName    string        `json:"employee_name"`
+	GrossSalary     float64       `json:"gross_salary"`
+	TotalDeductions float64       `json:"total_deductions"`
+	NetSalary       float64       `json:"net_salary"`
+	Snapshot        string        `gorm:"type:jsonb;not null" json:"-"`
+	Items           []PayslipItem `json:"items,omitempty" gorm:"foreignKey:PayslipID;constraint:OnDelete:CASCADE"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+type PayslipItemCategory string
+
+const (
+	PayslipEarning   PayslipItemCategory = "earning"   // 支給
+	PayslipDeduction PayslipItemCategory = "deduction" // 控除
+)
+
+// PayslipItem 給与明細の支給・控除の各項目
+type PayslipItem struct {
+	ID        uint                `gorm:"primaryKey" json:"id"`
+	PayslipID uint                `gorm:"not null;index" json:"payslip_id"`
+	Category  PayslipItemCategory `gorm:"type:varchar(20);not null" json:"category"`
+	Code      string              `gorm:"type:varchar(50);not null" json:"code"`
+	Label     string              `json:"label"`
+	Amount    float64             `json:"amount"`
+	SortOrder int                 `json:"sort_order"`
+}
+
+// checkPayrollPeriodOpen 従業員の会社で指定した年・月の給与計算が締められていればエラーを返す
+func checkPayrollPeriodOpen(tx *gorm.DB, employeeID uint, year, month int) error {
+	var count int64
+	if err := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&PayrollRun{}).
+		Joins("JOIN employees ON employees.company_id = payroll_runs.company_id").
+		Where("employees.id = ? AND payroll_runs.year = ? AND payroll_runs.month = ? AND payroll_runs.status = ?",
+			employeeID, year, month, PayrollRunLocked).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPayrollPeriodLocked
+	}
+	return nil
+}
+
+// checkPayrollRangeOpen 従業員の会社で指定した期間 (periodKey で表した開始月〜終了月) に締められた月があればエラーを返す
+func checkPayrollRangeOpen(tx *gorm.DB, employeeID uint, from, to int) error {
+	if from > to {
+		return nil
+	}
+	var count int64
+	if err := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&PayrollRun{}).
+		Joins("JOIN employees ON employees.company_id = payroll_runs.company_id").
+		Where("employees.id = ? AND payroll_runs.year * 12 + payroll_runs.month - 1 BETWEEN ? AND ? AND payroll_runs.status = ?",
+			employeeID, from, to, PayrollRunLocked).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPayrollPeriodLocked
+	}
+	return nil
+}
+
+// periodKey 年・月を月の通し番号で表す (期間の比較に使う)
+func periodKey(year, month int) int {
+	return year*12 + month - 1
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/db/dbtest"
+)
+
+// lockedRunsQuery 締められた給与計算を数えるクエリ
+const lockedRunsQuery = `FROM "payroll_runs" JOIN employees`
+
+// lockedCount 締められた給与計算の件数を返す結果
+func lockedCount(n int64) dbtest.Result {
+	return dbtest.Result{Columns: []string{"count"}, Rows: [][]any{{n}}}
+}
+
+// lockedRangeChecks 締めた月を確認した期間 (periodKey の開始・終了)
+func lockedRangeChecks(rec *dbtest.Recorder) [][2]int {
+	var ranges [][2]int
+	for _, q := range rec.Queries() {
+		if strings.Contains(q.SQL, lockedRunsQuery) && strings.Contains(q.SQL, "BETWEEN") {
+			ranges = append(ranges, [2]int{q.Args[1].(int), q.Args[2].(int)})
+		}
+	}
+	return ranges
+}
+
+func TestCheckPayrollPeriodOpen(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int64
+		wantErr error
+	}{
+		{"締められていない月", 0, nil},
+		{"締められた月", 1, ErrPayrollPeriodLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := dbtest.Open(t)
+			rec.On(lockedRunsQuery, lockedCount(tt.count))
+
+			if err := checkPayrollPeriodOpen(db, 1, 2025, 6); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkPayrollPeriodOpen error = %v, want %v", err, tt.wantErr)
+			}
+			q, ok := rec.Find(lockedRunsQuery)
+			if !ok {
+				t.Fatal("locked payroll runs were not counted")
+			}
+			if len(q.Args) != 4 || q.Args[0] != uint(1) || q.Args[1] != 2025 || q.Args[2] != 6 || q.Args[3] != PayrollRunLocked {
+				t.Errorf("args = %v, want [1 2025 6 locked]", q.Args)
+			}
+		})
+	}
+}
+
+func TestCheckPayrollRangeOpen(t *testing.T) {
+	db, rec := dbtest.Open(t)
+	rec.On(lockedRunsQuery, lockedCount(1))
+
+	// 開始月が終了月より後の期間は確認しない
+	if err := checkPayrollRangeOpen(db, 1, periodKey(2025, 7), periodKey(2025, 6)); err != nil {
+		t.Errorf("empty range error = %v", err)
+	}
+	if len(rec.Queries()) != 0 {
+		t.Errorf("empty range issued %d queries", len(rec.Queries()))
+	}
+
+	if err := checkPayrollRangeOpen(db, 1, periodKey(2025, 4), periodKey(2025, 6)); !errors.Is(err, ErrPayrollPeriodLocked) {
+		t.Errorf("locked range error = %v, want ErrPayrollPeriodLocked", err)
+	}
+	if got, want := lockedRangeChecks(rec), [][2]int{{periodKey(2025, 4), periodKey(2025, 6)}}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("checked ranges = %v, want %v", got, want)
+	}
+}
+
+func TestWorkRecordLockedMonth(t *testing.T) {
+	lockedMay := func(q dbtest.Query) bool {
+		return strings.Contains(q.SQL, lockedRunsQuery) && len(q.Args) == 4 && q.Args[2] == 5
+	}
+	storedMay := dbtest.Result{
+		Columns: []string{"id", "employee_id", "date"},
+		Rows:    [][]any{{int64(10), int64(1), time.Date(2025, time.May, 31, 0, 0, 0, 0, time.Local)}},
+	}
+	tests := []struct {
+		name    string
+		record  WorkRecord
+		stored  bool
+		wantErr bool
+	}{
+		{"締められた月の勤務記録は作成できない", WorkRecord{EmployeeID: 1, Date: time.Date(2025, time.May, 20, 0, 0, 0, 0, time.Local)}, false, true},
+		{"締められていない月の勤務記録は作成できる", WorkRecord{EmployeeID: 1, Date: time.Date(2025, time.June, 2, 0, 0, 0, 0, time.Local)}, false, false},
+		{"締められた月の勤務記録は別の月に移せない", WorkRecord{ID: 10, EmployeeID: 1, Date: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local)}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := dbtest.Open(t)
+			rec.OnQuery(lockedMay, lockedCount(1))
+			if tt.stored {
+				rec.On(`FROM "work_records"`, storedMay)
+			}
+
+			err := db.Save(&tt.record).Error
+			if tt.wantErr {
+				if !errors.Is(err, ErrPayrollPeriodLocked) {
+					t.Fatalf("error = %v, want ErrPayrollPeriodLocked", err)
+				}
+				for _, verb := range []string{"INSERT", "UPDATE"} {
+					if _, ok := rec.Find(verb + ` INTO "work_records"`); ok {
+						t.Errorf("work record was written by %s", verb)
+					}
+					if _, ok := rec.Find(verb + ` "work_records"`); ok {
+						t.Errorf("work record was written by %s", verb)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := rec.Find(`INSERT INTO "work_records"`); !ok {
+				t.Error("work record was not inserted")
+			}
+		})
+	}
+}
+
+func TestEmployeeAllowanceLockedMonth(t *testing.T) {
+	db, rec := dbtest.Open(t)
+	rec.On(lockedRunsQuery, lockedCount(1))
+
+	err := db.Create(&EmployeeAllowance{EmployeeID: 1, AllowanceTypeID: 2, Amount: 5000, Year: 2025, Month: 5}).Error
+	if !errors.Is(err, ErrPayrollPeriodLocked) {
+		t.Fatalf("error = %v, want ErrPayrollPeriodLocked", err)
+	}
+	if _, ok := rec.Find(`INSERT INTO "employee_allowances"`); ok {
+		t.Error("allowance was inserted into a locked month")
+	}
+}
+
+func TestRecurringAllowanceLockedRange(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	storedColumns := []string{"id", "employee_id", "allowance_type_id", "amount", "from_year", "from_month", "to_year", "to_month"}
+
+	tests := []struct {
+		name   string
+		stored []any
+		ra     RecurringAllowance
+		want   [][2]int
+	}{
+		{
+			"新規は開始月から終了なしまで",
+			nil,
+			RecurringAllowance{EmployeeID: 1, AllowanceTypeID: 2, Amount: 10000, FromYear: 2025, FromMonth: 4},
+			[][2]int{{periodKey(2025, 4), math.MaxInt32}},
+		},
+		{
+			"終了月の設定は支給しなくなる月だけ",
+			[]any{int64(5), int64(1), int64(2), int64(10000), int64(2025), int64(1), nil, nil},
+			RecurringAllowance{ID: 5, EmployeeID: 1, AllowanceTypeID: 2, Amount: 10000, FromYear: 2025, FromMonth: 1, ToYear: intPtr(2025), ToMonth: intPtr(6)},
+			[][2]int{{periodKey(2025, 6) + 1, math.MaxInt32}},
+		},
+		{
+			"終了月の延長は支給する月が増えた分だけ",
+			[]any{int64(5), int64(1), int64(2), int64(10000), int64(2025), int64(1), int64(2025), int64(3)},
+			RecurringAllowance{ID: 5, EmployeeID: 1, AllowanceTypeID: 2, Amount: 10000, FromYear: 2025, FromMonth: 1, ToYear: intPtr(2025), ToMonth: intPtr(8)},
+			[][2]int{{periodKey(2025, 4), periodKey(2025, 8)}},
+		},
+		{
+			"金額の変更は変更前・変更後の期間すべて",
+			[]any{int64(5), int64(1), int64(2), int64(10000), int64(2025), int64(1), int64(2025), int64(3)},
+			RecurringAllowance{ID: 5, EmployeeID: 1, AllowanceTypeID: 2, Amount: 12000, FromYear: 2025, FromMonth: 2, ToYear: intPtr(2025), ToMonth: intPtr(3)},
+			[][2]int{{periodKey(2025, 1), periodKey(2025, 3)}, {periodKey(2025, 2), periodKey(2025, 3)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := dbtest.Open(t)
+			if tt.stored != nil {
+				rec.On(`FROM "recurring_allowances"`, dbtest.Result{Columns: storedColumns, Rows: [][]any{tt.stored}})
+			}
+			rec.On(lockedRunsQuery, lockedCount(0))
+
+			if err := tt.ra.BeforeSave(db); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := lockedRangeChecks(rec)
+			if len(got) != len(tt.want) {
+				t.Fatalf("checked ranges = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("checked range %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurringAllowanceEndInLockedRange(t *testing.T) {
+	db, rec := dbtest.Open(t)
+	rec.On(`FROM "recurring_allowances"`, dbtest.Result{
+		Columns: []string{"id", "employee_id", "allowance_type_id", "amount", "from_year", "from_month"},
+		Rows:    [][]any{{int64(5), int64(1), int64(2), int64(10000), int64(2025), int64(1)}},
+	})
+	rec.On(lockedRunsQuery, lockedCount(1))
+
+	// 支給しなくなる月に締められた月があれば、終了月を設定できない
+	toYear, toMonth := 2025, 3
+	ra := RecurringAllowance{ID: 5, EmployeeID: 1, AllowanceTypeID: 2, Amount: 10000, FromYear: 2025, FromMonth: 1, ToYear: &toYear, ToMonth: &toMonth}
+	if err := ra.BeforeSave(db); !errors.Is(err, ErrPayrollPeriodLocked) {
+		t.Errorf("error = %v, want ErrPayrollPeriodLocked", err)
+	}
+}
@@ -11,6 +11,7 @@ type PensionInsuranceRate struct {
 	MaxMonthlyAmount int
 	PensionTotal     float64
 	PensionHalf      float64
+	PensionRate      float64 // 保険料率 (%)
 	FromYear         int
 	FromMonth        int
 	ToYear           int
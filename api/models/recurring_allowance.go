@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecurringAllowance 開始月から終了月まで毎月支給する手当 (通勤手当・住宅手当など)
+// 特定の月だけ金額を変える場合は、その月の EmployeeAllowance を登録して上書きする
+type RecurringAllowance struct {
+	ID              uint          `gorm:"primaryKey" json:"id"`
+	EmployeeID      uint          `gorm:"not null;index" json:"employee_id"`
+	AllowanceTypeID uint          `gorm:"not null" json:"allowance_type_id"`
+	Amount          int           `json:"amount"`
+	CommissionRate  *float64      `json:"commission_rate,omitempty"`
+	FromYear        int           `gorm:"not null" json:"from_year"`
+	FromMonth       int           `gorm:"not null" json:"from_month"`
+	ToYear          *int          `json:"to_year,omitempty"` // 終了月 (設定されていなければ終了なし)
+	ToMonth         *int          `json:"to_month,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	AllowanceType   AllowanceType `json:"allowance_type" gorm:"foreignKey:AllowanceTypeID"`
+}
+
+// Covers 指定した年・月が支給期間に含まれるか
+func (ra *RecurringAllowance) Covers(year, month int) bool {
+	key := periodKey(year, month)
+	return ra.startKey() <= key && key <= ra.endKey()
+}
+
+func (ra *RecurringAllowance) startKey() int {
+	return periodKey(ra.FromYear, ra.FromMonth)
+}
+
+func (ra *RecurringAllowance) endKey() int {
+	if ra.ToYear == nil || ra.ToMonth == nil {
+		return math.MaxInt32
+	}
+	return periodKey(*ra.ToYear, *ra.ToMonth)
+}
+
+// BeforeSave 変更前・変更後の支給期間に締められた月があれば保存しない
+// 終了月の変更のみの場合は、支給期間が増減した月だけを確認する (締めた月を含む手当も終了させられるように)
+func (ra *RecurringAllowance) BeforeSave(tx *gorm.DB) error {
+	if err := ra.validate(); err != nil {
+		return err
+	}
+
+	if ra.ID != 0 {
+		var stored RecurringAllowance
+		if err := tx.Session(&gorm.Session{NewDB: true}).First(&stored, ra.ID).Error; err == nil {
+			if stored.sameTerms(ra) {
+				from, to := min(stored.endKey(), ra.endKey())+1, max(stored.endKey(), ra.endKey())
+				return checkPayrollRangeOpen(tx, ra.EmployeeID, from, to)
+			}
+			if err := checkPayrollRangeOpen(tx, stored.EmployeeID, stored.startKey(), stored.endKey()); err != nil {
+				return err
+			}
+		}
+	}
+	return checkPayrollRangeOpen(tx, ra.EmployeeID, ra.startKey(), ra.endKey())
+}
+
+func (ra *RecurringAllowance) BeforeDelete(tx *gorm.DB) error {
+	var stored RecurringAllowance
+	if err := tx.Session(&gorm.Session{NewDB: true}).First(&stored, ra.ID).Error; err != nil {
+		return nil
+	}
+	return checkPayrollRangeOpen(tx, stored.EmployeeID, stored.startKey(), stored.endKey())
+}
+
+// sameTerms 終了月以外の内容が同じか
+func (ra *RecurringAllowance) sameTerms(other *RecurringAllowance) bool {
+	sameRate := (ra.CommissionRate == nil && other.CommissionRate == nil) ||
+		(ra.CommissionRate != nil && other.CommissionRate != nil && *ra.CommissionRate == *other.CommissionRate)
+	return sameRate &&
+		ra.EmployeeID == other.EmployeeID &&
+		ra.AllowanceTypeID == other.AllowanceTypeID &&
+		ra.Amount == other.Amount &&
+		ra.FromYear == other.FromYear &&
+		ra.FromMonth == other.FromMonth
+}
+
+func (ra *RecurringAllowance) validate() error {
+	if ra.Amount < 0 {
+		return errors.New("amount must not be negative")
+	}
+	if ra.FromMonth < 1 || ra.FromMonth > 12 {
+		return errors.New("invalid from_month")
+	}
+	if (ra.ToYear == nil) != (ra.ToMonth == nil) {
+		return errors.New("to_year and to_month must be specified together")
+	}
+	if ra.ToMonth != nil && (*ra.ToMonth < 1 || *ra.ToMonth > 12) {
+		return errors.New("invalid to_month")
+	}
+	if ra.endKey() < ra.startKey() {
+		return errors.New("end month must not be before start month")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ResidentTaxNotice 市区町村から届く特別徴収税額の決定通知 (6月から翌年5月までの1年度分)
+type ResidentTaxNotice struct {
+	ID            uint                     `gorm:"primaryKey" json:"id"`
+	EmployeeID    uint                     `gorm:"not null;uniqueIndex:idx_resident_tax_notice" json:"employee_id"`
+	FiscalYear    int                      `gorm:"not null;uniqueIndex:idx_resident_tax_notice" json:"fiscal_year"` // 6月が属する年
+	Municipality  string                   `json:"municipality"`
+	AnnualAmount  int                      `gorm:"not null" json:"annual_amount"`
+	JuneAmount    int                      `gorm:"not null" json:"june_amount"`    // 6月分 (端数を含むため他の月と異なる)
+	MonthlyAmount int                      `gorm:"not null" json:"monthly_amount"` // 7月分〜翌年5月分
+	Installments  []ResidentTaxInstallment `json:"installments,omitempty" gorm:"foreignKey:NoticeID;constraint:OnDelete:CASCADE"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+}
+
+// ResidentTaxInstallment 各月の給与から控除する住民税額
+type ResidentTaxInstallment struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	NoticeID   uint      `gorm:"not null;index" json:"notice_id"`
+	EmployeeID uint      `gorm:"not null;index" json:"employee_id"`
+	Year       int       `gorm:"not null" json:"year"`
+	Month      int       `gorm:"not null" json:"month"`
+	Amount     int       `gorm:"not null" json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShiftPattern 会社で使う勤務の型 (例: 9:00〜18:00、休憩60分)
+// 終了時刻が開始時刻以前の場合は翌日の時刻として扱う
+type ShiftPattern struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CompanyID    uint      `gorm:"not null;index" json:"company_id"`
+	Name         string    `gorm:"not null" json:"name"`
+	StartTime    string    `gorm:"type:varchar(5);not null" json:"start_time"` // "09:00" 形式
+	EndTime      string    `gorm:"type:varchar(5);not null" json:"end_time"`
+	BreakMinutes int       `gorm:"not null;default:0" json:"break_minutes"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (p *ShiftPattern) BeforeCreate(tx *gorm.DB) error {
+	return p.validate()
+}
+
+func (p *ShiftPattern) BeforeUpdate(tx *gorm.DB) error {
+	return p.validate()
+}
+
+func (p *ShiftPattern) validate() error {
+	start, err := parseClockTime(p.StartTime)
+	if err != nil {
+		return errors.New("invalid start_time: " + p.StartTime)
+	}
+	end, err := parseClockTime(p.EndTime)
+	if err != nil {
+		return errors.New("invalid end_time: " + p.EndTime)
+	}
+	if p.BreakMinutes < 0 {
+		return errors.New("break_minutes must not be negative")
+	}
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	if time.Duration(p.BreakMinutes)*time.Minute >= end-start {
+		return errors.New("break_minutes must be shorter than the shift")
+	}
+	return nil
+}
+
+// Span 勤務日の0時を基準とした開始・終了時刻
+func (p *ShiftPattern) Span(date time.Time) (time.Time, time.Time) {
+	start, _ := parseClockTime(p.StartTime)
+	end, _ := parseClockTime(p.EndTime)
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	return day.Add(start), day.Add(end)
+}
+
+// ScheduledMinutes 休憩を除いた所定労働時間 (分)
+func (p *ShiftPattern) ScheduledMinutes() int64 {
+	start, end := p.Span(time.Time{})
+	return int64(end.Sub(start).Minutes()) - int64(p.BreakMinutes)
+}
+
+// parseClockTime "15:04" 形式の時刻を0時からの経過時間にする
+func parseClockTime(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ShiftAssignment 従業員ごと・日ごとの勤務予定
+// ShiftPatternID が設定されていない場合は休日 (勤務の予定がない日) とする
+type ShiftAssignment struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	EmployeeID     uint          `gorm:"not null;uniqueIndex:idx_shift_assignment" json:"employee_id"`
+	Date           time.Time     `gorm:"type:date;not null;uniqueIndex:idx_shift_assignment" json:"date"`
+	ShiftPatternID *uint         `json:"shift_pattern_id"`
+	ShiftPattern   *ShiftPattern `json:"shift_pattern,omitempty" gorm:"foreignKey:ShiftPatternID"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 標準報酬月額の決定・改定の種類
+const (
+	RemunerationAcquisition = "acquisition" // 資格取得時決定
+	RemunerationTeiji       = "teiji"       // 定時決定 (算定基礎届)
+	RemunerationZuiji       = "zuiji"       // 随時改定 (月額変更届)
+)
+
+// StandardRemuneration 従業員ごとの標準報酬月額の履歴 (FromYear/FromMonth から次の履歴の前月まで適用)
+type StandardRemuneration struct {
+	ID                   uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID           uint      `gorm:"not null;uniqueIndex:idx_standard_remuneration" json:"employee_id"`
+	Reason               string    `gorm:"type:varchar(20);not null" json:"reason"`
+	AverageRemuneration  int       `json:"average_remuneration"` // 決定の基礎となった報酬月額の平均
+	HealthGrade          string    `json:"health_grade"`
+	HealthMonthlyAmount  int       `json:"health_monthly_amount"`
+	PensionGrade         string    `json:"pension_grade"`
+	PensionMonthlyAmount int       `json:"pension_monthly_amount"`
+	FromYear             int       `gorm:"not null;uniqueIndex:idx_standard_remuneration" json:"from_year"`
+	FromMonth            int       `gorm:"not null;uniqueIndex:idx_standard_remuneration" json:"from_month"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+func (r *StandardRemuneration) BeforeCreate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *StandardRemuneration) BeforeUpdate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *StandardRemuneration) validate() error {
+	switch r.Reason {
+	case RemunerationAcquisition, RemunerationTeiji, RemunerationZuiji:
+	default:
+		return errors.New("invalid standard remuneration reason: " + r.Reason)
+	}
+
+	if r.FromMonth < 1 || r.FromMonth > 12 {
+		return errors.New("invalid from_month")
+	}
+
+	return nil
+}
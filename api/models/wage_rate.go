@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 給与の支払形態
+const (
+	PayTypeMonthly = "monthly" // 月給
+	PayTypeDaily   = "daily"   // 日給
+	PayTypeHourly  = "hourly"  // 時給
+)
+
+// WageRate 従業員の支払形態と月給・日給・時給の額の履歴 (FromYear/FromMonth から次の履歴の前月まで適用)
+// 昇給などは適用開始月を将来の月にして登録しておき、その月の給与計算から反映する
+type WageRate struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID uint      `gorm:"not null;uniqueIndex:idx_wage_rate" json:"employee_id"`
+	PayType    string    `gorm:"type:varchar(10);not null;default:'monthly'" json:"pay_type"`
+	Rate       int       `gorm:"not null" json:"rate"` // 月給者は月額、日給者は日額、時給者は時間額
+	FromYear   int       `gorm:"not null;uniqueIndex:idx_wage_rate" json:"from_year"`
+	FromMonth  int       `gorm:"not null;uniqueIndex:idx_wage_rate" json:"from_month"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// BeforeSave 適用開始月の給与計算が締められている場合は、確定した給与が変わるため登録できない
+func (r *WageRate) BeforeSave(tx *gorm.DB) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	return checkPayrollPeriodOpen(tx, r.EmployeeID, r.FromYear, r.FromMonth)
+}
+
+func (r *WageRate) validate() error {
+	switch r.PayType {
+	case PayTypeMonthly, PayTypeDaily, PayTypeHourly:
+	default:
+		return errors.New("invalid pay type: " + r.PayType)
+	}
+	if r.Rate <= 0 {
+		return errors.New("rate must be positive")
+	}
+	if r.FromMonth < 1 || r.FromMonth > 12 {
+		return errors.New("invalid from_month")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	TaxTableKou  = "kou"  // 甲欄 (扶養控除等申告書の提出あり)
+	TaxTableOtsu = "otsu" // 乙欄 (扶養控除等申告書の提出なし)
+)
+
+// WithholdingTaxRate 給与所得の源泉徴収税額表 (月額表) の1マス分
+// 社会保険料等控除後の給与等の金額が MinAmount 以上 MaxAmount 未満の場合の税額
+type WithholdingTaxRate struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Year       int       `gorm:"not null;index" json:"year"`
+	TaxTable   string    `gorm:"type:varchar(10);not null" json:"tax_table"`
+	Dependents int       `gorm:"not null" json:"dependents"` // 扶養親族等の数 (乙欄は0)
+	MinAmount  int       `gorm:"not null" json:"min_amount"`
+	MaxAmount  int       `gorm:"not null" json:"max_amount"`
+	Tax        int       `gorm:"not null" json:"tax"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// BonusWithholdingTaxRate 賞与に対する源泉徴収税額の算出率の表の1マス分
+// 前月の社会保険料等控除後の給与等の金額が MinAmount 以上 MaxAmount 未満の場合に賞与に乗ずる率
+type BonusWithholdingTaxRate struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Year       int       `gorm:"not null;index" json:"year"`
+	TaxTable   string    `gorm:"type:varchar(10);not null" json:"tax_table"`
+	Dependents int       `gorm:"not null" json:"dependents"` // 扶養親族等の数 (乙欄は0)
+	MinAmount  int       `gorm:"not null" json:"min_amount"`
+	MaxAmount  int       `gorm:"not null" json:"max_amount"`
+	Rate       float64   `gorm:"not null" json:"rate"` // 賞与の金額に乗ずべき率 (%)
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
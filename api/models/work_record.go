@@ -1,15 +1,61 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 休暇の勤務記録の種類
+const (
+	WorkRecordPaidLeave    = "paid_leave"    // 年次有給休暇
+	WorkRecordSpecialLeave = "special_leave" // 特別休暇
+)
 
 type WorkRecord struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	EmployeeID   uint      `gorm:"not null" json:"employee_id"`
-	Date         time.Time `gorm:"type:date;not null" json:"date"`
-	ClockIn      time.Time `json:"clock_in"`
-	ClockOut     time.Time `json:"clock_out"`
-	BreakMinutes int64     `json:"break_minutes"`
-	WorkMinutes  int64     `json:"work_minutes"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID        uint      `gorm:"not null" json:"employee_id"`
+	ClockInID         *uint     `gorm:"index" json:"clock_in_id,omitempty"` // 勤務の起点となる出勤打刻 (勤務単位で集計するためのキー)
+	Date              time.Time `gorm:"type:date;not null" json:"date"`     // 出勤時刻が属する営業日
+	ClockIn           time.Time `json:"clock_in"`
+	ClockOut          time.Time `json:"clock_out"`
+	BreakMinutes      int64     `json:"break_minutes"`
+	WorkMinutes       int64     `json:"work_minutes"`
+	OvertimeMinutes   int64     `json:"overtime_minutes"`                                                 // 法定時間外労働 (1日・1週の法定労働時間超)
+	LateNightMinutes  int64     `json:"late_night_minutes"`                                               // 深夜労働 (22:00〜5:00)
+	HolidayMinutes    int64     `json:"holiday_minutes"`                                                  // 法定休日労働
+	LeaveType         string    `gorm:"type:varchar(20);not null;default:''" json:"leave_type,omitempty"` // 休暇の記録の場合の種類 (打刻による勤務の場合は空)
+	LeaveMinutes      int64     `gorm:"not null;default:0" json:"leave_minutes"`                          // 休暇として賃金を支払う時間 (労働時間には含めない)
+	LateMinutes       int64     `gorm:"not null;default:0" json:"late_minutes"`                           // 勤務予定の開始時刻からの遅刻
+	EarlyLeaveMinutes int64     `gorm:"not null;default:0" json:"early_leave_minutes"`                    // 勤務予定の終了時刻までの早退
+	Unscheduled       bool      `gorm:"not null;default:false" json:"unscheduled"`                        // 勤務予定のない日の勤務
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// BeforeSave 変更前・変更後のいずれかの勤務日の月の給与計算が締められていれば保存しない
+func (wr *WorkRecord) BeforeSave(tx *gorm.DB) error {
+	if err := wr.checkStoredPeriodOpen(tx); err != nil {
+		return err
+	}
+	if wr.Date.IsZero() {
+		return nil
+	}
+	return checkPayrollPeriodOpen(tx, wr.EmployeeID, wr.Date.Year(), int(wr.Date.Month()))
+}
+
+func (wr *WorkRecord) BeforeDelete(tx *gorm.DB) error {
+	return wr.checkStoredPeriodOpen(tx)
+}
+
+// checkStoredPeriodOpen 保存済みの勤務記録が締められた月のものでないかを確認する
+func (wr *WorkRecord) checkStoredPeriodOpen(tx *gorm.DB) error {
+	if wr.ID == 0 {
+		return nil
+	}
+	var stored WorkRecord
+	if err := tx.Session(&gorm.Session{NewDB: true}).First(&stored, wr.ID).Error; err != nil {
+		return nil
+	}
+	return checkPayrollPeriodOpen(tx, stored.EmployeeID, stored.Date.Year(), int(stored.Date.Month()))
 }
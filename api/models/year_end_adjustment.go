@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// YearEndAdjustment 従業員ごと・年ごとの年末調整
+// 保険料控除申告書・配偶者控除等申告書・扶養控除等申告書の内容と、その年の給与・賞与から計算した年税額を保持する
+// 計算済み (CalculatedAt が設定されている) の場合、過不足額を12月の給与で精算する
+type YearEndAdjustment struct {
+	ID         uint `gorm:"primaryKey" json:"id"`
+	EmployeeID uint `gorm:"not null;uniqueIndex:idx_year_end_adjustment" json:"employee_id"`
+	Year       int  `gorm:"not null;uniqueIndex:idx_year_end_adjustment" json:"year"`
+
+	// 支払った保険料 (保険料控除申告書)
+	LifeInsuranceNew     int `gorm:"not null;default:0" json:"life_insurance_new"`      // 一般の生命保険料 (新契約)
+	LifeInsuranceOld     int `gorm:"not null;default:0" json:"life_insurance_old"`      // 一般の生命保険料 (旧契約)
+	CareMedicalInsurance int `gorm:"not null;default:0" json:"care_medical_insurance"`  // 介護医療保険料
+	PensionInsuranceNew  int `gorm:"not null;default:0" json:"pension_insurance_new"`   // 個人年金保険料 (新契約)
+	PensionInsuranceOld  int `gorm:"not null;default:0" json:"pension_insurance_old"`   // 個人年金保険料 (旧契約)
+	EarthquakeInsurance  int `gorm:"not null;default:0" json:"earthquake_insurance"`    // 地震保険料
+	OldLongTermInsurance int `gorm:"not null;default:0" json:"old_long_term_insurance"` // 旧長期損害保険料
+	OtherSocialInsurance int `gorm:"not null;default:0" json:"other_social_insurance"`  // 給与から控除していない社会保険料 (国民年金保険料など)
+
+	// 配偶者 (配偶者控除等申告書)
+	HasSpouse         bool       `gorm:"not null;default:false" json:"has_spouse"`
+	SpouseName        string     `json:"spouse_name"`
+	SpouseDateOfBirth *time.Time `gorm:"type:date" json:"spouse_date_of_birth,omitempty"`
+	SpouseIncome      int        `gorm:"not null;default:0" json:"spouse_income"` // 配偶者の合計所得金額 (見積額)
+
+	HousingLoanCredit int `gorm:"not null;default:0" json:"housing_loan_credit"` // 住宅借入金等特別控除可能額
+
+	// 前職分 (その年の中途で入社した場合に、前の勤務先の源泉徴収票から転記する)
+	PreviousEmployerPay             int `gorm:"not null;default:0" json:"previous_employer_pay"`              // 支払金額
+	PreviousEmployerSocialInsurance int `gorm:"not null;default:0" json:"previous_employer_social_insurance"` // 社会保険料等の金額
+	PreviousEmployerWithheldTax     int `gorm:"not null;default:0" json:"previous_employer_withheld_tax"`     // 源泉徴収税額
+
+	Dependents []YearEndDependent `json:"dependents" gorm:"foreignKey:AdjustmentID;constraint:OnDelete:CASCADE"`
+
+	// 計算結果
+	TotalPay                     int        `json:"total_pay"`                      // 給与・賞与の支払金額 (前職分を含む)
+	SalaryIncome                 int        `json:"salary_income"`                  // 給与所得控除後の給与等の金額
+	SocialInsuranceDeduction     int        `json:"social_insurance_deduction"`     // 社会保険料控除
+	LifeInsuranceDeduction       int        `json:"life_insurance_deduction"`       // 生命保険料控除
+	EarthquakeInsuranceDeduction int        `json:"earthquake_insurance_deduction"` // 地震保険料控除
+	SpouseDeduction              int        `json:"spouse_deduction"`               // 配偶者控除・配偶者特別控除
+	DependentDeduction           int        `json:"dependent_deduction"`            // 扶養控除
+	BasicDeduction               int        `json:"basic_deduction"`                // 基礎控除
+	TotalDeductions              int        `json:"total_deductions"`               // 所得控除の合計
+	TaxableIncome                int        `json:"taxable_income"`                 // 課税給与所得金額 (1,000円未満切り捨て)
+	CalculatedTax                int        `json:"calculated_tax"`                 // 算出所得税額
+	HousingLoanDeduction         int        `json:"housing_loan_deduction"`         // 住宅借入金等特別控除額
+	AnnualTax                    int        `json:"annual_tax"`                     // 年調年税額 (復興特別所得税を含む)
+	WithheldTax                  int        `json:"withheld_tax"`                   // 給与・賞与から源泉徴収した税額 (前職分を含む)
+	Difference                   int        `json:"difference"`                     // 過不足額 (正:不足額を徴収, 負:超過額を還付)
+	CalculatedAt                 *time.Time `json:"calculated_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BeforeSave 12月の給与計算が締められた後は、精算額が変わるため年末調整を変更できない
+func (a *YearEndAdjustment) BeforeSave(tx *gorm.DB) error {
+	if err := a.validate(); err != nil {
+		return err
+	}
+	return checkPayrollPeriodOpen(tx, a.EmployeeID, a.Year, 12)
+}
+
+func (a *YearEndAdjustment) validate() error {
+	amounts := []int{
+		a.LifeInsuranceNew, a.LifeInsuranceOld, a.CareMedicalInsurance, a.PensionInsuranceNew, a.PensionInsuranceOld,
+		a.EarthquakeInsurance, a.OldLongTermInsurance, a.OtherSocialInsurance, a.SpouseIncome, a.HousingLoanCredit,
+		a.PreviousEmployerPay, a.PreviousEmployerSocialInsurance, a.PreviousEmployerWithheldTax,
+	}
+	for _, v := range amounts {
+		if v < 0 {
+			return errors.New("declared amounts must not be negative")
+		}
+	}
+	if a.HasSpouse && a.SpouseDateOfBirth == nil {
+		return errors.New("spouse_date_of_birth is required")
+	}
+	return nil
+}
+
+// YearEndDependent 扶養控除等申告書に記載された扶養親族
+type YearEndDependent struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	AdjustmentID     uint      `gorm:"not null;index" json:"adjustment_id"`
+	Name             string    `gorm:"not null" json:"name"`
+	DateOfBirth      time.Time `gorm:"type:date;not null" json:"date_of_birth"`
+	Income           int       `gorm:"not null;default:0" json:"income"`                // 合計所得金額 (見積額)
+	CohabitingParent bool      `gorm:"not null;default:false" json:"cohabiting_parent"` // 同居している本人または配偶者の直系尊属
+}
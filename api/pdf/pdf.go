@@ -0,0 +1,187 @@
+// Package pdf は給与明細などの帳票を出力するための最小限のPDFライタ
+//
+// 日本語は PDF ビューアが備える非埋め込みのCIDフォント (HeiseiKakuGo-W5, UniJIS-UCS2-H) で描画するため、
+// フォントファイルを同梱せずに出力できる。対応するのは A4 縦の1ページ単位の文字・罫線の描画のみ。
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// PageWidth A4 の幅 (pt)
+	PageWidth = 595.28
+	// PageHeight A4 の高さ (pt)
+	PageHeight = 841.89
+
+	fontName = "HeiseiKakuGo-W5"
+	// halfWidthEm 半角文字 (ASCII) の幅 (1000分率)
+	halfWidthEm = 500
+	// fullWidthEm 全角文字の幅 (1000分率)
+	fullWidthEm = 1000
+)
+
+// Document 複数ページからなるPDF文書
+type Document struct {
+	pages []*Page
+}
+
+// Page 1ページ分の描画内容 (座標は左下が原点、単位は pt)
+type Page struct {
+	content bytes.Buffer
+}
+
+// New 空の文書を作成する
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage A4 縦のページを追加する
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text 指定位置を左端のベースラインとして文字列を描画する
+func (p *Page) Text(x, y, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, y, encodeText(s))
+}
+
+// TextRight 指定位置を右端として文字列を描画する (金額の右寄せなど)
+func (p *Page) TextRight(x, y, size float64, s string) {
+	p.Text(x-TextWidth(s, size), y, size, s)
+}
+
+// TextCenter 指定位置を中央として文字列を描画する
+func (p *Page) TextCenter(x, y, size float64, s string) {
+	p.Text(x-TextWidth(s, size)/2, y, size, s)
+}
+
+// Line 線を描画する
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Rect 矩形の枠を描画する (x, y は左下)
+func (p *Page) Rect(x, y, w, h, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f %.2f %.2f re S\n", width, x, y, w, h)
+}
+
+// FillRect 矩形を灰色で塗りつぶす (見出しの背景など)
+func (p *Page) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", gray, x, y, w, h)
+}
+
+// TextWidth 文字列の描画幅 (ASCII は半角、それ以外は全角として計算)
+func TextWidth(s string, size float64) float64 {
+	var em int
+	for _, r := range s {
+		if r < 0x80 {
+			em += halfWidthEm
+		} else {
+			em += fullWidthEm
+		}
+	}
+	return float64(em) * size / 1000
+}
+
+// encodeText 文字列を UCS-2 (ビッグエンディアン) の16進表記にする
+// 基本多言語面以外の文字はフォントで表示できないため〓に置き換える
+func encodeText(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '〓'
+		}
+		fmt.Fprintf(&sb, "%04X", r)
+	}
+	return sb.String()
+}
+
+// Bytes PDFを出力したバイト列
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo PDFを書き出す
+// オブジェクト番号は 1:Catalog 2:Pages 3:Type0フォント 4:CIDフォント 5:FontDescriptor、以降は各ページとその内容
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	beginObj := func() int {
+		offsets = append(offsets, buf.Len())
+		n := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+		return n
+	}
+	endObj := func() {
+		buf.WriteString("endobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	const firstPageObj = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+i*2)
+	}
+
+	beginObj()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
+	endObj()
+
+	beginObj()
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\n", strings.Join(kids, " "), len(d.pages))
+	endObj()
+
+	beginObj()
+	fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /UniJIS-UCS2-H /DescendantFonts [4 0 R] >>\n", fontName)
+	endObj()
+
+	beginObj()
+	fmt.Fprintf(&buf, "<< /Type /Font /Subtype /CIDFontType0 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >> "+
+		"/FontDescriptor 5 0 R /DW %d /W [1 95 %d] >>\n", fontName, fullWidthEm, halfWidthEm)
+	endObj()
+
+	beginObj()
+	fmt.Fprintf(&buf, "<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [-92 -250 1010 922] "+
+		"/ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>\n", fontName)
+	endObj()
+
+	for _, p := range d.pages {
+		pageObj := beginObj()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>\n", PageWidth, PageHeight, pageObj+1)
+		endObj()
+
+		beginObj()
+		fmt.Fprintf(&buf, "<< /Length %d >>\nstream\n", p.content.Len())
+		buf.Write(p.content.Bytes())
+		buf.WriteString("endstream\n")
+		endObj()
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
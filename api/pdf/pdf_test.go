@@ -0,0 +1,108 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		size float64
+		want float64
+	}{
+		{"1,000", 10, 25},
+		{"給与明細書", 18, 90},
+		{"12,345 円", 12, 54},
+		{"", 10, 0},
+	}
+	for _, tt := range tests {
+		if got := TextWidth(tt.s, tt.size); got != tt.want {
+			t.Errorf("TextWidth(%q, %v) = %v, want %v", tt.s, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeText(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"A1", "00410031"},
+		{"給与", "7D664E0E"},
+		// 基本多言語面以外の文字は〓に置き換える
+		{"𠮷田", "30137530"},
+	}
+	for _, tt := range tests {
+		if got := encodeText(tt.s); got != tt.want {
+			t.Errorf("encodeText(%q) = %s, want %s", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	doc := New()
+	doc.AddPage().Text(50, 790, 18, "給与明細書")
+	p := doc.AddPage()
+	p.TextRight(545, 735, 11, "1,000")
+	p.Line(50, 710, 545, 710, 0.8)
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF: %q...", out[:min(len(out), 20)])
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) || !bytes.Contains(out, []byte("/Kids [6 0 R 8 0 R]")) {
+		t.Error("pages are not listed in the page tree")
+	}
+	if !bytes.Contains(out, []byte("<7D664E0E660E7D3066F8>")) {
+		t.Error("Japanese text is not encoded as UCS-2")
+	}
+	// 右寄せは文字列の幅だけ左から描画する (5文字×半角×11pt = 27.5pt)
+	if !bytes.Contains(out, []byte("517.50 735.00 Td")) {
+		t.Error("right-aligned text is not positioned by its width")
+	}
+
+	// 相互参照表の各オブジェクトの位置に、そのオブジェクトが書かれている
+	m := regexp.MustCompile(`(?s)startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n0 10\n")) {
+		t.Fatalf("startxref %d does not point to a 10 entry xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("got %d xref entries, want 9", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("xref entry %d points to %q", i+1, out[off:min(len(out), off+10)])
+		}
+	}
+
+	// ストリームの長さが内容と一致する
+	for _, s := range regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(out, -1) {
+		if n, _ := strconv.Atoi(string(s[1])); n != len(s[2]) {
+			t.Errorf("stream length = %d, want %d", n, len(s[2]))
+		}
+	}
+}
+
+func TestWriteToEmpty(t *testing.T) {
+	// ページがなくても1ページの文書として出力する
+	out, err := New().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("/Count 1")) {
+		t.Error("empty document should have one page")
+	}
+}
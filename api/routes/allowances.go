@@ -24,4 +24,13 @@ func addAllowanceRoutes(router *gin.Engine) {
 		employeeAllowances.PUT("/:id", controllers.UpdateEmployeeAllowance)
 		employeeAllowances.DELETE("/:id", controllers.DeleteEmployeeAllowance)
 	}
+
+	recurringAllowances := router.Group("/recurring_allowances", middleware.AuthMiddleware())
+	{
+		recurringAllowances.POST("", controllers.CreateRecurringAllowance)
+		recurringAllowances.GET("", controllers.GetRecurringAllowances)
+		recurringAllowances.GET("/:id", controllers.GetRecurringAllowance)
+		recurringAllowances.PUT("/:id", controllers.UpdateRecurringAllowance)
+		recurringAllowances.DELETE("/:id", controllers.DeleteRecurringAllowance)
+	}
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
+)
+
+func addBonusPaymentRoutes(router *gin.Engine) {
+	bonuses := router.Group("/bonus_payments", middleware.AuthMiddleware())
+	{
+		bonuses.POST("", controllers.CreateBonusPayment)
+		bonuses.GET("", controllers.GetBonusPayments)
+		bonuses.GET("/:id", controllers.GetBonusPayment)
+		bonuses.DELETE("/:id", controllers.DeleteBonusPayment)
+		bonuses.GET("/:id/calculation", controllers.CalculateBonusPayment)
+	}
+}
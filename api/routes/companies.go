@@ -3,11 +3,20 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
 )
 
 func addCompanyRoutes(router *gin.Engine) {
 	companies := router.Group("/companies")
 	{
 		companies.POST("", controllers.CreateCompany)
+		companies.GET("/current", middleware.AuthMiddleware(), controllers.GetCurrentCompany)
+		companies.PUT("/current", middleware.AuthMiddleware(), controllers.UpdateCompanySettings)
+		companies.GET("/current/remitter", middleware.AuthMiddleware(), controllers.GetCompanyRemitter)
+		companies.PUT("/current/remitter", middleware.AuthMiddleware(), controllers.UpdateCompanyRemitter)
+		companies.GET("/current/calendar", middleware.AuthMiddleware(), controllers.GetCompanyCalendar)
+		companies.GET("/current/holidays", middleware.AuthMiddleware(), controllers.GetCompanyHolidays)
+		companies.PUT("/current/holidays", middleware.AuthMiddleware(), controllers.SaveCompanyHoliday)
+		companies.DELETE("/current/holidays/:id", middleware.AuthMiddleware(), controllers.DeleteCompanyHoliday)
 	}
 }
@@ -15,6 +15,21 @@ func addEmployeeRoutes(router *gin.Engine) {
 		employees.POST("/:id/attendances", controllers.CreateAttendance)
 		employees.GET("/:id/insurance", controllers.CalculateEmployeeInsurance)
 		employees.GET("/:id/pension", controllers.CalculateEmployeePension)
+		employees.GET("/:id/employment_insurance", controllers.CalculateEmployeeEmploymentInsurance)
 		employees.GET("/:id/payroll", controllers.CalculateEmployeePayroll)
+		employees.GET("/:id/payslip.pdf", controllers.DownloadEmployeePayslip)
+		employees.GET("/:id/bank_account", controllers.GetEmployeeBankAccount)
+		employees.PUT("/:id/bank_account", controllers.UpdateEmployeeBankAccount)
+		employees.GET("/:id/wage_rates", controllers.GetWageRates)
+		employees.POST("/:id/wage_rates", controllers.CreateWageRate)
+		employees.DELETE("/:id/wage_rates/:rate_id", controllers.DeleteWageRate)
+		employees.GET("/:id/employment", controllers.GetEmployment)
+		employees.PUT("/:id/employment", controllers.UpdateEmployment)
+		employees.GET("/:id/paid_leave", controllers.GetPaidLeaveLedger)
+		employees.POST("/:id/paid_leave/usages", controllers.CreatePaidLeaveUsage)
+		employees.GET("/:id/schedule", controllers.GetEmployeeSchedule)
+		employees.GET("/:id/working_time", controllers.GetWorkingTime)
+		employees.PUT("/:id/working_time", controllers.UpdateWorkingTime)
+		employees.GET("/:id/flextime_settlement", controllers.GetFlextimeSettlement)
 	}
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
+)
+
+func addLeaveRequestRoutes(router *gin.Engine) {
+	requests := router.Group("/leave_requests", middleware.AuthMiddleware())
+	{
+		requests.POST("", controllers.CreateLeaveRequest)
+		requests.GET("", controllers.GetLeaveRequests)
+		requests.POST("/:id/approve", controllers.ApproveLeaveRequest)
+		requests.POST("/:id/reject", controllers.RejectLeaveRequest)
+	}
+}
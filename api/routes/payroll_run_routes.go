@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
+)
+
+func addPayrollRunRoutes(router *gin.Engine) {
+	runs := router.Group("/payroll_runs", middleware.AuthMiddleware())
+	{
+		runs.POST("", controllers.OpenPayrollRun)
+		runs.GET("", controllers.GetPayrollRuns)
+		runs.GET("/:id", controllers.GetPayrollRun)
+		runs.POST("/:id/compute", controllers.ComputePayrollRun)
+		runs.POST("/:id/lock", controllers.LockPayrollRun)
+		runs.POST("/:id/reopen", controllers.ReopenPayrollRun)
+	}
+
+	router.GET("/payroll", middleware.AuthMiddleware(), controllers.CalculateCompanyPayroll)
+	router.GET("/payroll/payslips.zip", middleware.AuthMiddleware(), controllers.DownloadCompanyPayslips)
+	router.GET("/payroll/bank_transfer", middleware.AuthMiddleware(), controllers.ExportBankTransfer)
+}
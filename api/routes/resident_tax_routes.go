@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
+)
+
+func addResidentTaxRoutes(router *gin.Engine) {
+	notices := router.Group("/resident_tax_notices", middleware.AuthMiddleware())
+	{
+		notices.POST("", controllers.CreateResidentTaxNotice)
+		notices.GET("", controllers.GetResidentTaxNotices)
+		notices.GET("/:id", controllers.GetResidentTaxNotice)
+		notices.PUT("/:id", controllers.UpdateResidentTaxNotice)
+		notices.DELETE("/:id", controllers.DeleteResidentTaxNotice)
+	}
+}
@@ -31,6 +31,13 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 	addTimeClockRoutes(router)
 	addWorkRecordRoutes(router)
 	addClockRequestRoutes(router)
+	addLeaveRequestRoutes(router)
+	addShiftRoutes(router)
+	addResidentTaxRoutes(router)
+	addStandardRemunerationRoutes(router)
+	addBonusPaymentRoutes(router)
+	addPayrollRunRoutes(router)
+	addYearEndAdjustmentRoutes(router)
 	addLineWebhookRoutes(router, cfg)
 
 	return router
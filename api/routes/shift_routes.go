@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
+)
+
+func addShiftRoutes(router *gin.Engine) {
+	patterns := router.Group("/shift_patterns", middleware.AuthMiddleware())
+	{
+		patterns.POST("", controllers.CreateShiftPattern)
+		patterns.GET("", controllers.GetShiftPatterns)
+		patterns.PUT("/:id", controllers.UpdateShiftPattern)
+		patterns.DELETE("/:id", controllers.DeleteShiftPattern)
+	}
+
+	shifts := router.Group("/shifts", middleware.AuthMiddleware())
+	{
+		shifts.GET("", controllers.GetShifts)
+		shifts.PUT("", controllers.ReplaceShifts)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
+)
+
+func addStandardRemunerationRoutes(router *gin.Engine) {
+	employees := router.Group("/employees", middleware.AuthMiddleware())
+	{
+		employees.GET("/:id/standard_remunerations", controllers.GetStandardRemunerations)
+		employees.POST("/:id/standard_remunerations/teiji", controllers.RunEmployeeTeijiKettei)
+		employees.GET("/:id/standard_remunerations/zuiji", controllers.DetectEmployeeZuijiKaitei)
+		employees.POST("/:id/standard_remunerations/zuiji", controllers.ApplyEmployeeZuijiKaitei)
+	}
+
+	standardRemunerations := router.Group("/standard_remunerations", middleware.AuthMiddleware())
+	{
+		standardRemunerations.POST("/teiji", controllers.RunCompanyTeijiKettei)
+	}
+}
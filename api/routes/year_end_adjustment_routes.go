@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/t2469/attendance-system.git/controllers"
+	"github.com/t2469/attendance-system.git/middleware"
+)
+
+func addYearEndAdjustmentRoutes(router *gin.Engine) {
+	employees := router.Group("/employees", middleware.AuthMiddleware())
+	{
+		employees.GET("/:id/year_end_adjustment", controllers.GetYearEndAdjustment)
+		employees.PUT("/:id/year_end_adjustment", controllers.SaveYearEndDeclaration)
+		employees.POST("/:id/year_end_adjustment/calculate", controllers.CalculateYearEndAdjustment)
+		employees.GET("/:id/withholding_slip", controllers.GetWithholdingSlip)
+		employees.GET("/:id/withholding_slip.pdf", controllers.DownloadWithholdingSlip)
+	}
+}
@@ -0,0 +1,108 @@
+package seed
+
+import (
+	"log"
+	"math"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// bonusWithholdingTableYear 国税庁「賞与に対する源泉徴収税額の算出率の表 (令和2年分以降分)」の適用開始年
+const bonusWithholdingTableYear = 2020
+
+// bonusKouThresholds 甲欄の算出率の表
+// 率ごとに、扶養親族等の数 (0〜7人) の欄の前月の社会保険料等控除後の給与等の金額の「以上」(千円)
+// 「未満」は次の率の「以上」で、最後の率は上限なし
+var bonusKouThresholds = []struct {
+	Rate float64
+	Min  [kouColumns]int
+}{
+	{0, [kouColumns]int{0, 0, 0, 0, 0, 0, 0, 0}},
+	{2.042, [kouColumns]int{68, 94, 133, 171, 210, 243, 275, 308}},
+	{4.084, [kouColumns]int{79, 243, 269, 295, 300, 300, 333, 372}},
+	{6.126, [kouColumns]int{252, 282, 312, 345, 378, 406, 431, 456}},
+	{8.168, [kouColumns]int{300, 338, 369, 398, 424, 450, 476, 502}},
+	{10.210, [kouColumns]int{334, 365, 393, 417, 444, 472, 499, 523}},
+	{12.252, [kouColumns]int{363, 394, 420, 445, 470, 496, 521, 545}},
+	{14.294, [kouColumns]int{395, 422, 450, 477, 503, 525, 547, 571}},
+	{16.336, [kouColumns]int{426, 455, 484, 510, 534, 557, 582, 607}},
+	{18.378, [kouColumns]int{520, 520, 520, 544, 570, 597, 623, 650}},
+	{20.420, [kouColumns]int{601, 617, 632, 647, 662, 677, 693, 708}},
+	{22.462, [kouColumns]int{678, 699, 721, 745, 768, 792, 815, 838}},
+	{24.504, [kouColumns]int{708, 733, 757, 782, 806, 831, 856, 880}},
+	{26.546, [kouColumns]int{745, 771, 797, 823, 849, 875, 900, 926}},
+	{28.588, [kouColumns]int{788, 814, 841, 868, 896, 923, 950, 978}},
+	{30.630, [kouColumns]int{846, 874, 902, 931, 959, 987, 1015, 1043}},
+	{32.672, [kouColumns]int{914, 944, 975, 1005, 1036, 1066, 1096, 1127}},
+	{35.735, [kouColumns]int{1312, 1336, 1360, 1385, 1409, 1434, 1458, 1482}},
+	{38.798, [kouColumns]int{1521, 1526, 1526, 1538, 1555, 1555, 1555, 1583}},
+	{41.861, [kouColumns]int{2621, 2645, 2669, 2693, 2716, 2740, 2764, 2788}},
+	{45.945, [kouColumns]int{3495, 3527, 3559, 3590, 3622, 3654, 3685, 3717}},
+}
+
+// bonusOtsuThresholds 乙欄の算出率の表 (前月の社会保険料等控除後の給与等の金額の「以上」、千円)
+var bonusOtsuThresholds = []struct {
+	Rate float64
+	Min  int
+}{
+	{10.210, 0},
+	{20.420, 222},
+	{30.630, 293},
+	{38.798, 524},
+	{45.945, 1118},
+}
+
+// SeedBonusWithholdingTaxRates は、賞与に対する源泉徴収税額の算出率の表をDBへ保存
+func SeedBonusWithholdingTaxRates(db *gorm.DB) error {
+	for dependents := 0; dependents < kouColumns; dependents++ {
+		for i, row := range bonusKouThresholds {
+			maxAmt := math.MaxInt32
+			if i+1 < len(bonusKouThresholds) {
+				maxAmt = bonusKouThresholds[i+1].Min[dependents] * 1000
+			}
+			record := models.BonusWithholdingTaxRate{
+				Year:       bonusWithholdingTableYear,
+				TaxTable:   models.TaxTableKou,
+				Dependents: dependents,
+				MinAmount:  row.Min[dependents] * 1000,
+				MaxAmount:  maxAmt,
+				Rate:       row.Rate,
+			}
+			if err := saveBonusWithholdingTaxRate(db, record); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, row := range bonusOtsuThresholds {
+		maxAmt := math.MaxInt32
+		if i+1 < len(bonusOtsuThresholds) {
+			maxAmt = bonusOtsuThresholds[i+1].Min * 1000
+		}
+		record := models.BonusWithholdingTaxRate{
+			Year:      bonusWithholdingTableYear,
+			TaxTable:  models.TaxTableOtsu,
+			MinAmount: row.Min * 1000,
+			MaxAmount: maxAmt,
+			Rate:      row.Rate,
+		}
+		if err := saveBonusWithholdingTaxRate(db, record); err != nil {
+			return err
+		}
+	}
+
+	log.Println("Finished seeding bonus withholding tax rates.")
+	return nil
+}
+
+// saveBonusWithholdingTaxRate 算出率の表の1マス分を作成 (登録済みの場合は率と上限を更新)
+func saveBonusWithholdingTaxRate(db *gorm.DB, record models.BonusWithholdingTaxRate) error {
+	// 扶養親族等の数・金額の0も条件に含めるため、構造体ではなく文字列で条件を指定する
+	var existing models.BonusWithholdingTaxRate
+	return db.Where("year = ? AND tax_table = ? AND dependents = ? AND min_amount = ?",
+		record.Year, record.TaxTable, record.Dependents, record.MinAmount).
+		Attrs(record).
+		Assign(map[string]interface{}{"max_amount": record.MaxAmount, "rate": record.Rate}).
+		FirstOrCreate(&existing).Error
+}
@@ -0,0 +1,60 @@
+package seed
+
+import "testing"
+
+// bonusKouRate 甲欄の算出率の表から前月の給与等の金額に対応する率を求める
+func bonusKouRate(prev, dependents int) float64 {
+	rate := bonusKouThresholds[0].Rate
+	for _, row := range bonusKouThresholds {
+		if prev >= row.Min[dependents]*1000 {
+			rate = row.Rate
+		}
+	}
+	return rate
+}
+
+func TestBonusKouThresholdsAreAscending(t *testing.T) {
+	for dependents := 0; dependents < kouColumns; dependents++ {
+		for i := 1; i < len(bonusKouThresholds); i++ {
+			prev, cur := bonusKouThresholds[i-1], bonusKouThresholds[i]
+			if cur.Rate <= prev.Rate {
+				t.Errorf("rate at row %d (%v) is not greater than %v", i, cur.Rate, prev.Rate)
+			}
+			if cur.Min[dependents] <= prev.Min[dependents] {
+				t.Errorf("dependents %d: threshold at rate %v (%d) is not greater than %d",
+					dependents, cur.Rate, cur.Min[dependents], prev.Min[dependents])
+			}
+		}
+	}
+	for i := 1; i < len(bonusOtsuThresholds); i++ {
+		if bonusOtsuThresholds[i].Min <= bonusOtsuThresholds[i-1].Min {
+			t.Errorf("otsu threshold at row %d is not ascending", i)
+		}
+	}
+}
+
+func TestBonusKouRate(t *testing.T) {
+	tests := []struct {
+		prev       int
+		dependents int
+		want       float64
+	}{
+		{67999, 0, 0},
+		{68000, 0, 2.042},
+		{250000, 0, 4.084},
+		{300000, 0, 8.168},
+		{300000, 1, 6.126},
+		{300000, 2, 4.084},
+		{300000, 4, 4.084},
+		{500000, 2, 16.336},
+		{520000, 2, 18.378},
+		{1000000, 7, 28.588},
+		{3495000, 0, 45.945},
+		{3494999, 0, 41.861},
+	}
+	for _, tt := range tests {
+		if got := bonusKouRate(tt.prev, tt.dependents); got != tt.want {
+			t.Errorf("bonusKouRate(%d, %d) = %v, want %v", tt.prev, tt.dependents, got, tt.want)
+		}
+	}
+}
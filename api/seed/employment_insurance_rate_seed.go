@@ -0,0 +1,80 @@
+package seed
+
+import (
+	"log"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// employmentInsurancePeriod 同じ雇用保険料率が適用される期間 (料率は1000分率)
+type employmentInsurancePeriod struct {
+	FromYear, FromMonth int
+	ToYear, ToMonth     int
+	Rates               map[string][2]float64 // 事業の種類 -> {労働者負担, 事業主負担}
+}
+
+// employmentInsurancePeriods 年度ごとの雇用保険料率 (令和4年度は10月に改定)
+// 最新の年度より後の月は最新の料率を使用する (employmentRatePeriod)
+var employmentInsurancePeriods = []employmentInsurancePeriod{
+	{2021, 4, 2022, 3, map[string][2]float64{
+		models.IndustryGeneral:      {3, 6},
+		models.IndustryAgriculture:  {4, 7},
+		models.IndustryConstruction: {4, 8},
+	}},
+	{2022, 4, 2022, 9, map[string][2]float64{
+		models.IndustryGeneral:      {3, 6.5},
+		models.IndustryAgriculture:  {4, 7.5},
+		models.IndustryConstruction: {4, 8.5},
+	}},
+	{2022, 10, 2023, 3, map[string][2]float64{
+		models.IndustryGeneral:      {5, 8.5},
+		models.IndustryAgriculture:  {6, 9.5},
+		models.IndustryConstruction: {6, 10.5},
+	}},
+	{2023, 4, 2024, 3, map[string][2]float64{
+		models.IndustryGeneral:      {6, 9.5},
+		models.IndustryAgriculture:  {7, 10.5},
+		models.IndustryConstruction: {7, 11.5},
+	}},
+	{2024, 4, 2025, 3, map[string][2]float64{
+		models.IndustryGeneral:      {6, 9.5},
+		models.IndustryAgriculture:  {7, 10.5},
+		models.IndustryConstruction: {7, 11.5},
+	}},
+	{2025, 4, 2026, 3, map[string][2]float64{
+		models.IndustryGeneral:      {5.5, 9},
+		models.IndustryAgriculture:  {6.5, 10},
+		models.IndustryConstruction: {6.5, 11},
+	}},
+	{2026, 4, 2027, 3, map[string][2]float64{
+		models.IndustryGeneral:      {5, 8.5},
+		models.IndustryAgriculture:  {6, 9.5},
+		models.IndustryConstruction: {6, 10.5},
+	}},
+}
+
+// SeedEmploymentInsuranceRates は、年度ごとの雇用保険料率をシードデータとしてDBへ保存
+func SeedEmploymentInsuranceRates(db *gorm.DB) error {
+	for _, period := range employmentInsurancePeriods {
+		for industry, rates := range period.Rates {
+			var record models.EmploymentInsuranceRate
+			result := db.Where(&models.EmploymentInsuranceRate{
+				IndustryClass: industry,
+				FromYear:      period.FromYear,
+				FromMonth:     period.FromMonth,
+			}).Attrs(models.EmploymentInsuranceRate{
+				EmployeeRate: rates[0] / 1000,
+				EmployerRate: rates[1] / 1000,
+				ToYear:       period.ToYear,
+				ToMonth:      period.ToMonth,
+			}).FirstOrCreate(&record)
+			if result.Error != nil {
+				log.Printf("failed to create employment insurance rate %s %d/%d: %v", industry, period.FromYear, period.FromMonth, result.Error)
+				return result.Error
+			}
+		}
+		log.Printf("Seeded employment insurance rates from %d/%d", period.FromYear, period.FromMonth)
+	}
+	return nil
+}
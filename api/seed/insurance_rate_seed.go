@@ -204,6 +204,10 @@ func SeedInsuranceRates(db *gorm.DB) error {
 						HealthHalfWithCare:  hHalfWithCare,
 						ToYear:              toYear,
 						ToMonth:             toMonth,
+					}).Assign(models.HealthInsuranceRate{
+						// 既に登録済みの行にも保険料率を設定する
+						HealthRateNoCare:   healthNoCare,
+						HealthRateWithCare: healthWithCare,
 					}).FirstOrCreate(&hRecord)
 					if hResult.Error != nil {
 						log.Printf("failed to create health record for sheet %s row %d: %v", sheetName, rowIdx, hResult.Error)
@@ -229,6 +233,8 @@ func SeedInsuranceRates(db *gorm.DB) error {
 							PensionHalf:      pHalf,
 							ToYear:           toYear,
 							ToMonth:          toMonth,
+						}).Assign(models.PensionInsuranceRate{
+							PensionRate: pensionRate,
 						}).FirstOrCreate(&pRecord)
 						if pResult.Error != nil {
 							log.Printf("failed to create pension record for sheet %s row %d: %v", sheetName, rowIdx, pResult.Error)
@@ -252,6 +258,10 @@ func SeedInsuranceRates(db *gorm.DB) error {
 						HealthHalfWithCare:  hHalfWithCare,
 						ToYear:              toYear,
 						ToMonth:             toMonth,
+					}).Assign(models.HealthInsuranceRate{
+						// 既に登録済みの行にも保険料率を設定する
+						HealthRateNoCare:   healthNoCare,
+						HealthRateWithCare: healthWithCare,
 					}).FirstOrCreate(&hRecord)
 					if hResult.Error != nil {
 						log.Printf("failed to create health record for sheet %s row %d: %v", sheetName, rowIdx, hResult.Error)
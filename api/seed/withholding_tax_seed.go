@@ -0,0 +1,189 @@
+package seed
+
+import (
+	"embed"
+	"fmt"
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+	"log"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 国税庁が公表する源泉徴収税額表 (月額表) を所定の形式に書き写したExcelファイルをembed
+// withholding_tax ディレクトリに withholding_tax_<西暦>.xlsx として置き、その年分の税額表として登録する
+//
+//go:embed withholding_tax
+var withholdingTaxFiles embed.FS
+
+// withholdingTaxDir 源泉徴収税額表のExcelファイルを置くディレクトリ
+const withholdingTaxDir = "withholding_tax"
+
+// withholdingTaxFilePattern 源泉徴収税額表のファイル名 (西暦を含む)
+var withholdingTaxFilePattern = regexp.MustCompile(`^withholding_tax_(\d{4})\.xlsx$`)
+
+const (
+	// withholdingSheet 月額表のシート名
+	withholdingSheet = "月額表"
+	// withholdingStartRow 税額が書かれた行の開始位置
+	withholdingStartRow = 5
+	// kouColumns 甲欄の列数 (扶養親族等の数 0〜7人)
+	kouColumns = 8
+	// bonusWithholdingSheet 賞与に対する源泉徴収税額の算出率の表のシート名
+	bonusWithholdingSheet = "賞与"
+)
+
+// SeedWithholdingTaxRates は、埋め込みの源泉徴収税額表のExcelファイルをすべて読み込み、DBへ保存
+// 月額表のシートは以上・未満・甲欄 (扶養親族等0〜7人)・乙欄の11列
+// 賞与のシートは任意で、算出率の表が改正された年分の表を登録する場合に使用する
+func SeedWithholdingTaxRates(db *gorm.DB) error {
+	entries, err := withholdingTaxFiles.ReadDir(withholdingTaxDir)
+	if err != nil {
+		return fmt.Errorf("failed to read embedded withholding tax tables: %v", err)
+	}
+
+	seeded := 0
+	for _, entry := range entries {
+		match := withholdingTaxFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		year, _ := strconv.Atoi(match[1])
+		fileName := entry.Name()
+
+		fData, err := withholdingTaxFiles.Open(path.Join(withholdingTaxDir, fileName))
+		if err != nil {
+			return fmt.Errorf("failed to open embedded Excel file %s: %v", fileName, err)
+		}
+		f, err := excelize.OpenReader(fData)
+		fData.Close()
+		if err != nil {
+			return fmt.Errorf("failed to open Excel file %s: %v", fileName, err)
+		}
+
+		rows, err := f.GetRows(withholdingSheet)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("failed to get rows in file %s: %v", fileName, err)
+		}
+		// 賞与のシートがない場合は算出率の表を登録しない
+		bonusRows, _ := f.GetRows(bonusWithholdingSheet)
+		f.Close()
+
+		for rowIdx := withholdingStartRow; rowIdx <= len(rows); rowIdx++ {
+			rowData := rows[rowIdx-1]
+			// 以上・未満・甲欄8列・乙欄の11列
+			if len(rowData) < 3+kouColumns {
+				continue
+			}
+
+			minAmt, err1 := strconv.Atoi(rmComma(strings.TrimSpace(rowData[0])))
+			maxAmt, err2 := strconv.Atoi(rmComma(strings.TrimSpace(rowData[1])))
+			if err1 != nil || err2 != nil {
+				log.Printf("failed to parse amounts in file %s row %d", fileName, rowIdx)
+				continue
+			}
+
+			for dependents := 0; dependents <= kouColumns; dependents++ {
+				tax, err := strconv.Atoi(rmComma(strings.TrimSpace(rowData[2+dependents])))
+				if err != nil {
+					log.Printf("failed to parse tax in file %s row %d: %v", fileName, rowIdx, err)
+					continue
+				}
+
+				// 最後の列は乙欄
+				rate := models.WithholdingTaxRate{
+					Year:       year,
+					TaxTable:   models.TaxTableKou,
+					Dependents: dependents,
+					MinAmount:  minAmt,
+					MaxAmount:  maxAmt,
+					Tax:        tax,
+				}
+				if dependents == kouColumns {
+					rate.TaxTable = models.TaxTableOtsu
+					rate.Dependents = 0
+				}
+
+				// 扶養親族等の数の0も条件に含めるため、構造体ではなく文字列で条件を指定する
+				var record models.WithholdingTaxRate
+				result := db.Where("year = ? AND tax_table = ? AND dependents = ? AND min_amount = ?",
+					rate.Year, rate.TaxTable, rate.Dependents, rate.MinAmount).
+					Attrs(rate).
+					FirstOrCreate(&record)
+				if result.Error != nil {
+					log.Printf("failed to create withholding tax record for file %s row %d: %v", fileName, rowIdx, result.Error)
+				}
+			}
+		}
+		seedBonusWithholdingTaxRates(db, bonusRows, fileName, year)
+		log.Printf("Finished processing file %s.", fileName)
+		seeded++
+	}
+
+	// 甲欄は電算機計算の特例で求められるが、乙欄は月額表がないと税額を求められない
+	if seeded == 0 {
+		log.Printf("warning: no withholding tax table in seed/%s; payroll for otsu employees fails until the NTA tables are added.", withholdingTaxDir)
+	}
+	return nil
+}
+
+// seedBonusWithholdingTaxRates 賞与の算出率の表を保存する
+// 各行は率と、扶養親族等の数 (0〜7人) ごと・乙欄の前月の給与等の金額の以上・未満の組 (未満が空欄の場合は上限なし)
+func seedBonusWithholdingTaxRates(db *gorm.DB, rows [][]string, fileName string, year int) {
+	for rowIdx := withholdingStartRow; rowIdx <= len(rows); rowIdx++ {
+		rowData := rows[rowIdx-1]
+		if len(rowData) == 0 {
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rowData[0]), 64)
+		if err != nil {
+			log.Printf("failed to parse bonus rate in file %s row %d: %v", fileName, rowIdx, err)
+			continue
+		}
+
+		for col := 0; col <= kouColumns; col++ {
+			minIdx, maxIdx := 1+col*2, 2+col*2
+			if len(rowData) <= minIdx || strings.TrimSpace(rowData[minIdx]) == "" {
+				continue
+			}
+
+			minAmt, err := strconv.Atoi(rmComma(strings.TrimSpace(rowData[minIdx])))
+			if err != nil {
+				log.Printf("failed to parse bonus amount in file %s row %d: %v", fileName, rowIdx, err)
+				continue
+			}
+			maxAmt := math.MaxInt32
+			if len(rowData) > maxIdx && strings.TrimSpace(rowData[maxIdx]) != "" {
+				if maxAmt, err = strconv.Atoi(rmComma(strings.TrimSpace(rowData[maxIdx]))); err != nil {
+					log.Printf("failed to parse bonus amount in file %s row %d: %v", fileName, rowIdx, err)
+					continue
+				}
+			}
+
+			// 最後の組は乙欄
+			record := models.BonusWithholdingTaxRate{
+				Year:       year,
+				TaxTable:   models.TaxTableKou,
+				Dependents: col,
+				MinAmount:  minAmt,
+			}
+			if col == kouColumns {
+				record.TaxTable = models.TaxTableOtsu
+				record.Dependents = 0
+			}
+
+			record.MaxAmount = maxAmt
+			record.Rate = rate
+
+			if err := saveBonusWithholdingTaxRate(db, record); err != nil {
+				log.Printf("failed to create bonus withholding tax record for file %s row %d: %v", fileName, rowIdx, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"golang.org/x/text/encoding/japanese"
+	"gorm.io/gorm"
+)
+
+// zenginSalaryTransfer 全銀フォーマットの種別コード (11:給与振込)
+const zenginSalaryTransfer = "11"
+
+// BankTransferEmployeeError 振込データを作成できない従業員と、その理由
+type BankTransferEmployeeError struct {
+	EmployeeID   uint     `json:"employee_id"`
+	EmployeeName string   `json:"employee_name"`
+	Errors       []string `json:"errors"`
+}
+
+// BankTransferValidationError 振込元・振込先の情報に不備があり振込データを作成できない
+type BankTransferValidationError struct {
+	Remitter  []string                    `json:"remitter,omitempty"`
+	Employees []BankTransferEmployeeError `json:"employees,omitempty"`
+}
+
+func (e *BankTransferValidationError) Error() string {
+	return fmt.Sprintf("bank transfer data is invalid (remitter: %d, employees: %d)", len(e.Remitter), len(e.Employees))
+}
+
+// bankTransfer 振込データの1件 (名義は変換済みの半角カナ)
+type bankTransfer struct {
+	Employee   models.Employee
+	HolderKana string
+	Amount     int64
+}
+
+// ExportBankTransferFile 指定した年・月の差引支給額を、全銀フォーマットの給与振込データ (Shift_JIS, 1レコード120バイト) にする
+// 差引支給額は締めた給与計算の給与明細のもので、締めていない月は ErrPayrollRunNotLocked を返す
+// 差引支給額が0円の従業員は含めない。不備がある場合は *BankTransferValidationError を返す
+func ExportBankTransferFile(db *gorm.DB, companyID uint, year, month int, payDate time.Time) ([]byte, error) {
+	invalid := &BankTransferValidationError{}
+
+	var remitter models.CompanyRemitter
+	err := db.Where("company_id = ?", companyID).First(&remitter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		invalid.Remitter = append(invalid.Remitter, "remitter profile is not registered")
+	} else if err != nil {
+		return nil, err
+	}
+
+	var remitterName, bankName, branchName string
+	if err == nil {
+		if remitterName, err = zenginKana(remitter.RequesterName); err != nil {
+			invalid.Remitter = append(invalid.Remitter, "requester name "+err.Error())
+		}
+		if bankName, err = zenginKana(remitter.BankName); err != nil {
+			invalid.Remitter = append(invalid.Remitter, "bank name "+err.Error())
+		}
+		if branchName, err = zenginKana(remitter.BranchName); err != nil {
+			invalid.Remitter = append(invalid.Remitter, "branch name "+err.Error())
+		}
+	}
+
+	// 振込額は締めた給与計算の給与明細から求める (締める前の月は再計算で金額が変わりうるため作成しない)
+	var run models.PayrollRun
+	err = db.Where("company_id = ? AND year = ? AND month = ? AND status = ?", companyID, year, month, models.PayrollRunLocked).
+		First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: lock the payroll run for %d/%d before exporting bank transfer data", ErrPayrollRunNotLocked, year, month)
+	} else if err != nil {
+		return nil, err
+	}
+	payslips, err := lockedCompanyPayroll(db, run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var employees []models.Employee
+	if err := db.Where("company_id = ?", companyID).Find(&employees).Error; err != nil {
+		return nil, err
+	}
+	employeeByID := make(map[uint]models.Employee, len(employees))
+	for _, emp := range employees {
+		employeeByID[emp.ID] = emp
+	}
+
+	var transfers []bankTransfer
+	for _, r := range payslips {
+		if r.Error != "" {
+			invalid.Employees = append(invalid.Employees, BankTransferEmployeeError{
+				EmployeeID: r.EmployeeID, EmployeeName: r.EmployeeName, Errors: []string{r.Error},
+			})
+			continue
+		}
+
+		amount := int64(math.Round(r.Payroll.NetSalary))
+		if amount == 0 {
+			continue
+		}
+
+		emp, ok := employeeByID[r.EmployeeID]
+		if !ok {
+			continue
+		}
+		transfer, errs := validateBankTransfer(emp, amount)
+		if len(errs) > 0 {
+			invalid.Employees = append(invalid.Employees, BankTransferEmployeeError{
+				EmployeeID: emp.ID, EmployeeName: emp.Name, Errors: errs,
+			})
+			continue
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if len(invalid.Remitter) > 0 || len(invalid.Employees) > 0 {
+		return nil, invalid
+	}
+
+	records := zenginRecords(remitter, remitterName, bankName, branchName, payDate, transfers)
+	return japanese.ShiftJIS.NewEncoder().Bytes([]byte(strings.Join(records, "\r\n") + "\r\n"))
+}
+
+// zenginRecords ヘッダー・データ・トレーラー・エンドの各レコード (それぞれ半角120文字)
+// 名称は zenginKana で半角カナに変換済みのものを渡す
+func zenginRecords(remitter models.CompanyRemitter, remitterName, bankName, branchName string, payDate time.Time, transfers []bankTransfer) []string {
+	records := make([]string, 0, len(transfers)+3)
+	records = append(records, "1"+
+		zenginSalaryTransfer+
+		"0"+
+		zenginNumber(remitter.RequesterCode, 10)+
+		zenginText(remitterName, 40)+
+		payDate.Format("0102")+
+		zenginNumber(remitter.BankCode, 4)+
+		zenginText(bankName, 15)+
+		zenginNumber(remitter.BranchCode, 3)+
+		zenginText(branchName, 15)+
+		zenginAccountType(remitter.AccountType)+
+		zenginNumber(remitter.AccountNumber, 7)+
+		zenginText("", 17))
+
+	var total int64
+	for _, t := range transfers {
+		total += t.Amount
+		records = append(records, "2"+
+			zenginNumber(t.Employee.BankCode, 4)+
+			zenginText("", 15)+
+			zenginNumber(t.Employee.BranchCode, 3)+
+			zenginText("", 15)+
+			zenginText("", 4)+
+			zenginAccountType(t.Employee.AccountType)+
+			zenginNumber(t.Employee.AccountNumber, 7)+
+			zenginText(t.HolderKana, 30)+
+			zenginNumber(strconv.FormatInt(t.Amount, 10), 10)+
+			"0"+
+			zenginNumber(strconv.FormatUint(uint64(t.Employee.ID), 10), 10)+
+			zenginText("", 10)+
+			zenginText("", 9))
+	}
+
+	records = append(records, "8"+
+		zenginNumber(strconv.Itoa(len(transfers)), 6)+
+		zenginNumber(strconv.FormatInt(total, 10), 12)+
+		zenginText("", 101))
+	records = append(records, "9"+zenginText("", 119))
+
+	return records
+}
+
+// validateBankTransfer 従業員の振込先口座と振込金額を確認する
+func validateBankTransfer(emp models.Employee, amount int64) (bankTransfer, []string) {
+	var errs []string
+	if amount < 0 {
+		errs = append(errs, "net salary is negative")
+	} else if amount >= 10_000_000_000 {
+		errs = append(errs, "net salary exceeds the maximum transfer amount")
+	}
+	if emp.BankCode == "" {
+		errs = append(errs, "bank code is not registered")
+	}
+	if emp.BranchCode == "" {
+		errs = append(errs, "branch code is not registered")
+	}
+	if emp.AccountType == "" {
+		errs = append(errs, "account type is not registered")
+	}
+	if emp.AccountNumber == "" {
+		errs = append(errs, "account number is not registered")
+	}
+
+	holder, err := zenginKana(emp.AccountHolderKana)
+	if err != nil {
+		errs = append(errs, "account holder "+err.Error())
+	} else if holder == "" {
+		errs = append(errs, "account holder kana is not registered")
+	} else if len([]rune(holder)) > 30 {
+		errs = append(errs, "account holder kana must be 30 characters or fewer")
+	}
+
+	return bankTransfer{Employee: emp, HolderKana: holder, Amount: amount}, errs
+}
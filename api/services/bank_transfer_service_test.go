@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestZenginKana(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"ヤマダ タロウ", "ﾔﾏﾀﾞ ﾀﾛｳ", false},
+		{"やまだ はなこ", "ﾔﾏﾀﾞ ﾊﾅｺ", false},
+		{"パーク", "ﾊﾟ-ｸ", false},
+		{"キャッシュ", "ｷﾔﾂｼﾕ", false},
+		{"ｶ)ｴｰﾋﾞｰｼｰ", "ｶ)ｴ-ﾋﾞ-ｼ-", false},
+		{"abc 123", "ABC 123", false},
+		{" ﾀﾅｶ ", "ﾀﾅｶ", false},
+		{"山田", "", true},
+		{"ﾀﾅｶ!", "", true},
+	}
+	for _, tt := range tests {
+		got, err := zenginKana(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("zenginKana(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("zenginKana(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestZenginFields(t *testing.T) {
+	if got := zenginNumber("123", 7); got != "0000123" {
+		t.Errorf("zenginNumber pads to %q", got)
+	}
+	if got := zenginNumber("12345678", 7); got != "2345678" {
+		t.Errorf("zenginNumber truncates to %q", got)
+	}
+	if got := zenginText("ﾀﾅｶ", 5); got != "ﾀﾅｶ  " {
+		t.Errorf("zenginText pads to %q", got)
+	}
+	if got := zenginText("ﾀﾅｶﾀﾛｳ", 5); got != "ﾀﾅｶﾀﾛ" {
+		t.Errorf("zenginText truncates to %q", got)
+	}
+}
+
+func TestZenginRecordWidths(t *testing.T) {
+	remitter := models.CompanyRemitter{
+		RequesterCode: "1234567890",
+		BankCode:      "0001",
+		BranchCode:    "001",
+		AccountType:   models.AccountTypeOrdinary,
+		AccountNumber: "1234567",
+	}
+	transfers := []bankTransfer{
+		{
+			Employee:   models.Employee{ID: 1, BankCode: "0005", BranchCode: "123", AccountType: models.AccountTypeOrdinary, AccountNumber: "7654321"},
+			HolderKana: "ﾔﾏﾀﾞ ﾀﾛｳ",
+			Amount:     250000,
+		},
+		{
+			Employee:   models.Employee{ID: 42, BankCode: "0009", BranchCode: "456", AccountType: models.AccountTypeChecking, AccountNumber: "11"},
+			HolderKana: "ｻﾄｳ ﾊﾅｺ",
+			Amount:     312345,
+		},
+	}
+	payDate := time.Date(2025, time.June, 25, 0, 0, 0, 0, time.Local)
+
+	records := zenginRecords(remitter, "ｶ)ｻﾝﾌﾟﾙ", "ﾐｽﾞﾎ", "ﾄｳｷﾖｳ", payDate, transfers)
+	if len(records) != len(transfers)+3 {
+		t.Fatalf("got %d records, want %d", len(records), len(transfers)+3)
+	}
+
+	encoded := make([]string, len(records))
+	for i, r := range records {
+		b, err := japanese.ShiftJIS.NewEncoder().Bytes([]byte(r))
+		if err != nil {
+			t.Fatalf("record %d cannot be encoded: %v", i, err)
+		}
+		if len(b) != zenginRecordLength {
+			t.Errorf("record %d is %d bytes, want %d: %q", i, len(b), zenginRecordLength, r)
+		}
+		encoded[i] = string(b)
+	}
+
+	tests := []struct {
+		name   string
+		record int
+		from   int // 1始まりの開始位置
+		to     int
+		want   string
+	}{
+		{"ヘッダーのデータ区分", 0, 1, 1, "1"},
+		{"種別コード", 0, 2, 3, "11"},
+		{"委託者コード", 0, 5, 14, "1234567890"},
+		{"振込指定日", 0, 55, 58, "0625"},
+		{"仕向銀行番号", 0, 59, 62, "0001"},
+		{"依頼人の預金種目", 0, 96, 96, "1"},
+		{"依頼人の口座番号", 0, 97, 103, "1234567"},
+		{"データのデータ区分", 1, 1, 1, "2"},
+		{"被仕向銀行番号", 1, 2, 5, "0005"},
+		{"被仕向支店番号", 1, 21, 23, "123"},
+		{"預金種目", 2, 43, 43, "2"},
+		{"口座番号", 2, 44, 50, "0000011"},
+		{"振込金額", 1, 81, 90, "0000250000"},
+		{"新規コード", 1, 91, 91, "0"},
+		{"顧客コード", 2, 92, 101, "0000000042"},
+		{"トレーラーのデータ区分", 3, 1, 1, "8"},
+		{"合計件数", 3, 2, 7, "000002"},
+		{"合計金額", 3, 8, 19, "000000562345"},
+		{"エンドのデータ区分", 4, 1, 1, "9"},
+	}
+	for _, tt := range tests {
+		if got := encoded[tt.record][tt.from-1 : tt.to]; got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// healthBonusAnnualCap 健康保険の標準賞与額の年度 (4月〜翌年3月) の累計上限
+	healthBonusAnnualCap = 5730000
+	// pensionBonusMonthlyCap 厚生年金保険の標準賞与額の1か月あたりの上限
+	pensionBonusMonthlyCap = 1500000
+	// bonusSpecialRuleMultiple 前月の給与の何倍を超える賞与に月額表を用いた計算を行うか
+	bonusSpecialRuleMultiple = 10
+	// bonusSpreadMonths 月額表を用いた計算で賞与を按分する月数 (賞与の計算期間が6か月以下の場合)
+	bonusSpreadMonths = 6
+)
+
+type BonusCalculationResponse struct {
+	EmployeeName                string    `json:"employee_name"`
+	PaidOn                      time.Time `json:"paid_on"`
+	BonusAmount                 float64   `json:"bonus_amount"`
+	StandardBonusAmount         int       `json:"standard_bonus_amount"`         // 標準賞与額 (1,000円未満切り捨て)
+	HealthStandardBonusAmount   int       `json:"health_standard_bonus_amount"`  // 年度の累計上限を適用した健康保険の標準賞与額
+	PensionStandardBonusAmount  int       `json:"pension_standard_bonus_amount"` // 1か月の上限を適用した厚生年金の標準賞与額
+	HealthInsurance             float64   `json:"health_insurance"`
+	EmployerHealthInsurance     float64   `json:"employer_health_insurance"`
+	WithCare                    bool      `json:"with_care"`
+	Pension                     float64   `json:"pension"`
+	EmployerPension             float64   `json:"employer_pension"`
+	EmploymentInsurance         float64   `json:"employment_insurance"`
+	EmployerEmploymentInsurance float64   `json:"employer_employment_insurance"`
+	PreviousMonthTaxableSalary  float64   `json:"previous_month_taxable_salary"`
+	TaxableBonus                float64   `json:"taxable_bonus"`
+	WithholdingRate             float64   `json:"withholding_rate"` // 賞与の金額に乗ずべき率 (%)。月額表を用いて計算した場合は0
+	WithholdingTax              float64   `json:"withholding_tax"`
+	TotalDeductions             float64   `json:"total_deductions"`
+	NetBonus                    float64   `json:"net_bonus"`
+}
+
+// CalculateBonus 賞与から控除する社会保険料と源泉所得税を計算する
+// 健康保険・厚生年金の保険料は、保険料額表と同じ期間の保険料率を標準賞与額に掛けて求める
+func CalculateBonus(db *gorm.DB, bonusID uint) (BonusCalculationResponse, error) {
+	var bonus models.BonusPayment
+	if err := db.First(&bonus, bonusID).Error; err != nil {
+		return BonusCalculationResponse{}, err
+	}
+
+	paidOn := bonus.PaidOn.In(time.Local)
+	year, month := paidOn.Year(), int(paidOn.Month())
+
+	emp, err := loadPayrollEmployee(db, bonus.EmployeeID, year, month)
+	if err != nil {
+		return BonusCalculationResponse{}, err
+	}
+	tables, err := loadRateTables(db, emp.Company, year, month)
+	if err != nil {
+		return BonusCalculationResponse{}, err
+	}
+
+	amount := float64(bonus.Amount)
+	standard := standardBonusAmount(bonus.Amount)
+	resp := BonusCalculationResponse{
+		EmployeeName:        emp.Name,
+		PaidOn:              bonus.PaidOn,
+		BonusAmount:         amount,
+		StandardBonusAmount: standard,
+	}
+
+	// 健康保険 (年度の累計が上限を超える部分は対象外)
+	fiscalYearStart := time.Date(year, time.April, 1, 0, 0, 0, 0, time.Local)
+	if month < 4 {
+		fiscalYearStart = fiscalYearStart.AddDate(-1, 0, 0)
+	}
+	healthPrior, err := priorStandardBonusAmount(db, bonus, fiscalYearStart)
+	if err != nil {
+		return BonusCalculationResponse{}, err
+	}
+	resp.HealthStandardBonusAmount = max(min(standard, healthBonusAnnualCap-healthPrior), 0)
+
+	age := calculateAge(emp.DateOfBirth, year, month)
+	resp.WithCare = age >= careInsuranceMinAge && age < careInsuranceMaxAge
+
+	healthRate, ok := tables.healthRate(resp.WithCare)
+	if !ok {
+		return BonusCalculationResponse{}, errors.New("health insurance rate is not registered for the specified calculation date")
+	}
+	resp.HealthInsurance, resp.EmployerHealthInsurance = bonusPremium(resp.HealthStandardBonusAmount, healthRate)
+
+	// 厚生年金保険 (同じ月の賞与の合計が上限を超える部分は対象外、70歳以上は対象外)
+	if age < pensionMaxAge {
+		monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+		pensionPrior, err := priorStandardBonusAmount(db, bonus, monthStart)
+		if err != nil {
+			return BonusCalculationResponse{}, err
+		}
+		resp.PensionStandardBonusAmount = max(min(standard, pensionBonusMonthlyCap-pensionPrior), 0)
+
+		pensionRate, ok := tables.pensionRate()
+		if !ok {
+			return BonusCalculationResponse{}, errors.New("pension insurance rate is not registered for the specified calculation date")
+		}
+		resp.Pension, resp.EmployerPension = bonusPremium(resp.PensionStandardBonusAmount, pensionRate)
+	}
+
+	// 雇用保険 (標準賞与額ではなく賞与の総額に料率を掛ける)
+	employmentResp, err := calculateEmploymentInsurance(emp, amount, tables)
+	if err != nil {
+		return BonusCalculationResponse{}, err
+	}
+	resp.EmploymentInsurance = employmentResp.EmployeeShare
+	resp.EmployerEmploymentInsurance = employmentResp.EmployerShare
+
+	// 源泉所得税 (前月の社会保険料等控除後の給与等の金額をもとに求める)
+	socialInsurance := resp.HealthInsurance + resp.Pension + resp.EmploymentInsurance
+	resp.TaxableBonus = amount - socialInsurance
+
+	prevYear, prevMonth := addMonths(year, month, -1)
+	prevPayroll, err := PayrollForMonth(db, emp.ID, prevYear, prevMonth)
+	if err != nil {
+		return BonusCalculationResponse{}, err
+	}
+	resp.PreviousMonthTaxableSalary = prevPayroll.TaxableSalary
+
+	tax, rate, err := bonusWithholdingTax(db, resp.TaxableBonus, prevPayroll.TaxableSalary, emp.Dependents, emp.TaxTable, year)
+	if err != nil {
+		return BonusCalculationResponse{}, err
+	}
+	resp.WithholdingRate = rate
+	resp.WithholdingTax = float64(tax)
+
+	resp.TotalDeductions = socialInsurance + resp.WithholdingTax
+	resp.NetBonus = amount - resp.TotalDeductions
+	return resp, nil
+}
+
+// standardBonusAmount 賞与の総額の1,000円未満を切り捨てた標準賞与額
+func standardBonusAmount(amount int) int {
+	return amount / 1000 * 1000
+}
+
+// priorStandardBonusAmount since 以降、対象の賞与より前に支給された賞与の標準賞与額の合計
+func priorStandardBonusAmount(db *gorm.DB, bonus models.BonusPayment, since time.Time) (int, error) {
+	var prior []models.BonusPayment
+	if err := db.Where("employee_id = ? AND paid_on >= ? AND (paid_on < ? OR (paid_on = ? AND id < ?))",
+		bonus.EmployeeID, since, bonus.PaidOn, bonus.PaidOn, bonus.ID).
+		Find(&prior).Error; err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, b := range prior {
+		total += standardBonusAmount(b.Amount)
+	}
+	return total, nil
+}
+
+// bonusPremium 標準賞与額に保険料率 (%) を掛けて、労使の負担額を求める
+// 被保険者負担分は保険料率の2分の1を掛け、50銭以下を切り捨て50銭を超える場合は切り上げる
+func bonusPremium(standard int, rate float64) (employee, employer float64) {
+	if standard == 0 || rate == 0 {
+		return 0, 0
+	}
+	// 浮動小数点の誤差で50銭ちょうどの判定がずれないよう、料率を1000倍した整数で計算する
+	rateMilli := int64(math.Round(rate * 1000))
+	total := float64(int64(standard)*rateMilli) / 100000
+	employee = roundEmployeeShare(float64(int64(standard)*rateMilli) / 200000)
+	return employee, total - employee
+}
+
+// bonusWithholdingTax 賞与に対する源泉徴収税額と適用した率 (%) を求める
+// 前月の給与がない場合や、賞与が前月の給与の10倍を超える場合は月額表を用いて計算する
+func bonusWithholdingTax(db *gorm.DB, taxableBonus, prevTaxable float64, dependents int, taxTable string, year int) (int, float64, error) {
+	if taxableBonus <= 0 {
+		return 0, 0, nil
+	}
+	if taxTable == "" {
+		taxTable = models.TaxTableKou
+	}
+
+	if prevTaxable <= 0 || taxableBonus > prevTaxable*bonusSpecialRuleMultiple {
+		tax, err := spreadBonusWithholdingTax(db, taxableBonus, max(prevTaxable, 0), dependents, taxTable, year)
+		return tax, 0, err
+	}
+
+	tableYear, err := bonusWithholdingTableYear(db, year)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 扶養親族等の数が7人を超える場合は7人の欄を使用する
+	cols := 0
+	if taxTable == models.TaxTableKou {
+		cols = min(dependents, maxTableDependents)
+	}
+
+	prev := int(math.Floor(prevTaxable))
+	var rate models.BonusWithholdingTaxRate
+	if err := db.Where("year = ? AND tax_table = ? AND dependents = ? AND min_amount <= ? AND max_amount > ?",
+		tableYear, taxTable, cols, prev, prev).
+		First(&rate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, 0, errors.New("no bonus withholding tax rate found for the previous month's salary")
+		}
+		return 0, 0, err
+	}
+
+	return int(math.Floor(taxableBonus * rate.Rate / 100)), rate.Rate, nil
+}
+
+// bonusWithholdingTableYear 指定年に適用する賞与の算出率の表の年
+func bonusWithholdingTableYear(db *gorm.DB, year int) (int, error) {
+	var rate models.BonusWithholdingTaxRate
+	if err := db.Where("year <= ?", year).Order("year desc").First(&rate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("no bonus withholding tax table found for the specified year")
+		}
+		return 0, err
+	}
+	return rate.Year, nil
+}
+
+// spreadBonusWithholdingTax 賞与の6分の1を前月の給与に加えた金額の月額表の税額から、
+// 前月の給与に対する税額を差し引いた額の6倍を税額とする
+func spreadBonusWithholdingTax(db *gorm.DB, taxableBonus, prevTaxable float64, dependents int, taxTable string, year int) (int, error) {
+	withBonus, err := CalculateWithholdingTax(db, prevTaxable+taxableBonus/bonusSpreadMonths, dependents, taxTable, year)
+	if err != nil {
+		return 0, err
+	}
+	withoutBonus, err := CalculateWithholdingTax(db, prevTaxable, dependents, taxTable, year)
+	if err != nil {
+		return 0, err
+	}
+	return (withBonus - withoutBonus) * bonusSpreadMonths, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import "testing"
+
+func TestStandardBonusAmount(t *testing.T) {
+	tests := []struct {
+		amount int
+		want   int
+	}{
+		{0, 0},
+		{999, 0},
+		{500000, 500000},
+		{523456, 523000},
+		{1500999, 1500000},
+	}
+	for _, tt := range tests {
+		if got := standardBonusAmount(tt.amount); got != tt.want {
+			t.Errorf("standardBonusAmount(%d) = %d, want %d", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestBonusPremium(t *testing.T) {
+	tests := []struct {
+		name         string
+		standard     int
+		rate         float64
+		wantEmployee float64
+		wantEmployer float64
+	}{
+		{"健康保険", 500000, 9.98, 24950, 24950},
+		{"介護保険第2号被保険者", 123000, 11.58, 7122, 7121.4},
+		{"50銭ちょうどは切り捨て", 10000, 9.99, 499, 500},
+		{"50銭を超える場合は切り上げ", 1000, 9.99, 50, 49.9},
+		{"厚生年金の上限", 1500000, 18.3, 137250, 137250},
+		{"標準賞与額が0", 0, 9.98, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employee, employer := bonusPremium(tt.standard, tt.rate)
+			if employee != tt.wantEmployee {
+				t.Errorf("employee = %v, want %v", employee, tt.wantEmployee)
+			}
+			if diff := employer - tt.wantEmployer; diff > 0.001 || diff < -0.001 {
+				t.Errorf("employer = %v, want %v", employer, tt.wantEmployer)
+			}
+		})
+	}
+}
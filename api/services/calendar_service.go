@@ -0,0 +1,222 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+//go:generate go run ../cmd/national_holidays
+
+// CalendarDay 会社カレンダーの1日
+type CalendarDay struct {
+	Date            time.Time                 `json:"date"`
+	Weekday         int                       `json:"weekday"`
+	WorkingDay      bool                      `json:"working_day"`                // 所定労働日
+	LegalHoliday    bool                      `json:"legal_holiday"`              // 法定休日 (休日労働の割増の対象)
+	NationalHoliday string                    `json:"national_holiday,omitempty"` // 国民の祝日・休日の名称
+	Kind            models.CompanyHolidayKind `json:"kind,omitempty"`             // 会社カレンダーで指定した日の種類
+	Name            string                    `json:"name,omitempty"`
+}
+
+// MonthlyCalendar 会社の指定した年・月のカレンダー
+type MonthlyCalendar struct {
+	Year          int           `json:"year"`
+	Month         int           `json:"month"`
+	ScheduledDays int           `json:"scheduled_days"` // 所定労働日数
+	Days          []CalendarDay `json:"days"`
+}
+
+// companyCalendar 期間内の会社カレンダー (国民の祝日・所定休日の曜日・会社が指定した日)
+type companyCalendar struct {
+	company models.Company
+	entries map[string]models.CompanyHoliday
+	// designatedWeeks 法定休日を日付で指定した週の初日 (その週は曜日による法定休日を適用しない)
+	designatedWeeks map[time.Time]bool
+}
+
+// loadCompanyCalendar from から to までの日を含む週の会社カレンダーを読み込む
+func loadCompanyCalendar(tx *gorm.DB, company models.Company, from, to time.Time) (*companyCalendar, error) {
+	start := weekStart(from, company)
+	end := weekStart(to, company).AddDate(0, 0, 7)
+
+	var holidays []models.CompanyHoliday
+	if err := tx.Where("company_id = ? AND date >= ? AND date < ?", company.ID, start, end).
+		Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+
+	cal := &companyCalendar{
+		company:         company,
+		entries:         make(map[string]models.CompanyHoliday, len(holidays)),
+		designatedWeeks: make(map[time.Time]bool),
+	}
+	for _, h := range holidays {
+		cal.entries[h.Date.Format("2006-01-02")] = h
+		if h.Kind == models.CompanyHolidayLegal {
+			cal.designatedWeeks[weekStart(h.Date, company)] = true
+		}
+	}
+	return cal, nil
+}
+
+// calendarForRanges 勤務の区間にかかる日の会社カレンダー
+func calendarForRanges(tx *gorm.DB, company models.Company, ranges []timeRange) (*companyCalendar, error) {
+	days := eachDay(ranges)
+	if len(days) == 0 {
+		return &companyCalendar{company: company}, nil
+	}
+	return loadCompanyCalendar(tx, company, days[0], days[len(days)-1])
+}
+
+// legalHoliday 指定日が法定休日かどうか
+// 日付で指定された法定休日を優先し、指定のない週は法定休日の曜日を法定休日とする
+func (cal *companyCalendar) legalHoliday(day time.Time) bool {
+	d := day.In(time.Local)
+	if h, ok := cal.entries[d.Format("2006-01-02")]; ok {
+		switch h.Kind {
+		case models.CompanyHolidayLegal:
+			return true
+		case models.CompanyHolidayWorkday:
+			return false
+		}
+	}
+	if cal.designatedWeeks[weekStart(d, cal.company)] {
+		return false
+	}
+	return int(d.Weekday()) == cal.company.LegalHolidayWeekday
+}
+
+// workingDay 指定日が所定労働日かどうか
+func (cal *companyCalendar) workingDay(day time.Time) bool {
+	d := day.In(time.Local)
+	if h, ok := cal.entries[d.Format("2006-01-02")]; ok {
+		return h.Kind == models.CompanyHolidayWorkday
+	}
+	if cal.legalHoliday(d) || cal.company.IsRestWeekday(d.Weekday()) {
+		return false
+	}
+	if _, ok := nationalHolidays[d.Format("2006-01-02")]; ok && !cal.company.WorkOnNationalHolidays {
+		return false
+	}
+	return true
+}
+
+// day 指定日のカレンダー
+func (cal *companyCalendar) day(day time.Time) CalendarDay {
+	d := dateOnly(day)
+	key := d.Format("2006-01-02")
+	cd := CalendarDay{
+		Date:            d,
+		Weekday:         int(d.Weekday()),
+		WorkingDay:      cal.workingDay(d),
+		LegalHoliday:    cal.legalHoliday(d),
+		NationalHoliday: nationalHolidays[key],
+	}
+	if h, ok := cal.entries[key]; ok {
+		cd.Kind = h.Kind
+		cd.Name = h.Name
+	}
+	return cd
+}
+
+// GetCompanyCalendar 会社の指定した年・月のカレンダーと所定労働日数
+func GetCompanyCalendar(db *gorm.DB, company models.Company, year, month int) (MonthlyCalendar, error) {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, -1)
+	cal, err := loadCompanyCalendar(db, company, from, to)
+	if err != nil {
+		return MonthlyCalendar{}, err
+	}
+
+	result := MonthlyCalendar{Year: year, Month: month, Days: []CalendarDay{}}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := cal.day(d)
+		if day.WorkingDay {
+			result.ScheduledDays++
+		}
+		result.Days = append(result.Days, day)
+	}
+	return result, nil
+}
+
+// IsWorkingDay 指定日が会社の所定労働日かどうか
+func IsWorkingDay(db *gorm.DB, company models.Company, date time.Time) (bool, error) {
+	cal, err := loadCompanyCalendar(db, company, date, date)
+	if err != nil {
+		return false, err
+	}
+	return cal.workingDay(date), nil
+}
+
+// SaveCompanyHoliday 会社カレンダーに休日・出勤日を登録 (同じ日の登録は置き換える) し、
+// その週の勤務記録の休日労働・時間外労働を再集計する
+func SaveCompanyHoliday(db *gorm.DB, company models.Company, holiday *models.CompanyHoliday) error {
+	holiday.CompanyID = company.ID
+	holiday.Date = dateOnly(holiday.Date)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing models.CompanyHoliday
+		err := tx.Where("company_id = ? AND date = ?", company.ID, holiday.Date).First(&existing).Error
+		if err == nil {
+			holiday.ID = existing.ID
+			holiday.CreatedAt = existing.CreatedAt
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := tx.Save(holiday).Error; err != nil {
+			return err
+		}
+		return reaggregateCompanyWeek(tx, company, holiday.Date)
+	})
+}
+
+// DeleteCompanyHoliday 会社カレンダーの休日・出勤日を削除し、その週の勤務記録を再集計する
+func DeleteCompanyHoliday(db *gorm.DB, company models.Company, holiday models.CompanyHoliday) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&holiday).Error; err != nil {
+			return err
+		}
+		return reaggregateCompanyWeek(tx, company, holiday.Date)
+	})
+}
+
+// reaggregateCompanyWeek 指定日が属する週 (前日から始まる勤務を含む) の会社の勤務記録を打刻から集計し直す
+// 給与計算が締められた月の勤務記録に影響する場合はエラーになる
+func reaggregateCompanyWeek(tx *gorm.DB, company models.Company, date time.Time) error {
+	from := weekStart(date, company).AddDate(0, 0, -1)
+	to := weekStart(date, company).AddDate(0, 0, 7)
+
+	var records []models.WorkRecord
+	if err := tx.
+		Joins("JOIN employees ON employees.id = work_records.employee_id").
+		Where("employees.company_id = ? AND work_records.date >= ? AND work_records.date < ? AND work_records.clock_in_id IS NOT NULL",
+			company.ID, from, to).
+		Order("work_records.date ASC, work_records.clock_in ASC").
+		Find(&records).Error; err != nil {
+		return err
+	}
+
+	employees := make(map[uint]models.Employee)
+	for _, r := range records {
+		emp, ok := employees[r.EmployeeID]
+		if !ok {
+			if err := tx.First(&emp, r.EmployeeID).Error; err != nil {
+				return err
+			}
+			emp.Company = company
+			employees[r.EmployeeID] = emp
+		}
+
+		var start models.TimeClock
+		if err := tx.First(&start, *r.ClockInID).Error; err != nil {
+			return err
+		}
+		if err := aggregateShift(tx, emp, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+// 内閣府の「国民の祝日について」で公表された祝日・休日
+func TestNationalHolidaysOfYear(t *testing.T) {
+	tests := []struct {
+		year string
+		want map[string]string
+	}{
+		{"2025", map[string]string{
+			"2025-01-01": "元日",
+			"2025-01-13": "成人の日",
+			"2025-02-11": "建国記念の日",
+			"2025-02-23": "天皇誕生日",
+			"2025-02-24": "振替休日",
+			"2025-03-20": "春分の日",
+			"2025-04-29": "昭和の日",
+			"2025-05-03": "憲法記念日",
+			"2025-05-04": "みどりの日",
+			"2025-05-05": "こどもの日",
+			"2025-05-06": "振替休日",
+			"2025-07-21": "海の日",
+			"2025-08-11": "山の日",
+			"2025-09-15": "敬老の日",
+			"2025-09-23": "秋分の日",
+			"2025-10-13": "スポーツの日",
+			"2025-11-03": "文化の日",
+			"2025-11-23": "勤労感謝の日",
+			"2025-11-24": "振替休日",
+		}},
+		{"2026", map[string]string{
+			"2026-01-01": "元日",
+			"2026-01-12": "成人の日",
+			"2026-02-11": "建国記念の日",
+			"2026-02-23": "天皇誕生日",
+			"2026-03-20": "春分の日",
+			"2026-04-29": "昭和の日",
+			"2026-05-03": "憲法記念日",
+			"2026-05-04": "みどりの日",
+			"2026-05-05": "こどもの日",
+			"2026-05-06": "振替休日",
+			"2026-07-20": "海の日",
+			"2026-08-11": "山の日",
+			"2026-09-21": "敬老の日",
+			"2026-09-22": "国民の休日",
+			"2026-09-23": "秋分の日",
+			"2026-10-12": "スポーツの日",
+			"2026-11-03": "文化の日",
+			"2026-11-23": "勤労感謝の日",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.year, func(t *testing.T) {
+			got := make(map[string]string)
+			for date, name := range nationalHolidays {
+				if strings.HasPrefix(date, tt.year+"-") {
+					got[date] = name
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("national holidays of %s = %v, want %v", tt.year, got, tt.want)
+			}
+		})
+	}
+}
+
+// 特例法による祝日の移動と、一度限りの休日
+func TestNationalHolidaysSpecialDates(t *testing.T) {
+	tests := []struct {
+		date    string
+		holiday bool
+	}{
+		{"2019-04-30", true},  // 国民の休日
+		{"2019-05-01", true},  // 天皇の即位の日
+		{"2019-05-02", true},  // 国民の休日
+		{"2019-10-22", true},  // 即位礼正殿の儀の行われる日
+		{"2019-12-23", false}, // 天皇誕生日は令和2年から2月23日
+		{"2020-07-23", true},  // 東京オリンピックに伴う海の日の移動
+		{"2020-07-24", true},  // スポーツの日
+		{"2020-07-20", false},
+		{"2020-08-10", true}, // 山の日
+		{"2020-08-11", false},
+		{"2020-10-12", false},
+		{"2021-07-22", true}, // 海の日
+		{"2021-07-23", true}, // スポーツの日
+		{"2021-08-08", true}, // 山の日
+		{"2021-08-09", true}, // 振替休日
+		{"2021-07-19", false},
+		{"2021-10-11", false},
+		{"2032-09-21", true}, // 敬老の日と秋分の日に挟まれた国民の休日
+	}
+	for _, tt := range tests {
+		if _, got := nationalHolidays[tt.date]; got != tt.holiday {
+			t.Errorf("nationalHolidays[%s] = %v, want %v", tt.date, got, tt.holiday)
+		}
+	}
+}
+
+func TestCompanyCalendarWorkingDay(t *testing.T) {
+	company := models.Company{LegalHolidayWeekday: 0, RestWeekdays: "0,6"}
+	worksOnHolidays := company
+	worksOnHolidays.WorkOnNationalHolidays = true
+	entries := map[string]models.CompanyHoliday{
+		"2025-08-13": {Kind: models.CompanyHolidayClosed},
+		"2025-07-21": {Kind: models.CompanyHolidayWorkday},
+		"2025-06-07": {Kind: models.CompanyHolidayWorkday},
+	}
+
+	tests := []struct {
+		name    string
+		company models.Company
+		date    time.Time
+		want    bool
+	}{
+		{"平日", company, localDate(2025, time.June, 2), true},
+		{"所定休日の土曜", company, localDate(2025, time.June, 14), false},
+		{"法定休日の日曜", company, localDate(2025, time.June, 15), false},
+		{"国民の祝日", company, localDate(2025, time.September, 15), false},
+		{"振替休日", company, localDate(2025, time.November, 24), false},
+		{"祝日を出勤日とする会社", worksOnHolidays, localDate(2025, time.September, 15), true},
+		{"会社休日", company, localDate(2025, time.August, 13), false},
+		{"祝日を出勤日に指定", company, localDate(2025, time.July, 21), true},
+		{"土曜を出勤日に指定", company, localDate(2025, time.June, 7), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := &companyCalendar{company: tt.company, entries: entries}
+			if got := cal.workingDay(tt.date); got != tt.want {
+				t.Errorf("workingDay(%s) = %v, want %v", tt.date.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+// WorkState 打刻の履歴から決まる従業員の勤務状態
+type WorkState string
+
+const (
+	OffDuty WorkState = "off_duty"
+	Working WorkState = "working"
+	OnBreak WorkState = "on_break"
+)
+
+// Label LINEでの返信などに使う表示名
+func (s WorkState) Label() string {
+	switch s {
+	case OffDuty:
+		return "勤務外"
+	case Working:
+		return "勤務中"
+	case OnBreak:
+		return "休憩中"
+	default:
+		return string(s)
+	}
+}
+
+// clockTransitions 各状態で受け付ける打刻と遷移先の状態
+var clockTransitions = map[WorkState]map[models.TimeClockType]WorkState{
+	OffDuty: {
+		models.ClockIn: Working,
+	},
+	Working: {
+		models.ClockOut:   OffDuty,
+		models.BreakBegin: OnBreak,
+	},
+	OnBreak: {
+		models.BreakEnd: Working,
+	},
+}
+
+// ErrInvalidClockTransition 現在の勤務状態では受け付けられない打刻
+var ErrInvalidClockTransition = errors.New("invalid clock transition")
+
+// ClockTransitionError 不正な打刻の詳細 (errors.Is で ErrInvalidClockTransition と判定できる)
+type ClockTransitionError struct {
+	State WorkState
+	Type  models.TimeClockType
+	// Next 遡って打刻した場合に、直後の打刻と矛盾するときに設定される
+	Next *models.TimeClockType
+}
+
+func (e *ClockTransitionError) Error() string {
+	if e.Next != nil {
+		return fmt.Sprintf("%s conflicts with the following %s punch", e.Type, *e.Next)
+	}
+	return fmt.Sprintf("cannot record %s while %s", e.Type, e.State)
+}
+
+func (e *ClockTransitionError) Unwrap() error {
+	return ErrInvalidClockTransition
+}
+
+// stateAfter 指定した打刻を行った直後の勤務状態
+func stateAfter(clockType models.TimeClockType) WorkState {
+	switch clockType {
+	case models.ClockIn, models.BreakEnd:
+		return Working
+	case models.BreakBegin:
+		return OnBreak
+	default:
+		return OffDuty
+	}
+}
+
+// transition 現在の状態に打刻を適用した結果の状態を返す
+func transition(state WorkState, clockType models.TimeClockType) (WorkState, error) {
+	next, ok := clockTransitions[state][clockType]
+	if !ok {
+		return state, &ClockTransitionError{State: state, Type: clockType}
+	}
+	return next, nil
+}
+
+// checkClockTransition 現在の状態で打刻でき、直後の打刻 (遡って打刻した場合) とも矛盾しないかを確認する
+func checkClockTransition(state WorkState, clockType models.TimeClockType, following *models.TimeClockType) error {
+	next, err := transition(state, clockType)
+	if err != nil || following == nil {
+		return err
+	}
+	if _, err := transition(next, *following); err != nil {
+		return &ClockTransitionError{State: state, Type: clockType, Next: following}
+	}
+	return nil
+}
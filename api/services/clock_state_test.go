@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestCheckClockTransition(t *testing.T) {
+	clockIn, clockOut := models.ClockIn, models.ClockOut
+	breakBegin, breakEnd := models.BreakBegin, models.BreakEnd
+
+	tests := []struct {
+		name      string
+		state     WorkState
+		clockType models.TimeClockType
+		following *models.TimeClockType
+		wantErr   bool
+	}{
+		{"勤務外から出勤", OffDuty, models.ClockIn, nil, false},
+		{"勤務中に退勤", Working, models.ClockOut, nil, false},
+		{"勤務中に休憩開始", Working, models.BreakBegin, nil, false},
+		{"休憩中に休憩終了", OnBreak, models.BreakEnd, nil, false},
+		{"勤務中の二重の出勤", Working, models.ClockIn, nil, true},
+		{"勤務外の退勤", OffDuty, models.ClockOut, nil, true},
+		{"勤務外の休憩開始", OffDuty, models.BreakBegin, nil, true},
+		{"休憩中の退勤", OnBreak, models.ClockOut, nil, true},
+		{"休憩中の二重の休憩開始", OnBreak, models.BreakBegin, nil, true},
+		{"勤務中の休憩終了", Working, models.BreakEnd, nil, true},
+		{"遡った出勤の直後に退勤がある", OffDuty, models.ClockIn, &clockOut, false},
+		{"遡った休憩の直後に休憩終了がある", Working, models.BreakBegin, &breakEnd, false},
+		{"遡った出勤の直後に別の出勤がある", OffDuty, models.ClockIn, &clockIn, true},
+		{"遡った退勤の直後に休憩開始がある", Working, models.ClockOut, &breakBegin, true},
+		{"遡った休憩開始の直後に退勤がある", Working, models.BreakBegin, &clockOut, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkClockTransition(tt.state, tt.clockType, tt.following)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkClockTransition(%s, %s) error = %v, wantErr %v", tt.state, tt.clockType, err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, ErrInvalidClockTransition) {
+				t.Errorf("error %v is not ErrInvalidClockTransition", err)
+			}
+			var transitionErr *ClockTransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Fatalf("error %v is not a ClockTransitionError", err)
+			}
+			if transitionErr.State != tt.state || transitionErr.Type != tt.clockType {
+				t.Errorf("error = %+v, want state %s and type %s", transitionErr, tt.state, tt.clockType)
+			}
+		})
+	}
+}
+
+func TestStateAfter(t *testing.T) {
+	tests := []struct {
+		clockType models.TimeClockType
+		want      WorkState
+	}{
+		{models.ClockIn, Working},
+		{models.BreakBegin, OnBreak},
+		{models.BreakEnd, Working},
+		{models.ClockOut, OffDuty},
+	}
+	for _, tt := range tests {
+		if got := stateAfter(tt.clockType); got != tt.want {
+			t.Errorf("stateAfter(%s) = %s, want %s", tt.clockType, got, tt.want)
+		}
+		// 打刻の直後の状態は、その打刻を受け付ける状態からの遷移先と一致する
+		for state, transitions := range clockTransitions {
+			if next, ok := transitions[tt.clockType]; ok && next != tt.want {
+				t.Errorf("transition from %s by %s = %s, but stateAfter = %s", state, tt.clockType, next, tt.want)
+			}
+		}
+	}
+}
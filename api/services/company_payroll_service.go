@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// payrollWorkers 会社全体の給与計算で同時に計算する従業員数の上限
+const payrollWorkers = 8
+
+// CompanyPayrollResult 従業員ごとの計算結果 (計算できなかった場合は Error を設定)
+type CompanyPayrollResult struct {
+	EmployeeID   uint                        `json:"employee_id"`
+	EmployeeName string                      `json:"employee_name"`
+	Payroll      *PayrollCalculationResponse `json:"payroll,omitempty"`
+	Error        string                      `json:"error,omitempty"`
+}
+
+// PayrollTotals 会社全体の合計 (計算できた従業員のみ)
+type PayrollTotals struct {
+	Employees                   int     `json:"employees"`
+	GrossSalary                 float64 `json:"gross_salary"`
+	HealthInsurance             float64 `json:"health_insurance"`
+	Pension                     float64 `json:"pension"`
+	EmploymentInsurance         float64 `json:"employment_insurance"`
+	WithholdingTax              float64 `json:"withholding_tax"`
+	ResidentTax                 float64 `json:"resident_tax"`
+	YearEndAdjustment           float64 `json:"year_end_adjustment"`
+	TotalDeductions             float64 `json:"total_deductions"`
+	NetSalary                   float64 `json:"net_salary"`
+	EmployerHealthInsurance     float64 `json:"employer_health_insurance"`
+	EmployerPension             float64 `json:"employer_pension"`
+	EmployerEmploymentInsurance float64 `json:"employer_employment_insurance"`
+	EmployerInsuranceTotal      float64 `json:"employer_insurance_total"`
+	TotalLaborCost              float64 `json:"total_labor_cost"` // 総支給額+事業主負担の社会保険料
+}
+
+type CompanyPayrollResponse struct {
+	Year      int                    `json:"year"`
+	Month     int                    `json:"month"`
+	Locked    bool                   `json:"locked"` // 給与計算が締められ、確定した給与明細の内容を返しているか
+	Employees []CompanyPayrollResult `json:"employees"`
+	Totals    PayrollTotals          `json:"totals"`
+}
+
+// CalculateCompanyPayroll 会社の全従業員の給与を計算する
+// 保険料額表は一度だけ読み込み、従業員ごとの計算は payrollWorkers 件ずつ並行して行う
+// 給与計算が締められた月は、確定した給与明細の内容を返す
+func CalculateCompanyPayroll(db *gorm.DB, companyID uint, year, month int) (CompanyPayrollResponse, error) {
+	resp := CompanyPayrollResponse{Year: year, Month: month}
+
+	var run models.PayrollRun
+	err := db.Where("company_id = ? AND year = ? AND month = ? AND status = ?", companyID, year, month, models.PayrollRunLocked).
+		First(&run).Error
+	if err == nil {
+		resp.Locked = true
+		resp.Employees, err = lockedCompanyPayroll(db, run.ID)
+		if err != nil {
+			return resp, err
+		}
+		resp.Totals = sumPayrollTotals(resp.Employees)
+		return resp, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return resp, err
+	}
+
+	var company models.Company
+	if err := db.First(&company, companyID).Error; err != nil {
+		return resp, err
+	}
+
+	tables, err := loadRateTables(db, company, year, month)
+	if err != nil {
+		return resp, err
+	}
+
+	employees, err := loadPayrollEmployees(db, companyID, year, month)
+	if err != nil {
+		return resp, err
+	}
+
+	results := make([]CompanyPayrollResult, len(employees))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(payrollWorkers, len(employees)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				emp := employees[i]
+				result := CompanyPayrollResult{EmployeeID: emp.ID, EmployeeName: emp.Name}
+				payroll, err := calculatePayroll(db, emp, tables, year, month)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Payroll = &payroll
+				}
+				results[i] = result
+			}
+		}()
+	}
+	for i := range employees {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	resp.Employees = results
+	resp.Totals = sumPayrollTotals(results)
+	return resp, nil
+}
+
+// lockedCompanyPayroll 締められた給与計算の給与明細から計算結果を取り出す
+func lockedCompanyPayroll(db *gorm.DB, runID uint) ([]CompanyPayrollResult, error) {
+	var payslips []models.Payslip
+	if err := db.Where("payroll_run_id = ?", runID).Order("employee_id ASC").Find(&payslips).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]CompanyPayrollResult, 0, len(payslips))
+	for _, p := range payslips {
+		var payroll PayrollCalculationResponse
+		if err := json.Unmarshal([]byte(p.Snapshot), &payroll); err != nil {
+			return nil, err
+		}
+		results = append(results, CompanyPayrollResult{
+			EmployeeID:   p.EmployeeID,
+			EmployeeName: p.EmployeeName,
+			Payroll:      &payroll,
+		})
+	}
+	return results, nil
+}
+
+func sumPayrollTotals(results []CompanyPayrollResult) PayrollTotals {
+	var t PayrollTotals
+	for _, r := range results {
+		if r.Payroll == nil {
+			continue
+		}
+		p := r.Payroll
+		t.Employees++
+		t.GrossSalary += p.GrossSalary
+		t.HealthInsurance += p.HealthInsurance
+		t.Pension += p.Pension
+		t.EmploymentInsurance += p.EmploymentInsurance
+		t.WithholdingTax += p.WithholdingTax
+		t.ResidentTax += p.ResidentTax
+		t.YearEndAdjustment += p.YearEndAdjustment
+		t.TotalDeductions += p.TotalDeductions
+		t.NetSalary += p.NetSalary
+		t.EmployerHealthInsurance += p.EmployerHealthInsurance
+		t.EmployerPension += p.EmployerPension
+		t.EmployerEmploymentInsurance += p.EmployerEmploymentInsurance
+	}
+	t.EmployerInsuranceTotal = t.EmployerHealthInsurance + t.EmployerPension + t.EmployerEmploymentInsurance
+	t.TotalLaborCost = t.GrossSalary + t.EmployerInsuranceTotal
+	return t
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"math"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+type EmploymentInsuranceResponse struct {
+	EmployeeName  string  `json:"employee_name"`
+	CompanyName   string  `json:"company_name"`
+	IndustryClass string  `json:"industry_class"`
+	Wages         float64 `json:"wages"`
+	EmployeeRate  float64 `json:"employee_rate"`
+	EmployerRate  float64 `json:"employer_rate"`
+	EmployeeShare float64 `json:"employee_share"`
+	EmployerShare float64 `json:"employer_share"`
+}
+
+// CalculateEmploymentInsurance 指定された年・月の賃金総額をもとに雇用保険料を計算する関数
+func CalculateEmploymentInsurance(db *gorm.DB, employeeID uint, year, month int) (EmploymentInsuranceResponse, error) {
+	emp, err := loadPayrollEmployee(db, employeeID, year, month)
+	if err != nil {
+		return EmploymentInsuranceResponse{}, err
+	}
+
+	earnings, err := calculateEarnings(db, emp, year, month)
+	if err != nil {
+		return EmploymentInsuranceResponse{}, err
+	}
+
+	tables, err := loadRateTables(db, emp.Company, year, month)
+	if err != nil {
+		return EmploymentInsuranceResponse{}, err
+	}
+
+	return calculateEmploymentInsurance(emp, earnings.Gross(), tables)
+}
+
+// employmentRatePeriod 事業の種類・指定した年・月に適用される雇用保険料率に絞り込む
+// 指定した月を含む期間の料率が登録されていない場合は、源泉徴収税額表と同じく、それより前で最新の料率を使用する
+func employmentRatePeriod(db *gorm.DB, industry string, year, month int) *gorm.DB {
+	return db.Where(
+		"industry_class = ? AND (from_year < ? OR (from_year = ? AND from_month <= ?))",
+		industry,
+		year, year, month,
+	).Order("from_year desc, from_month desc")
+}
+
+// calculateEmploymentInsurance 賃金総額に事業の種類・年度に応じた料率を掛けて労使それぞれの負担額を求める
+func calculateEmploymentInsurance(emp models.Employee, wages float64, tables rateTables) (EmploymentInsuranceResponse, error) {
+	rate := tables.Employment
+	if rate == nil {
+		return EmploymentInsuranceResponse{}, errors.New("employment insurance rate is not registered for the specified calculation date")
+	}
+
+	resp := EmploymentInsuranceResponse{
+		EmployeeName:  emp.Name,
+		CompanyName:   emp.Company.Name,
+		IndustryClass: rate.IndustryClass,
+		Wages:         wages,
+		EmployeeRate:  rate.EmployeeRate,
+		EmployerRate:  rate.EmployerRate,
+		EmployeeShare: roundEmployeeShare(wages * rate.EmployeeRate),
+		EmployerShare: wages * rate.EmployerRate,
+	}
+	return resp, nil
+}
+
+// roundEmployeeShare 給与から控除する被保険者負担分の端数処理 (50銭以下切り捨て、50銭を超える場合は切り上げ)
+func roundEmployeeShare(amount float64) float64 {
+	yen := math.Floor(amount)
+	if amount-yen > 0.5 {
+		return yen + 1
+	}
+	return yen
+}
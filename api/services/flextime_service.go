@@ -0,0 +1,466 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// flextimeMonthlyWeeklyMinutes 清算期間が1か月を超える場合に、各月で週平均の労働時間が超えてはならない時間 (50時間)
+const flextimeMonthlyWeeklyMinutes = 50 * 60
+
+// FlextimeMonth 清算期間の各月の労働時間
+type FlextimeMonth struct {
+	Year              int   `json:"year"`
+	Month             int   `json:"month"`
+	ScheduledDays     int   `json:"scheduled_days"`      // 会社カレンダーの所定労働日数
+	RequiredMinutes   int64 `json:"required_minutes"`    // 所定労働日数×標準となる1日の労働時間
+	LegalLimitMinutes int64 `json:"legal_limit_minutes"` // 法定労働時間の総枠 (週の法定労働時間×暦日数÷7)
+	WorkMinutes       int64 `json:"work_minutes"`        // 実労働時間 (法定休日労働を除く)
+	LeaveMinutes      int64 `json:"leave_minutes"`       // 有給休暇・特別休暇の時間 (総労働時間に充当する)
+	OvertimeMinutes   int64 `json:"overtime_minutes"`    // この月の給与で支払う時間外労働
+}
+
+// CoreTimeViolation コアタイムに勤務していない日
+type CoreTimeViolation struct {
+	Date              time.Time `json:"date"`
+	LateMinutes       int64     `json:"late_minutes"`        // コアタイムの開始に遅れた時間
+	EarlyLeaveMinutes int64     `json:"early_leave_minutes"` // コアタイムの終了より前に退勤した時間
+	Absent            bool      `json:"absent"`              // 所定労働日に勤務・休暇の記録がない
+}
+
+// FlextimeSettlement フレックスタイム制の清算期間の労働時間の清算
+type FlextimeSettlement struct {
+	EmployeeID         uint                `json:"employee_id"`
+	StartYear          int                 `json:"start_year"`
+	StartMonth         int                 `json:"start_month"`
+	EndYear            int                 `json:"end_year"`
+	EndMonth           int                 `json:"end_month"`
+	Months             []FlextimeMonth     `json:"months"`
+	CarriedInMinutes   int64               `json:"carried_in_minutes"`  // 前の清算期間から繰り越した不足時間
+	RequiredMinutes    int64               `json:"required_minutes"`    // 総労働時間 (繰り越した不足時間を含む)
+	LegalLimitMinutes  int64               `json:"legal_limit_minutes"` // 法定労働時間の総枠
+	ActualMinutes      int64               `json:"actual_minutes"`      // 実労働時間と休暇の時間の合計
+	SurplusMinutes     int64               `json:"surplus_minutes"`     // 総労働時間を超えた時間
+	DeficitMinutes     int64               `json:"deficit_minutes"`     // 総労働時間に満たない時間
+	OvertimeMinutes    int64               `json:"overtime_minutes"`    // 法定労働時間の総枠 (各月の週平均50時間を含む) を超えた時間外労働
+	CarryOverMinutes   int64               `json:"carry_over_minutes"`  // 不足時間のうち次の清算期間に繰り越す時間
+	DeductionMinutes   int64               `json:"deduction_minutes"`   // 不足時間のうち繰り越せず賃金から控除する時間
+	CoreTimeViolations []CoreTimeViolation `json:"core_time_violations"`
+}
+
+// flextimeSettingFor フレックスタイム制の従業員の設定 (通常の労働時間制なら nil)
+func flextimeSettingFor(tx *gorm.DB, emp models.Employee) (*models.FlextimeSetting, error) {
+	if emp.WorkingTimeSystem != models.WorkingTimeFlextime {
+		return nil, nil
+	}
+	var setting models.FlextimeSetting
+	err := tx.Where("employee_id = ?", emp.ID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// SaveWorkingTimeSystem 従業員の労働時間制とフレックスタイム制の設定を登録し、
+// 変更前後でフレックスタイム制の適用期間となる月以降の勤務記録の時間外労働を再計算する
+func SaveWorkingTimeSystem(db *gorm.DB, emp *models.Employee, system string, setting *models.FlextimeSetting) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var previous models.FlextimeSetting
+		err := tx.Where("employee_id = ?", emp.ID).First(&previous).Error
+		hasPrevious := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		emp.WorkingTimeSystem = system
+		if err := tx.Model(emp).Update("working_time_system", system).Error; err != nil {
+			return err
+		}
+		if setting != nil {
+			setting.EmployeeID = emp.ID
+			if hasPrevious {
+				setting.ID = previous.ID
+				setting.CreatedAt = previous.CreatedAt
+			}
+			if err := tx.Save(setting).Error; err != nil {
+				return err
+			}
+		}
+
+		// 時間外労働の判定が変わり得る最初の月から再計算する
+		var from time.Time
+		for _, s := range []*models.FlextimeSetting{&previous, setting} {
+			if s == nil || s.StartMonth == 0 {
+				continue
+			}
+			start := time.Date(s.StartYear, time.Month(s.StartMonth), 1, 0, 0, 0, 0, time.Local)
+			if from.IsZero() || start.Before(from) {
+				from = start
+			}
+		}
+		if from.IsZero() {
+			return nil
+		}
+
+		var full models.Employee
+		if err := tx.Preload("Company").First(&full, emp.ID).Error; err != nil {
+			return err
+		}
+		var dates []time.Time
+		if err := tx.Model(&models.WorkRecord{}).
+			Where("employee_id = ? AND date >= ?", emp.ID, from).
+			Distinct("date").
+			Order("date ASC").
+			Pluck("date", &dates).Error; err != nil {
+			return err
+		}
+		var last time.Time
+		for _, date := range dates {
+			week := weekStart(date, full.Company)
+			if week.Equal(last) {
+				continue
+			}
+			last = week
+			if err := recalculateOvertime(tx, full, date); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CalculateFlextimeSettlement 従業員の指定した年・月を含む清算期間の労働時間を清算する
+// 清算期間の途中では、その時点までの勤務記録で計算する
+func CalculateFlextimeSettlement(db *gorm.DB, employeeID uint, year, month int) (FlextimeSettlement, error) {
+	var emp models.Employee
+	if err := db.Preload("Company").First(&emp, employeeID).Error; err != nil {
+		return FlextimeSettlement{}, err
+	}
+	setting, err := flextimeSettingFor(db, emp)
+	if err != nil {
+		return FlextimeSettlement{}, err
+	}
+	if setting == nil {
+		return FlextimeSettlement{}, errors.New("employee is not on flextime")
+	}
+	startYear, startMonth, ok := setting.PeriodStart(year, month)
+	if !ok {
+		return FlextimeSettlement{}, errors.New("month is before the first settlement period")
+	}
+	return settleFlextimePeriod(db, emp, *setting, startYear, startMonth)
+}
+
+// flextimeOvertimeMinutes フレックスタイム制の従業員の指定した年・月の給与で支払う時間外労働 (適用期間外なら ok が false)
+func flextimeOvertimeMinutes(db *gorm.DB, emp models.Employee, year, month int) (int64, bool, error) {
+	setting, err := flextimeSettingFor(db, emp)
+	if err != nil || setting == nil {
+		return 0, false, err
+	}
+	startYear, startMonth, ok := setting.PeriodStart(year, month)
+	if !ok {
+		return 0, false, nil
+	}
+	s, err := settleFlextimePeriod(db, emp, *setting, startYear, startMonth)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, m := range s.Months {
+		if m.Year == year && m.Month == month {
+			return m.OvertimeMinutes, true, nil
+		}
+	}
+	return 0, true, nil
+}
+
+// settleFlextimePeriod 指定した年・月から始まる清算期間を清算する
+func settleFlextimePeriod(db *gorm.DB, emp models.Employee, setting models.FlextimeSetting, startYear, startMonth int) (FlextimeSettlement, error) {
+	carriedIn, err := flextimeCarriedIn(db, emp, setting, startYear, startMonth)
+	if err != nil {
+		return FlextimeSettlement{}, err
+	}
+	return settleFlextimePeriodWith(db, emp, setting, startYear, startMonth, carriedIn)
+}
+
+// settleFlextimePeriodWith 前の清算期間から繰り越した不足時間を指定して清算期間を清算する
+func settleFlextimePeriodWith(db *gorm.DB, emp models.Employee, setting models.FlextimeSetting, startYear, startMonth int, carriedIn int64) (FlextimeSettlement, error) {
+	from := time.Date(startYear, time.Month(startMonth), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, setting.SettlementMonths, 0)
+
+	cal, err := loadCompanyCalendar(db, emp.Company, from, to.AddDate(0, 0, -1))
+	if err != nil {
+		return FlextimeSettlement{}, err
+	}
+	var records []models.WorkRecord
+	if err := db.Where("employee_id = ? AND date >= ? AND date < ?", emp.ID, from, to).
+		Order("date ASC, clock_in ASC").
+		Find(&records).Error; err != nil {
+		return FlextimeSettlement{}, err
+	}
+
+	s := settleFlextime(emp, setting, cal, records, startYear, startMonth, carriedIn, dateOnly(time.Now()))
+	var headroom int64
+	if s.DeficitMinutes > 0 && setting.MaxCarryOverMinutes > 0 {
+		if headroom, err = flextimeCarryOverHeadroom(db, emp, setting, s.EndYear, s.EndMonth); err != nil {
+			return s, err
+		}
+	}
+	applyFlextimeCarryOver(&s, setting, headroom)
+	return s, nil
+}
+
+// settleFlextime 清算期間の勤務記録 (日付・出勤時刻の順) から総労働時間と時間外労働を求める
+// 清算期間が1か月を超える場合は、各月の週平均50時間を超えた時間をその月の時間外労働とし、
+// 最後の月に法定労働時間の総枠を超えた残りの時間を加える
+func settleFlextime(emp models.Employee, setting models.FlextimeSetting, cal *companyCalendar, records []models.WorkRecord, startYear, startMonth int, carriedIn int64, today time.Time) FlextimeSettlement {
+	endYear, endMonth := addMonths(startYear, startMonth, setting.SettlementMonths-1)
+	s := FlextimeSettlement{
+		EmployeeID:         emp.ID,
+		StartYear:          startYear,
+		StartMonth:         startMonth,
+		EndYear:            endYear,
+		EndMonth:           endMonth,
+		CarriedInMinutes:   carriedIn,
+		CoreTimeViolations: []CoreTimeViolation{},
+	}
+
+	byDate := make(map[string][]models.WorkRecord)
+	for _, r := range records {
+		key := r.Date.In(time.Local).Format("2006-01-02")
+		byDate[key] = append(byDate[key], r)
+	}
+
+	var monthlyOvertime int64
+	var totalDays int
+	for i := 0; i < setting.SettlementMonths; i++ {
+		y, m := addMonths(startYear, startMonth, i)
+		monthFrom := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.Local)
+		days := daysInMonth(y, m)
+		totalDays += days
+
+		fm := FlextimeMonth{
+			Year:              y,
+			Month:             m,
+			LegalLimitMinutes: int64(emp.Company.LegalWeeklyMinutes * days / 7),
+		}
+		for d := monthFrom; d.Month() == monthFrom.Month(); d = d.AddDate(0, 0, 1) {
+			working := cal.workingDay(d)
+			if working {
+				fm.ScheduledDays++
+			}
+
+			var worked, onLeave bool
+			for _, r := range byDate[d.Format("2006-01-02")] {
+				if r.LeaveType != "" {
+					fm.LeaveMinutes += r.LeaveMinutes
+					onLeave = true
+					continue
+				}
+				worked = true
+				fm.WorkMinutes += r.WorkMinutes - r.HolidayMinutes
+			}
+			if !working || onLeave {
+				continue
+			}
+			if v, ok := coreTimeViolation(setting, d, byDate[d.Format("2006-01-02")], worked, today); ok {
+				s.CoreTimeViolations = append(s.CoreTimeViolations, v)
+			}
+		}
+		fm.RequiredMinutes = int64(fm.ScheduledDays * setting.StandardDailyMinutes)
+
+		if setting.SettlementMonths > 1 {
+			limit := int64(flextimeMonthlyWeeklyMinutes * days / 7)
+			fm.OvertimeMinutes = max(0, fm.WorkMinutes-limit)
+			monthlyOvertime += fm.OvertimeMinutes
+		}
+
+		s.RequiredMinutes += fm.RequiredMinutes
+		s.ActualMinutes += fm.WorkMinutes + fm.LeaveMinutes
+		s.Months = append(s.Months, fm)
+	}
+	s.RequiredMinutes += carriedIn
+	s.LegalLimitMinutes = int64(emp.Company.LegalWeeklyMinutes * totalDays / 7)
+
+	var totalWork int64
+	for _, fm := range s.Months {
+		totalWork += fm.WorkMinutes
+	}
+	last := &s.Months[len(s.Months)-1]
+	last.OvertimeMinutes += max(0, totalWork-monthlyOvertime-s.LegalLimitMinutes)
+	for _, fm := range s.Months {
+		s.OvertimeMinutes += fm.OvertimeMinutes
+	}
+
+	s.SurplusMinutes = max(0, s.ActualMinutes-s.RequiredMinutes)
+	s.DeficitMinutes = max(0, s.RequiredMinutes-s.ActualMinutes)
+	s.DeductionMinutes = s.DeficitMinutes
+	return s
+}
+
+// applyFlextimeCarryOver 不足時間を、繰り越しの上限と次の清算期間の余裕 (headroom) の範囲で繰り越し、残りを控除する時間とする
+func applyFlextimeCarryOver(s *FlextimeSettlement, setting models.FlextimeSetting, headroom int64) {
+	s.CarryOverMinutes = 0
+	if s.DeficitMinutes > 0 && setting.MaxCarryOverMinutes > 0 {
+		s.CarryOverMinutes = min(s.DeficitMinutes, int64(setting.MaxCarryOverMinutes), max(headroom, 0))
+	}
+	s.DeductionMinutes = s.DeficitMinutes - s.CarryOverMinutes
+}
+
+// coreTimeViolation 所定労働日の勤務がコアタイムを満たしているか (当日以降の勤務のない日は判定しない)
+func coreTimeViolation(setting models.FlextimeSetting, date time.Time, records []models.WorkRecord, worked bool, today time.Time) (CoreTimeViolation, bool) {
+	coreStart, coreEnd, ok := setting.CoreSpan(date)
+	if !ok {
+		return CoreTimeViolation{}, false
+	}
+	if !worked {
+		return CoreTimeViolation{Date: date, Absent: true}, date.Before(today)
+	}
+
+	v := CoreTimeViolation{Date: date}
+	var first, lastOut time.Time
+	for _, r := range records {
+		if r.LeaveType != "" {
+			continue
+		}
+		if first.IsZero() || r.ClockIn.Before(first) {
+			first = r.ClockIn
+		}
+		if r.ClockOut.After(lastOut) {
+			lastOut = r.ClockOut
+		}
+	}
+	if first.After(coreStart) {
+		v.LateMinutes = int64(first.Sub(coreStart).Minutes())
+	}
+	if !lastOut.IsZero() && lastOut.Before(coreEnd) {
+		v.EarlyLeaveMinutes = int64(coreEnd.Sub(lastOut).Minutes())
+	}
+	return v, v.LateMinutes > 0 || v.EarlyLeaveMinutes > 0
+}
+
+// flextimeCarriedIn 前の清算期間から繰り越された不足時間 (繰り越しを認めない設定や最初の清算期間は0)
+// 前の清算期間の最後の月を締めた際に保存した時間を使い、保存されていない場合は前の清算期間だけを清算して求める
+// (その際、さらに前の清算期間からの繰り越しは保存された時間のみを使い、清算期間をさかのぼって計算し直さない)
+func flextimeCarriedIn(db *gorm.DB, emp models.Employee, setting models.FlextimeSetting, startYear, startMonth int) (int64, error) {
+	if setting.MaxCarryOverMinutes == 0 {
+		return 0, nil
+	}
+	prevYear, prevMonth := addMonths(startYear, startMonth, -setting.SettlementMonths)
+	if _, _, ok := setting.PeriodStart(prevYear, prevMonth); !ok {
+		return 0, nil
+	}
+
+	minutes, ok, err := savedFlextimeCarryOver(db, emp.ID, prevYear, prevMonth)
+	if err != nil || ok {
+		return minutes, err
+	}
+
+	var prevCarriedIn int64
+	beforeYear, beforeMonth := addMonths(prevYear, prevMonth, -setting.SettlementMonths)
+	if _, _, ok := setting.PeriodStart(beforeYear, beforeMonth); ok {
+		if prevCarriedIn, _, err = savedFlextimeCarryOver(db, emp.ID, beforeYear, beforeMonth); err != nil {
+			return 0, err
+		}
+	}
+	prev, err := settleFlextimePeriodWith(db, emp, setting, prevYear, prevMonth, prevCarriedIn)
+	if err != nil {
+		return 0, err
+	}
+	return prev.CarryOverMinutes, nil
+}
+
+// savedFlextimeCarryOver 指定した年・月から始まる清算期間について保存された繰り越し時間 (保存されていなければ ok が false)
+func savedFlextimeCarryOver(db *gorm.DB, employeeID uint, startYear, startMonth int) (int64, bool, error) {
+	var carryOver models.FlextimeCarryOver
+	err := db.Where("employee_id = ? AND start_year = ? AND start_month = ?", employeeID, startYear, startMonth).
+		First(&carryOver).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	return carryOver.Minutes, true, nil
+}
+
+// saveFlextimeCarryOvers 給与計算を締めた月が清算期間の最後の月となる従業員について、次の清算期間に繰り越す不足時間を保存する
+func saveFlextimeCarryOvers(tx *gorm.DB, run models.PayrollRun) error {
+	var employees []models.Employee
+	if err := tx.Preload("Company").
+		Where("id IN (?) AND working_time_system = ?",
+			tx.Model(&models.Payslip{}).Select("employee_id").Where("payroll_run_id = ?", run.ID),
+			models.WorkingTimeFlextime).
+		Find(&employees).Error; err != nil {
+		return err
+	}
+
+	for _, emp := range employees {
+		setting, err := flextimeSettingFor(tx, emp)
+		if err != nil {
+			return err
+		}
+		if setting == nil {
+			continue
+		}
+		startYear, startMonth, ok := setting.PeriodStart(run.Year, run.Month)
+		if !ok {
+			continue
+		}
+		if endYear, endMonth := addMonths(startYear, startMonth, setting.SettlementMonths-1); endYear != run.Year || endMonth != run.Month {
+			continue
+		}
+
+		s, err := settleFlextimePeriod(tx, emp, *setting, startYear, startMonth)
+		if err != nil {
+			return err
+		}
+		carryOver := models.FlextimeCarryOver{
+			EmployeeID: emp.ID,
+			StartYear:  startYear,
+			StartMonth: startMonth,
+			EndYear:    run.Year,
+			EndMonth:   run.Month,
+			Minutes:    s.CarryOverMinutes,
+		}
+		if err := tx.Where("employee_id = ? AND start_year = ? AND start_month = ?", emp.ID, startYear, startMonth).
+			Assign(map[string]interface{}{"end_year": run.Year, "end_month": run.Month, "minutes": s.CarryOverMinutes}).
+			Attrs(carryOver).
+			FirstOrCreate(&models.FlextimeCarryOver{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteFlextimeCarryOvers 締めを解除した月が最後の月となる清算期間の繰り越し時間を削除する
+func deleteFlextimeCarryOvers(tx *gorm.DB, run models.PayrollRun) error {
+	return tx.Where("end_year = ? AND end_month = ? AND employee_id IN (?)", run.Year, run.Month,
+		tx.Model(&models.Payslip{}).Select("employee_id").Where("payroll_run_id = ?", run.ID)).
+		Delete(&models.FlextimeCarryOver{}).Error
+}
+
+// flextimeCarryOverHeadroom 次の清算期間に繰り越せる不足時間の上限
+// 繰り越した時間を加えた総労働時間が法定労働時間の総枠を超えない範囲とする
+func flextimeCarryOverHeadroom(db *gorm.DB, emp models.Employee, setting models.FlextimeSetting, endYear, endMonth int) (int64, error) {
+	nextYear, nextMonth := addMonths(endYear, endMonth, 1)
+	from := time.Date(nextYear, time.Month(nextMonth), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, setting.SettlementMonths, 0)
+	cal, err := loadCompanyCalendar(db, emp.Company, from, to.AddDate(0, 0, -1))
+	if err != nil {
+		return 0, err
+	}
+
+	var required int64
+	days := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		days++
+		if cal.workingDay(d) {
+			required += int64(setting.StandardDailyMinutes)
+		}
+	}
+	legal := int64(emp.Company.LegalWeeklyMinutes * days / 7)
+	return max(0, legal-required), nil
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+// flextimeRecords 2025年6月の平日 (21日) それぞれに minutes 分働いた勤務記録 (9時出勤、休憩1時間)
+// skip に含まれる日は記録を作らない
+func flextimeRecords(minutes int64, skip ...int) []models.WorkRecord {
+	var records []models.WorkRecord
+	for d := 1; d <= 30; d++ {
+		date := localDate(2025, time.June, d)
+		if w := date.Weekday(); w == time.Saturday || w == time.Sunday {
+			continue
+		}
+		skipped := false
+		for _, s := range skip {
+			skipped = skipped || s == d
+		}
+		if skipped {
+			continue
+		}
+		clockIn := date.Add(9 * time.Hour)
+		records = append(records, models.WorkRecord{
+			Date:        date,
+			ClockIn:     clockIn,
+			ClockOut:    clockIn.Add(time.Duration(minutes+60) * time.Minute),
+			WorkMinutes: minutes,
+		})
+	}
+	return records
+}
+
+func TestSettleFlextime(t *testing.T) {
+	company := testCompany()
+	company.RestWeekdays = "0,6"
+	emp := models.Employee{ID: 1, Company: company}
+	cal := &companyCalendar{company: company}
+	today := localDate(2025, time.December, 1)
+
+	monthly := models.FlextimeSetting{StartYear: 2025, StartMonth: 4, SettlementMonths: 1, StandardDailyMinutes: 480}
+	quarterly := models.FlextimeSetting{StartYear: 2025, StartMonth: 6, SettlementMonths: 3, StandardDailyMinutes: 480}
+	withCore := monthly
+	withCore.CoreStartTime, withCore.CoreEndTime = "10:00", "15:00"
+
+	leave := flextimeRecords(480, 30)
+	leave = append(leave, models.WorkRecord{
+		Date: localDate(2025, time.June, 30), LeaveType: string(models.LeavePaidFullDay), LeaveMinutes: 480,
+	})
+
+	tests := []struct {
+		name          string
+		setting       models.FlextimeSetting
+		records       []models.WorkRecord
+		carriedIn     int64
+		wantRequired  int64
+		wantLegal     int64
+		wantActual    int64
+		wantOvertime  []int64
+		wantSurplus   int64
+		wantDeficit   int64
+		wantViolation int
+	}{
+		{
+			"総労働時間ちょうど", monthly, flextimeRecords(480), 0,
+			10080, 10285, 10080, []int64{0}, 0, 0, 0,
+		},
+		{
+			"法定労働時間の総枠を超えた分が時間外労働", monthly, flextimeRecords(540), 0,
+			10080, 10285, 11340, []int64{1055}, 1260, 0, 0,
+		},
+		{
+			"総労働時間に満たない", monthly, flextimeRecords(450), 0,
+			10080, 10285, 9450, []int64{0}, 0, 630, 0,
+		},
+		{
+			"繰り越した不足時間を総労働時間に加える", monthly, flextimeRecords(480), 120,
+			10200, 10285, 10080, []int64{0}, 0, 120, 0,
+		},
+		{
+			"有給休暇の時間を総労働時間に充当する", monthly, leave, 0,
+			10080, 10285, 10080, []int64{0}, 0, 0, 0,
+		},
+		{
+			"3か月の清算期間は各月の週平均50時間を超えた分をその月の時間外労働とする", quarterly, flextimeRecords(660), 0,
+			10080 + 10560 + 9600, 31542, 13860, []int64{1003, 0, 0}, 0, 30240 - 13860, 0,
+		},
+		{
+			"コアタイムの遅刻と欠勤", withCore, append(flextimeRecords(480, 2, 3), models.WorkRecord{
+				Date:        localDate(2025, time.June, 3),
+				ClockIn:     localTime(2025, time.June, 3, 10, 30),
+				ClockOut:    localTime(2025, time.June, 3, 19, 30),
+				WorkMinutes: 480,
+			}), 0,
+			10080, 10285, 9600, []int64{0}, 0, 480, 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := settleFlextime(emp, tt.setting, cal, tt.records, 2025, 6, tt.carriedIn, today)
+			if s.RequiredMinutes != tt.wantRequired {
+				t.Errorf("required = %d, want %d", s.RequiredMinutes, tt.wantRequired)
+			}
+			if s.LegalLimitMinutes != tt.wantLegal {
+				t.Errorf("legal limit = %d, want %d", s.LegalLimitMinutes, tt.wantLegal)
+			}
+			if s.ActualMinutes != tt.wantActual {
+				t.Errorf("actual = %d, want %d", s.ActualMinutes, tt.wantActual)
+			}
+			var overtime []int64
+			for _, m := range s.Months {
+				overtime = append(overtime, m.OvertimeMinutes)
+			}
+			if !reflect.DeepEqual(overtime, tt.wantOvertime) {
+				t.Errorf("monthly overtime = %v, want %v", overtime, tt.wantOvertime)
+			}
+			if s.SurplusMinutes != tt.wantSurplus || s.DeficitMinutes != tt.wantDeficit {
+				t.Errorf("surplus/deficit = %d/%d, want %d/%d", s.SurplusMinutes, s.DeficitMinutes, tt.wantSurplus, tt.wantDeficit)
+			}
+			if len(s.CoreTimeViolations) != tt.wantViolation {
+				t.Errorf("core time violations = %+v, want %d", s.CoreTimeViolations, tt.wantViolation)
+			}
+		})
+	}
+}
+
+func TestApplyFlextimeCarryOver(t *testing.T) {
+	tests := []struct {
+		name          string
+		deficit       int64
+		maxCarryOver  int
+		headroom      int64
+		wantCarry     int64
+		wantDeduction int64
+	}{
+		{"繰り越しを認めない", 630, 0, 1000, 0, 630},
+		{"上限まで繰り越す", 630, 600, 1000, 600, 30},
+		{"次の清算期間の余裕まで繰り越す", 630, 600, 300, 300, 330},
+		{"不足時間がすべて繰り越せる", 120, 600, 1000, 120, 0},
+		{"不足時間がない", 0, 600, 1000, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := FlextimeSettlement{DeficitMinutes: tt.deficit}
+			applyFlextimeCarryOver(&s, models.FlextimeSetting{MaxCarryOverMinutes: tt.maxCarryOver}, tt.headroom)
+			if s.CarryOverMinutes != tt.wantCarry || s.DeductionMinutes != tt.wantDeduction {
+				t.Errorf("carry over/deduction = %d/%d, want %d/%d",
+					s.CarryOverMinutes, s.DeductionMinutes, tt.wantCarry, tt.wantDeduction)
+			}
+		})
+	}
+}
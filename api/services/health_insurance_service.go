@@ -4,7 +4,13 @@ import (
 	"errors"
 	"github.com/t2469/attendance-system.git/models"
 	"gorm.io/gorm"
-	"math"
+)
+
+const (
+	// careInsuranceMinAge 介護保険第2号被保険者となる年齢
+	careInsuranceMinAge = 40
+	// careInsuranceMaxAge 介護保険第1号被保険者となり、健康保険での介護保険料の徴収がなくなる年齢
+	careInsuranceMaxAge = 65
 )
 
 type HealthInsuranceResponse struct {
@@ -21,40 +27,47 @@ type HealthInsuranceResponse struct {
 }
 
 // CalculateInsurance 指定された年・月をもとに健康保険料を計算する関数
+// 定時決定・随時改定で決まった標準報酬月額があればその等級を使い、なければ当月の報酬から等級を求める
 func CalculateInsurance(db *gorm.DB, employeeID uint, year, month int) (HealthInsuranceResponse, error) {
-	var employee models.Employee
-	if err := db.Preload("Company").Preload("Allowances", "year = ? AND month = ?", year, month).Preload("Allowances.AllowanceType").First(&employee, employeeID).Error; err != nil {
+	employee, err := loadPayrollEmployee(db, employeeID, year, month)
+	if err != nil {
 		return HealthInsuranceResponse{}, err
 	}
 
-	age := calculateAge(employee.DateOfBirth)
-	withCare := age >= 40 && age < 65
-
-	totalAllowance := calculateTotalAllowance(employee.Allowances)
-	standardMonthlyAmount := employee.MonthlySalary + int(math.Round(totalAllowance))
+	tables, err := loadRateTables(db, employee.Company, year, month)
+	if err != nil {
+		return HealthInsuranceResponse{}, err
+	}
 
-	prefectureID := employee.Company.PrefectureID
-	if prefectureID == 0 {
-		return HealthInsuranceResponse{}, errors.New("prefecture ID not set for employee's company")
+	standard, err := StandardRemunerationInForce(db, employeeID, year, month)
+	if err != nil {
+		return HealthInsuranceResponse{}, err
 	}
 
-	var rate models.HealthInsuranceRate
-	err := db.Where(
-		"prefecture_id = ? AND min_monthly_amount <= ? AND max_monthly_amount >= ? "+
-			"AND ((? > from_year) OR (? = from_year AND ? >= from_month)) "+
-			"AND ((? < to_year) OR (? = to_year AND ? <= to_month))",
-		prefectureID, standardMonthlyAmount, standardMonthlyAmount,
-		year, year, month,
-		year, year, month,
-	).Order("from_year desc, from_month desc").
-		First(&rate).Error
+	earnings, err := calculateEarnings(db, employee, year, month)
 	if err != nil {
-		return HealthInsuranceResponse{}, errors.New("no matching rate found for employee's company for the specified calculation date")
+		return HealthInsuranceResponse{}, err
 	}
 
-	var pref models.Prefecture
-	if err := db.First(&pref, prefectureID).Error; err != nil {
-		return HealthInsuranceResponse{}, errors.New("prefecture not found")
+	return calculateHealthInsurance(employee, standard, earnings.remuneration(), tables, year, month)
+}
+
+// calculateHealthInsurance 読み込み済みの保険料額表から健康保険料を求める
+// 標準報酬月額の履歴がない場合は remuneration (当月の報酬月額) から等級を求める
+func calculateHealthInsurance(employee models.Employee, standard *models.StandardRemuneration, remuneration int, tables rateTables, year, month int) (HealthInsuranceResponse, error) {
+	// 40歳に達した日の属する月から65歳に達した日の属する月の前月まで介護保険料を徴収する
+	age := calculateAge(employee.DateOfBirth, year, month)
+	withCare := age >= careInsuranceMinAge && age < careInsuranceMaxAge
+
+	var rate models.HealthInsuranceRate
+	var ok bool
+	if standard != nil {
+		rate, ok = tables.healthByGrade(standard.HealthGrade)
+	} else {
+		rate, ok = tables.healthByAmount(remuneration)
+	}
+	if !ok {
+		return HealthInsuranceResponse{}, errors.New("no matching rate found for employee's company for the specified calculation date")
 	}
 
 	var totalHealth, employeeHealth float64
@@ -70,7 +83,7 @@ func CalculateInsurance(db *gorm.DB, employeeID uint, year, month int) (HealthIn
 	resp := HealthInsuranceResponse{
 		EmployeeName:            employee.Name,
 		CompanyName:             employee.Company.Name,
-		PrefectureName:          pref.Name,
+		PrefectureName:          tables.Prefecture.Name,
 		Grade:                   rate.Grade,
 		CalculatedMonthlyAmount: rate.MonthlyAmount,
 		HealthTotal:             totalHealth,
@@ -81,3 +94,15 @@ func CalculateInsurance(db *gorm.DB, employeeID uint, year, month int) (HealthIn
 	}
 	return resp, nil
 }
+
+// healthRatePeriod 指定した年・月に適用される健康保険料額表に絞り込む
+func healthRatePeriod(db *gorm.DB, prefectureID uint, year, month int) *gorm.DB {
+	return db.Where(
+		"prefecture_id = ? "+
+			"AND ((? > from_year) OR (? = from_year AND ? >= from_month)) "+
+			"AND ((? < to_year) OR (? = to_year AND ? <= to_month))",
+		prefectureID,
+		year, year, month,
+		year, year, month,
+	).Order("from_year desc, from_month desc")
+}
@@ -0,0 +1,269 @@
+package services
+
+import "math"
+
+// 所得税の年税額の計算 (年末調整)
+// 令和7年分以降は給与所得控除の最低額・基礎控除・扶養親族等の所得要件が改正後の額となる
+
+const (
+	// taxReformYear 給与所得控除・基礎控除・所得要件が改正された年 (令和7年)
+	taxReformYear = 2025
+	// basicDeductionSurchargeEndYear 基礎控除の特例による加算がある最後の年 (令和8年)
+	basicDeductionSurchargeEndYear = 2026
+	// reconstructionSurtaxPermille 復興特別所得税を含めた税率 (102.1%)
+	reconstructionSurtaxPermille = 1021
+)
+
+// salaryIncome 給与等の収入金額から給与所得控除後の給与等の金額を求める
+// 年末調整では 660万円未満の収入を4,000円単位に切り捨ててから計算する (所得税法別表第五)
+func salaryIncome(year, pay int) int {
+	minDeduction, tableFrom := 650000, 1900000
+	if year < taxReformYear {
+		minDeduction, tableFrom = 550000, 1628000
+	}
+
+	if pay < tableFrom {
+		return max(pay-minDeduction, 0)
+	}
+
+	amount := pay
+	if pay < 6600000 {
+		amount = pay / 4000 * 4000
+	}
+
+	var deduction int
+	switch {
+	case amount <= 1800000:
+		deduction = amount*40/100 - 100000
+	case amount <= 3600000:
+		deduction = amount*30/100 + 80000
+	case amount <= 6600000:
+		deduction = amount*20/100 + 440000
+	case amount <= 8500000:
+		deduction = amount*10/100 + 1100000
+	default:
+		deduction = 1950000
+	}
+	return amount - max(deduction, minDeduction)
+}
+
+// basicDeduction 合計所得金額に応じた基礎控除の額
+func basicDeduction(year, income int) int {
+	switch {
+	case income > 25000000:
+		return 0
+	case income > 24500000:
+		return 160000
+	case income > 24000000:
+		return 320000
+	case income > 23500000 || year < taxReformYear:
+		return 480000
+	case income <= 1320000:
+		return 950000
+	}
+
+	// 令和7年・8年は合計所得金額 655万円以下の場合に加算がある
+	deduction := 580000
+	if year <= basicDeductionSurchargeEndYear {
+		switch {
+		case income <= 3360000:
+			deduction += 300000
+		case income <= 4890000:
+			deduction += 100000
+		case income <= 6550000:
+			deduction += 50000
+		}
+	}
+	return deduction
+}
+
+// dependentIncomeLimit 同一生計配偶者・扶養親族となる合計所得金額の上限
+func dependentIncomeLimit(year int) int {
+	if year < taxReformYear {
+		return 480000
+	}
+	return 580000
+}
+
+// lifeInsuranceNewDeduction 新契約の保険料に対する控除額 (上限4万円)
+func lifeInsuranceNewDeduction(premium int) int {
+	switch {
+	case premium <= 20000:
+		return premium
+	case premium <= 40000:
+		return ceilDiv(premium, 2) + 10000
+	case premium <= 80000:
+		return ceilDiv(premium, 4) + 20000
+	default:
+		return 40000
+	}
+}
+
+// lifeInsuranceOldDeduction 旧契約の保険料に対する控除額 (上限5万円)
+func lifeInsuranceOldDeduction(premium int) int {
+	switch {
+	case premium <= 25000:
+		return premium
+	case premium <= 50000:
+		return ceilDiv(premium, 2) + 12500
+	case premium <= 100000:
+		return ceilDiv(premium, 4) + 25000
+	default:
+		return 50000
+	}
+}
+
+// lifeInsuranceCategoryDeduction 新契約・旧契約の両方がある区分の控除額
+// 旧契約のみで4万円を超える場合はその額、それ以外は合計して4万円を上限とする
+func lifeInsuranceCategoryDeduction(newPremium, oldPremium int) int {
+	oldDeduction := lifeInsuranceOldDeduction(oldPremium)
+	if newPremium == 0 || oldDeduction > 40000 {
+		return oldDeduction
+	}
+	return min(lifeInsuranceNewDeduction(newPremium)+oldDeduction, 40000)
+}
+
+// lifeInsuranceDeduction 一般・介護医療・個人年金の各区分の控除額の合計 (上限12万円)
+func lifeInsuranceDeduction(generalNew, generalOld, careMedical, pensionNew, pensionOld int) int {
+	total := lifeInsuranceCategoryDeduction(generalNew, generalOld) +
+		lifeInsuranceNewDeduction(careMedical) +
+		lifeInsuranceCategoryDeduction(pensionNew, pensionOld)
+	return min(total, 120000)
+}
+
+// earthquakeInsuranceDeduction 地震保険料と旧長期損害保険料の控除額 (合計の上限5万円)
+func earthquakeInsuranceDeduction(earthquake, oldLongTerm int) int {
+	var longTerm int
+	switch {
+	case oldLongTerm <= 10000:
+		longTerm = oldLongTerm
+	case oldLongTerm <= 20000:
+		longTerm = ceilDiv(oldLongTerm, 2) + 5000
+	default:
+		longTerm = 15000
+	}
+	return min(min(earthquake, 50000)+longTerm, 50000)
+}
+
+// spouseSpecialDeductionTable 配偶者特別控除の額 (配偶者の合計所得金額の上限, 本人の合計所得金額が900万円以下・950万円以下・1,000万円以下の額)
+var spouseSpecialDeductionTable = []struct {
+	MaxSpouseIncome int
+	Amounts         [3]int
+}{
+	{950000, [3]int{380000, 260000, 130000}},
+	{1000000, [3]int{360000, 240000, 120000}},
+	{1050000, [3]int{310000, 210000, 110000}},
+	{1100000, [3]int{260000, 180000, 90000}},
+	{1150000, [3]int{210000, 140000, 70000}},
+	{1200000, [3]int{160000, 110000, 60000}},
+	{1250000, [3]int{110000, 80000, 40000}},
+	{1300000, [3]int{60000, 40000, 20000}},
+	{1330000, [3]int{30000, 20000, 10000}},
+}
+
+// spouseDeduction 配偶者控除または配偶者特別控除の額 (本人の合計所得金額が1,000万円を超える場合は0)
+func spouseDeduction(year, income, spouseIncome int, elderlySpouse bool) int {
+	var column int
+	switch {
+	case income <= 9000000:
+		column = 0
+	case income <= 9500000:
+		column = 1
+	case income <= 10000000:
+		column = 2
+	default:
+		return 0
+	}
+
+	if spouseIncome <= dependentIncomeLimit(year) {
+		if elderlySpouse {
+			return [3]int{480000, 320000, 160000}[column]
+		}
+		return [3]int{380000, 260000, 130000}[column]
+	}
+
+	for _, row := range spouseSpecialDeductionTable {
+		if spouseIncome <= row.MaxSpouseIncome {
+			return row.Amounts[column]
+		}
+	}
+	return 0
+}
+
+// 扶養親族の区分
+const (
+	dependentUnder16       = "under16"        // 16歳未満 (扶養控除の対象外)
+	dependentGeneral       = "general"        // 一般の控除対象扶養親族
+	dependentSpecific      = "specific"       // 特定扶養親族 (19歳以上23歳未満)
+	dependentElderly       = "elderly"        // 老人扶養親族 (70歳以上)
+	dependentElderlyParent = "elderly_parent" // 同居老親等
+	dependentNotApplicable = ""               // 所得要件を満たさない
+)
+
+// classifyDependent 12月31日時点の年齢と所得から扶養親族の区分を判定する
+func classifyDependent(year, age, income int, cohabitingParent bool) string {
+	if income > dependentIncomeLimit(year) {
+		return dependentNotApplicable
+	}
+	switch {
+	case age < 16:
+		return dependentUnder16
+	case age >= 19 && age < 23:
+		return dependentSpecific
+	case age >= 70 && cohabitingParent:
+		return dependentElderlyParent
+	case age >= 70:
+		return dependentElderly
+	default:
+		return dependentGeneral
+	}
+}
+
+// dependentDeductionAmount 扶養親族の区分ごとの扶養控除の額
+func dependentDeductionAmount(category string) int {
+	switch category {
+	case dependentGeneral:
+		return 380000
+	case dependentSpecific:
+		return 630000
+	case dependentElderly:
+		return 480000
+	case dependentElderlyParent:
+		return 580000
+	default:
+		return 0
+	}
+}
+
+// incomeTaxAmount 課税所得金額に対する所得税額 (速算表)
+func incomeTaxAmount(taxable int) int {
+	brackets := []struct {
+		Max       int
+		Rate      int // %
+		Deduction int
+	}{
+		{1950000, 5, 0},
+		{3300000, 10, 97500},
+		{6950000, 20, 427500},
+		{9000000, 23, 636000},
+		{18000000, 33, 1536000},
+		{40000000, 40, 2796000},
+		{math.MaxInt, 45, 4796000},
+	}
+	for _, b := range brackets {
+		if taxable <= b.Max {
+			return taxable*b.Rate/100 - b.Deduction
+		}
+	}
+	return 0
+}
+
+// annualTaxWithSurtax 年調所得税額に復興特別所得税を加えた年調年税額 (100円未満切り捨て)
+func annualTaxWithSurtax(tax int) int {
+	return tax * reconstructionSurtaxPermille / 1000 / 100 * 100
+}
+
+// ceilDiv 割り算の結果の1円未満を切り上げる
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,101 @@
+package services
+
+import "testing"
+
+func TestSalaryIncome(t *testing.T) {
+	tests := []struct {
+		name string
+		year int
+		pay  int
+		want int
+	}{
+		{"令和6年: 最低額の控除", 2024, 1000000, 450000},
+		{"令和6年: 控除が収入を上回る", 2024, 500000, 0},
+		{"令和6年: 別表第五の手前", 2024, 1627999, 1077999},
+		{"令和6年: 別表第五の最初の区分", 2024, 1628000, 1076800},
+		{"令和6年: 4,000円未満を切り捨てる", 2024, 3001999, 2020000},
+		{"令和6年: 30%の区分", 2024, 3000000, 2020000},
+		{"令和6年: 20%の区分", 2024, 5000000, 3560000},
+		{"令和6年: 660万円", 2024, 6600000, 4840000},
+		{"令和6年: 10%の区分", 2024, 8000000, 6100000},
+		{"令和6年: 控除の上限", 2024, 10000000, 8050000},
+		{"令和7年: 最低額65万円", 2025, 1000000, 350000},
+		{"令和7年: 控除が収入を上回る", 2025, 600000, 0},
+		{"令和7年: 別表第五の手前", 2025, 1899999, 1249999},
+		{"令和7年: 別表第五の最初の区分", 2025, 1900000, 1250000},
+		{"令和7年: 30%の区分", 2025, 2000000, 1320000},
+		{"令和7年: 20%の区分は改正前と同じ", 2025, 5000000, 3560000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := salaryIncome(tt.year, tt.pay); got != tt.want {
+				t.Errorf("salaryIncome(%d, %d) = %d, want %d", tt.year, tt.pay, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBasicDeduction(t *testing.T) {
+	tests := []struct {
+		year   int
+		income int
+		want   int
+	}{
+		{2024, 1000000, 480000},
+		{2024, 23500001, 480000},
+		{2024, 24000001, 320000},
+		{2024, 24500001, 160000},
+		{2024, 25000001, 0},
+		{2025, 1320000, 950000},
+		{2025, 1320001, 880000},
+		{2025, 3360000, 880000},
+		{2025, 3360001, 680000},
+		{2025, 4890000, 680000},
+		{2025, 4890001, 630000},
+		{2025, 6550000, 630000},
+		{2025, 6550001, 580000},
+		{2025, 23500000, 580000},
+		{2025, 23500001, 480000},
+		{2025, 25000001, 0},
+	}
+	for _, tt := range tests {
+		if got := basicDeduction(tt.year, tt.income); got != tt.want {
+			t.Errorf("basicDeduction(%d, %d) = %d, want %d", tt.year, tt.income, got, tt.want)
+		}
+	}
+}
+
+func TestSpouseDeduction(t *testing.T) {
+	tests := []struct {
+		name         string
+		year         int
+		income       int
+		spouseIncome int
+		elderly      bool
+		want         int
+	}{
+		{"配偶者控除", 2025, 5000000, 0, false, 380000},
+		{"老人控除対象配偶者", 2025, 5000000, 0, true, 480000},
+		{"本人の所得が900万円超", 2025, 9200000, 0, false, 260000},
+		{"本人の所得が900万円超の老人控除対象配偶者", 2025, 9200000, 0, true, 320000},
+		{"本人の所得が950万円超", 2025, 9800000, 0, false, 130000},
+		{"本人の所得が1,000万円超", 2025, 10000001, 0, false, 0},
+		{"令和7年: 配偶者の所得58万円までは配偶者控除", 2025, 5000000, 580000, true, 480000},
+		{"令和6年: 配偶者の所得48万円超は配偶者特別控除", 2024, 5000000, 580000, true, 380000},
+		{"配偶者特別控除: 95万円以下", 2025, 5000000, 950000, false, 380000},
+		{"配偶者特別控除: 100万円以下", 2025, 5000000, 1000000, false, 360000},
+		{"配偶者特別控除: 105万円以下", 2025, 5000000, 1050000, false, 310000},
+		{"配偶者特別控除: 133万円以下", 2025, 5000000, 1330000, false, 30000},
+		{"配偶者特別控除: 133万円超", 2025, 5000000, 1330001, false, 0},
+		{"配偶者特別控除: 本人の所得が900万円超", 2025, 9200000, 1200000, false, 110000},
+		{"配偶者特別控除: 本人の所得が950万円超", 2025, 9800000, 1300000, false, 20000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spouseDeduction(tt.year, tt.income, tt.spouseIncome, tt.elderly); got != tt.want {
+				t.Errorf("spouseDeduction(%d, %d, %d, %v) = %d, want %d",
+					tt.year, tt.income, tt.spouseIncome, tt.elderly, got, tt.want)
+			}
+		})
+	}
+}
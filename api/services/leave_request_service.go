@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrLeaveRequestReviewed 既に承認・却下された申請
+	ErrLeaveRequestReviewed = errors.New("request already reviewed")
+	// ErrLeaveAlreadyTaken 休暇の時間の合計が1日の所定労働時間を超える
+	ErrLeaveAlreadyTaken = errors.New("leave already taken on the date")
+)
+
+// ApproveLeaveRequest 休暇の申請を承認する
+// 年次有給休暇は残日数から差し引き、休暇として賃金を支払う時間を持つ勤務記録を作成する
+// 給与計算が締められた月の休暇は勤務記録を作成できないため承認しない
+func ApproveLeaveRequest(db *gorm.DB, req *models.LeaveRequest, accountID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 同時に承認・却下されないよう申請を行ロックして状態を確認する
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(req, req.ID).Error; err != nil {
+			return err
+		}
+		if req.Status != models.Pending {
+			return ErrLeaveRequestReviewed
+		}
+
+		// 同じ日の休暇が同時に承認されないよう従業員の行をロックする
+		var emp models.Employee
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&emp, req.EmployeeID).Error; err != nil {
+			return err
+		}
+
+		daily := int64(scheduledDailyMinutes(emp))
+		date := dateOnly(req.Date)
+		record := models.WorkRecord{
+			EmployeeID: emp.ID,
+			Date:       date,
+			LeaveType:  models.WorkRecordPaidLeave,
+		}
+		switch req.Type {
+		case models.LeavePaidFullDay, models.LeaveSpecial:
+			record.LeaveMinutes = daily
+		case models.LeavePaidHalfDay:
+			record.LeaveMinutes = daily / 2
+		case models.LeavePaidHourly:
+			record.LeaveMinutes = int64(req.Minutes)
+		}
+		if req.Type == models.LeaveSpecial {
+			record.LeaveType = models.WorkRecordSpecialLeave
+		}
+
+		// 有給休暇を消化する前に、同じ日の休暇と合わせて1日の所定労働時間を超えないかを確認する
+		var taken int64
+		if err := tx.Model(&models.WorkRecord{}).
+			Where("employee_id = ? AND date = ? AND leave_type <> ''", emp.ID, date).
+			Select("COALESCE(SUM(leave_minutes), 0)").
+			Scan(&taken).Error; err != nil {
+			return err
+		}
+		if taken+record.LeaveMinutes > daily {
+			return ErrLeaveAlreadyTaken
+		}
+
+		var err error
+		switch req.Type {
+		case models.LeavePaidFullDay:
+			_, err = usePaidLeave(tx, emp.ID, date, 1, 0)
+		case models.LeavePaidHalfDay:
+			_, err = usePaidLeave(tx, emp.ID, date, 0.5, 0)
+		case models.LeavePaidHourly:
+			err = useHourlyPaidLeave(tx, emp, date, req.Minutes)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		req.Status = models.Approved
+		req.ReviewedBy = &accountID
+		req.ReviewedAt = &now
+		req.WorkRecordID = &record.ID
+		return tx.Save(req).Error
+	})
+}
+
+// useHourlyPaidLeave 時間単位の有給休暇を取得する
+// 取得時間を1日分の時間数で日数に換算して消化する。付与の年ごとに5日分を超える場合は消化せずにエラーとする
+func useHourlyPaidLeave(tx *gorm.DB, emp models.Employee, date time.Time, minutes int) error {
+	// 付与の年を正しく判定するため、付与日を過ぎた有給休暇を先に付与する
+	if err := grantDuePaidLeave(tx, emp, date); err != nil {
+		return err
+	}
+
+	hours := paidLeaveHoursPerDay(emp)
+	taken, err := hourlyPaidLeaveTaken(tx, emp.ID, date)
+	if err != nil {
+		return err
+	}
+	if taken+minutes > hourlyPaidLeaveDaysLimit*hours*60 {
+		return ErrHourlyPaidLeaveLimit
+	}
+
+	_, err = usePaidLeave(tx, emp.ID, date, float64(minutes)/float64(hours*60), minutes)
+	return err
+}
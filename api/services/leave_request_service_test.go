@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/t2469/attendance-system.git/db/dbtest"
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestApproveLeaveRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		leaveType   models.LeaveType
+		minutes     int
+		status      models.RequestStatus
+		leaveTaken  int64   // 同じ日の休暇の時間の合計
+		hourlyTaken int64   // 付与の年に時間単位で取得した時間
+		grantUsed   float64 // 付与10日のうち取得済みの日数
+		wantErr     error
+		wantMinutes int64   // 勤務記録の休暇の時間
+		wantDays    float64 // 有給休暇の取得日数 (0 なら消化しない)
+		wantLeave   string
+	}{
+		{name: "全日は1日分を消化する", leaveType: models.LeavePaidFullDay, status: models.Pending, grantUsed: 3,
+			wantMinutes: 480, wantDays: 1, wantLeave: models.WorkRecordPaidLeave},
+		{name: "半日は所定労働時間の半分", leaveType: models.LeavePaidHalfDay, status: models.Pending, grantUsed: 3,
+			wantMinutes: 240, wantDays: 0.5, wantLeave: models.WorkRecordPaidLeave},
+		{name: "時間単位は1日分の時間数で日数に換算", leaveType: models.LeavePaidHourly, minutes: 120, status: models.Pending, grantUsed: 3,
+			wantMinutes: 120, wantDays: 0.25, wantLeave: models.WorkRecordPaidLeave},
+		{name: "同じ日の半日と合わせて1日分", leaveType: models.LeavePaidHalfDay, status: models.Pending, leaveTaken: 240, grantUsed: 3,
+			wantMinutes: 240, wantDays: 0.5, wantLeave: models.WorkRecordPaidLeave},
+		{name: "特別休暇は有給休暇を消化しない", leaveType: models.LeaveSpecial, status: models.Pending, grantUsed: 10,
+			wantMinutes: 480, wantLeave: models.WorkRecordSpecialLeave},
+		{name: "同じ日の休暇と合わせて所定労働時間を超える", leaveType: models.LeavePaidHalfDay, status: models.Pending, leaveTaken: 300, grantUsed: 3,
+			wantErr: ErrLeaveAlreadyTaken},
+		{name: "特別休暇も所定労働時間を超えられない", leaveType: models.LeaveSpecial, status: models.Pending, leaveTaken: 60,
+			wantErr: ErrLeaveAlreadyTaken},
+		{name: "残日数が足りない", leaveType: models.LeavePaidFullDay, status: models.Pending, grantUsed: 9.5,
+			wantErr: ErrInsufficientPaidLeave},
+		{name: "時間単位の年5日分を超える", leaveType: models.LeavePaidHourly, minutes: 120, status: models.Pending, hourlyTaken: 2340, grantUsed: 3,
+			wantErr: ErrHourlyPaidLeaveLimit},
+		{name: "承認済み", leaveType: models.LeavePaidFullDay, status: models.Approved, grantUsed: 3,
+			wantErr: ErrLeaveRequestReviewed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := dbtest.Open(t)
+			date := localDate(2025, 6, 10)
+			rec.On(`FROM "leave_requests"`, dbtest.Result{
+				Columns: []string{"id", "employee_id", "type", "date", "minutes", "status"},
+				Rows:    [][]any{{int64(3), int64(1), string(tt.leaveType), date, int64(tt.minutes), string(tt.status)}},
+			})
+			rec.On(`FROM "employees"`, dbtest.Result{
+				Columns: []string{"id", "company_id", "name"},
+				Rows:    [][]any{{int64(1), int64(1), "山田 太郎"}},
+			})
+			rec.On("COALESCE(SUM(leave_minutes), 0)", dbtest.Result{Columns: []string{"coalesce"}, Rows: [][]any{{tt.leaveTaken}}})
+			rec.On("COALESCE(SUM(minutes), 0)", dbtest.Result{Columns: []string{"coalesce"}, Rows: [][]any{{tt.hourlyTaken}}})
+			if tt.grantUsed < 10 {
+				rec.On(`FROM "paid_leave_grants"`, dbtest.Result{
+					Columns: []string{"id", "employee_id", "grant_date", "expires_on", "days", "used_days"},
+					Rows:    [][]any{{int64(5), int64(1), localDate(2025, 4, 1), localDate(2027, 3, 31), float64(10), tt.grantUsed}},
+				})
+			}
+			rec.On(`INSERT INTO "work_records"`, dbtest.Result{Columns: []string{"id"}, Rows: [][]any{{int64(55)}}})
+			rec.On("UPDATE", dbtest.Result{RowsAffected: 1})
+
+			req := models.LeaveRequest{ID: 3}
+			err := ApproveLeaveRequest(db, &req, 9)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				for _, table := range []string{`INSERT INTO "paid_leave_usages"`, `INSERT INTO "work_records"`, `UPDATE "leave_requests"`} {
+					if q, ok := rec.Find(table); ok {
+						t.Errorf("rejected request issued %s", q.SQL)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			q, ok := rec.Find(`INSERT INTO "work_records"`)
+			if !ok {
+				t.Fatalf("work record not created: %v", rec.Queries())
+			}
+			record := q.Inserted()
+			if record["leave_minutes"] != tt.wantMinutes || record["leave_type"] != tt.wantLeave {
+				t.Errorf("work record leave = %v %v, want %v %v", record["leave_type"], record["leave_minutes"], tt.wantLeave, tt.wantMinutes)
+			}
+
+			usage, used := rec.Find(`INSERT INTO "paid_leave_usages"`)
+			if tt.wantDays == 0 {
+				if used {
+					t.Errorf("paid leave used: %v", usage.Args)
+				}
+			} else {
+				if !used {
+					t.Fatalf("paid leave not used: %v", rec.Queries())
+				}
+				if got := usage.Inserted()["days"]; got != tt.wantDays {
+					t.Errorf("usage days = %v, want %v", got, tt.wantDays)
+				}
+				grant, ok := rec.Find(`UPDATE "paid_leave_grants"`)
+				if !ok || !containsArg(grant.Args, tt.grantUsed+tt.wantDays) {
+					t.Errorf("grant update = %v, want used days %v", grant.Args, tt.grantUsed+tt.wantDays)
+				}
+			}
+
+			if req.Status != models.Approved || req.ReviewedBy == nil || *req.ReviewedBy != 9 {
+				t.Errorf("request = %+v, want approved by 9", req)
+			}
+			if req.WorkRecordID == nil || *req.WorkRecordID != 55 {
+				t.Errorf("work record id = %v, want 55", req.WorkRecordID)
+			}
+		})
+	}
+}
+
+// containsArg クエリの引数に want が含まれるか
+func containsArg(args []any, want any) bool {
+	for _, a := range args {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/t2469/attendance-system.git/db"
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// schedulerInterval 期限切れジョブを確認する間隔
+	schedulerInterval = 30 * time.Second
+	// schedulerBatchSize 1回の確認で取得するジョブの最大件数
+	schedulerBatchSize = 20
+	// staleJobTimeout 処理中のまま放置されたジョブを再取得するまでの時間 (処理中にプロセスが落ちた場合など)
+	staleJobTimeout = 5 * time.Minute
+)
+
+// errNotificationJobReclaimed 処理中のジョブが他のワーカーに再取得されていた
+var errNotificationJobReclaimed = errors.New("notification job was reclaimed by another worker")
+
+// NextNotifyTime 出勤時刻と "15:04" 形式の通知時刻から次の通知日時を求める
+// 通知時刻が出勤時刻以前の場合は翌日の同時刻とする (夜勤などで日付をまたぐ場合)
+func NextNotifyTime(clockIn time.Time, notifyAt string) (time.Time, error) {
+	t, err := time.Parse("15:04", notifyAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid notify_at format: %w", err)
+	}
+
+	local := clockIn.In(time.Local)
+	notifyTime := time.Date(
+		local.Year(), local.Month(), local.Day(),
+		t.Hour(), t.Minute(), 0, 0,
+		time.Local,
+	)
+	if !notifyTime.After(local) {
+		notifyTime = notifyTime.AddDate(0, 0, 1)
+	}
+	return notifyTime, nil
+}
+
+// ScheduleClockOutReminder 退勤打刻忘れのLINE通知ジョブを登録する
+// 出勤の打刻と同じトランザクションで登録できるよう tx を受け取る
+func ScheduleClockOutReminder(tx *gorm.DB, empID uint, clockIn time.Time, notifyAt string) (models.NotificationJob, error) {
+	runAt, err := NextNotifyTime(clockIn, notifyAt)
+	if err != nil {
+		return models.NotificationJob{}, err
+	}
+
+	job := models.NotificationJob{
+		EmployeeID:  empID,
+		Type:        models.ClockOutReminder,
+		ClockInAt:   clockIn,
+		RunAt:       runAt,
+		Status:      models.JobPending,
+		MaxAttempts: 5,
+	}
+	if err := tx.Create(&job).Error; err != nil {
+		return job, err
+	}
+	return job, nil
+}
+
+// StartNotificationScheduler 通知ジョブを定期的に実行するワーカーを起動する
+// 複数のレプリカで起動しても、行ロックにより同じジョブが二重に実行されることはない
+func StartNotificationScheduler() {
+	go func() {
+		ticker := time.NewTicker(schedulerInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := RunDueNotificationJobs(time.Now()); err != nil {
+				log.Println("notification scheduler:", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// RunDueNotificationJobs 実行時刻を過ぎたジョブを取得して実行する
+func RunDueNotificationJobs(now time.Time) error {
+	jobs, err := claimDueJobs(now)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		err := runNotificationJob(job)
+		if err := finishNotificationJob(job, err); err != nil {
+			log.Printf("failed to update notification job %d: %v", job.ID, err)
+		}
+	}
+	return nil
+}
+
+// claimDueJobs 実行対象のジョブを行ロック付きで取得し、処理中に更新する
+// SKIP LOCKED により他のワーカーが取得中のジョブは読み飛ばす
+func claimDueJobs(now time.Time) ([]models.NotificationJob, error) {
+	var jobs []models.NotificationJob
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?)",
+				models.JobPending, now,
+				models.JobProcessing, now.Add(-staleJobTimeout),
+			).
+			Order("run_at ASC").
+			Limit(schedulerBatchSize).
+			Find(&jobs).Error; err != nil {
+			return err
+		}
+
+		// 完了時に取得した時刻で所有を確認するため、DBに保存できる精度に揃える
+		lockedAt := now.Truncate(time.Microsecond)
+		for i := range jobs {
+			jobs[i].Status = models.JobProcessing
+			jobs[i].Attempts++
+			jobs[i].LockedAt = &lockedAt
+			if err := tx.Save(&jobs[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// finishNotificationJob 実行結果に応じてジョブを完了・再試行・失敗のいずれかに更新する
+// 処理が長引いて他のワーカーに再取得された場合は、新しい実行の状態を上書きしない
+func finishNotificationJob(job models.NotificationJob, runErr error) error {
+	claimedAt := job.LockedAt
+	job.LockedAt = nil
+
+	switch {
+	case runErr == nil:
+		job.Status = models.JobDone
+		job.LastError = ""
+	case job.Attempts >= job.MaxAttempts:
+		job.Status = models.JobFailed
+		job.LastError = runErr.Error()
+	default:
+		// 試行回数に応じて待機時間を延ばす (1分, 2分, 4分...)
+		job.Status = models.JobPending
+		job.LastError = runErr.Error()
+		job.RunAt = time.Now().Add(time.Duration(1<<(job.Attempts-1)) * time.Minute)
+	}
+
+	result := db.DB.Model(&job).
+		Where("status = ? AND locked_at = ?", models.JobProcessing, claimedAt).
+		Select("status", "last_error", "run_at", "locked_at", "updated_at").
+		Updates(&job)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errNotificationJobReclaimed
+	}
+	return nil
+}
+
+func runNotificationJob(job models.NotificationJob) error {
+	switch job.Type {
+	case models.ClockOutReminder:
+		return sendClockOutReminder(job)
+	default:
+		return errors.New("unknown notification job type: " + string(job.Type))
+	}
+}
+
+// sendClockOutReminder 出勤後に退勤打刻がなければLINEで通知する
+func sendClockOutReminder(job models.NotificationJob) error {
+	var cnt int64
+	if err := db.DB.Model(&models.TimeClock{}).
+		Where("employee_id = ? AND type = ? AND timestamp > ?", job.EmployeeID, models.ClockOut, job.ClockInAt).
+		Count(&cnt).Error; err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return nil
+	}
+
+	var emp models.Employee
+	if err := db.DB.First(&emp, job.EmployeeID).Error; err != nil {
+		return err
+	}
+	// LINE未連携の場合は通知先がないため完了扱い
+	if emp.LineUserID == nil {
+		return nil
+	}
+
+	msg := fmt.Sprintf(
+		"%sさん\n退勤を忘れていませんか？\n出勤時刻: %s\n現在時刻: %s",
+		emp.Name,
+		job.ClockInAt.In(time.Local).Format("1/2 15:04"),
+		time.Now().In(time.Local).Format("1/2 15:04"),
+	)
+	return SendMessage(*emp.LineUserID, msg)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextNotifyTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		clockIn  time.Time
+		notifyAt string
+		want     time.Time
+	}{
+		{"同じ日の退勤予定", localTime(2025, time.June, 2, 9, 0), "18:00", localTime(2025, time.June, 2, 18, 0)},
+		{"夜勤は翌日の通知時刻", localTime(2025, time.June, 2, 22, 0), "07:00", localTime(2025, time.June, 3, 7, 0)},
+		{"月末の夜勤は翌月", localTime(2025, time.June, 30, 21, 30), "06:30", localTime(2025, time.July, 1, 6, 30)},
+		{"年末の夜勤は翌年", localTime(2025, time.December, 31, 22, 0), "05:00", localTime(2026, time.January, 1, 5, 0)},
+		{"出勤時刻と同じ時刻は翌日", localTime(2025, time.June, 2, 9, 0), "09:00", localTime(2025, time.June, 3, 9, 0)},
+		{"深夜0時過ぎの出勤", localTime(2025, time.June, 3, 0, 30), "09:00", localTime(2025, time.June, 3, 9, 0)},
+		// 後から打刻した出勤は過去の通知時刻となり、スケジューラーがすぐに実行する
+		{"過去の日付の出勤", localTime(2025, time.May, 30, 9, 0), "18:00", localTime(2025, time.May, 30, 18, 0)},
+		{"過去の日付の夜勤", localTime(2025, time.May, 30, 22, 0), "07:00", localTime(2025, time.May, 31, 7, 0)},
+		{"UTCの出勤時刻はローカル時刻の日付で判定", localTime(2025, time.June, 2, 22, 0).UTC(), "07:00", localTime(2025, time.June, 3, 7, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextNotifyTime(tt.clockIn, tt.notifyAt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("NextNotifyTime(%s, %s) = %s, want %s",
+					tt.clockIn.Format(time.RFC3339), tt.notifyAt, got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestNextNotifyTimeInvalidFormat(t *testing.T) {
+	for _, notifyAt := range []string{"", "18", "25:00", "18:60", "6pm"} {
+		if _, err := NextNotifyTime(localTime(2025, time.June, 2, 9, 0), notifyAt); err == nil {
+			t.Errorf("NextNotifyTime(%q) should fail", notifyAt)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInsufficientPaidLeave 有給休暇の残日数が足りない
+	ErrInsufficientPaidLeave = errors.New("insufficient paid leave balance")
+	// ErrPaidLeaveAlreadyTaken 同じ日に1日を超えて有給休暇を取得しようとした
+	ErrPaidLeaveAlreadyTaken = errors.New("paid leave already taken on the date")
+	// ErrHourlyPaidLeaveLimit 時間単位の有給休暇は年5日分まで
+	ErrHourlyPaidLeaveLimit = errors.New("hourly paid leave exceeds the annual limit")
+)
+
+const (
+	paidLeaveFirstGrantMonths = 6  // 入社から最初の付与までの月数
+	paidLeaveValidMonths      = 24 // 付与から時効までの月数
+	// 年10日以上付与された従業員は、付与日から1年以内に5日を取得させなければならない
+	mandatoryPaidLeaveThreshold = 10
+	mandatoryPaidLeaveDays      = 5
+	// 週の所定労働時間がこれ未満かつ週の所定労働日数が4日以下の場合は比例付与
+	proportionalGrantWeeklyMinutes = 30 * 60
+	// 時間単位で取得できる年間の日数
+	hourlyPaidLeaveDaysLimit = 5
+	// 時間単位の取得を日数に換算した端数の比較に使う許容誤差
+	paidLeaveEpsilon = 1e-9
+)
+
+// paidLeaveGrantTable 継続勤務年数 (0.5年, 1.5年, …, 6.5年以上) ごとの付与日数
+var paidLeaveGrantTable = [7]int{10, 11, 12, 14, 16, 18, 20}
+
+// proportionalGrantTable 比例付与の週の所定労働日数ごとの付与日数
+var proportionalGrantTable = map[int][7]int{
+	4: {7, 8, 9, 10, 12, 13, 15},
+	3: {5, 6, 6, 8, 9, 10, 11},
+	2: {3, 4, 4, 5, 6, 6, 7},
+	1: {1, 2, 2, 2, 3, 3, 3},
+}
+
+// PaidLeaveGrantEntry 有給休暇の付与と、基準日時点の残日数
+type PaidLeaveGrantEntry struct {
+	models.PaidLeaveGrant
+	Remaining float64 `json:"remaining"`
+	Expired   bool    `json:"expired"`
+}
+
+// PaidLeaveObligation 年5日の取得義務の達成状況 (10日以上の付与ごと)
+type PaidLeaveObligation struct {
+	GrantDate    time.Time `json:"grant_date"`
+	Deadline     time.Time `json:"deadline"` // 付与日から1年を経過する日の前日
+	RequiredDays float64   `json:"required_days"`
+	TakenDays    float64   `json:"taken_days"`
+	Fulfilled    bool      `json:"fulfilled"`
+	Overdue      bool      `json:"overdue"` // 期限を過ぎても取得日数が足りない
+}
+
+// PaidLeaveLedger 従業員の年次有給休暇管理簿
+type PaidLeaveLedger struct {
+	EmployeeID  uint                    `json:"employee_id"`
+	HireDate    *time.Time              `json:"hire_date,omitempty"`
+	AsOf        time.Time               `json:"as_of"`
+	Balance     float64                 `json:"balance"`
+	Grants      []PaidLeaveGrantEntry   `json:"grants"`
+	Usages      []models.PaidLeaveUsage `json:"usages"`
+	Obligations []PaidLeaveObligation   `json:"obligations"`
+}
+
+// paidLeaveGrantDays n回目 (0始まり) の付与の日数
+// 出勤率8割以上の要件は満たしているものとして扱う
+func paidLeaveGrantDays(emp models.Employee, n int) float64 {
+	i := min(n, len(paidLeaveGrantTable)-1)
+	if emp.WeeklyWorkMinutes < proportionalGrantWeeklyMinutes {
+		if table, ok := proportionalGrantTable[emp.WeeklyWorkDays]; ok {
+			return float64(table[i])
+		}
+	}
+	return float64(paidLeaveGrantTable[i])
+}
+
+// grantDuePaidLeave 付与日が基準日以前で、まだ付与していない有給休暇を付与する
+// 入社日から6か月後、その後は1年ごとに付与する (基準日時点で時効となっているものは付与しない)
+func grantDuePaidLeave(db *gorm.DB, emp models.Employee, asOf time.Time) error {
+	if emp.HireDate == nil {
+		return nil
+	}
+	asOf = dateOnly(asOf)
+
+	var grants []models.PaidLeaveGrant
+	if err := db.Where("employee_id = ?", emp.ID).Find(&grants).Error; err != nil {
+		return err
+	}
+	granted := make(map[string]bool, len(grants))
+	for _, g := range grants {
+		granted[g.GrantDate.Format("2006-01-02")] = true
+	}
+
+	for _, grant := range paidLeaveGrantSchedule(emp, asOf) {
+		if granted[grant.GrantDate.Format("2006-01-02")] {
+			continue
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&grant).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paidLeaveGrantSchedule 基準日までに付与日を迎え、基準日時点で時効となっていない付与
+func paidLeaveGrantSchedule(emp models.Employee, asOf time.Time) []models.PaidLeaveGrant {
+	if emp.HireDate == nil {
+		return nil
+	}
+
+	var grants []models.PaidLeaveGrant
+	first := addMonthsToDate(dateOnly(*emp.HireDate), paidLeaveFirstGrantMonths)
+	for n := 0; ; n++ {
+		date := addMonthsToDate(first, 12*n)
+		if date.After(asOf) {
+			return grants
+		}
+		expiresOn := paidLeaveExpiresOn(date)
+		if expiresOn.Before(asOf) {
+			continue
+		}
+		grants = append(grants, models.PaidLeaveGrant{
+			EmployeeID: emp.ID,
+			GrantDate:  date,
+			ExpiresOn:  expiresOn,
+			Days:       paidLeaveGrantDays(emp, n),
+		})
+	}
+}
+
+// paidLeaveExpiresOn 付与日から2年を経過する日 (応当日の前日、応当日がない月はその月の末日)
+func paidLeaveExpiresOn(grantDate time.Time) time.Time {
+	end := addMonthsToDate(grantDate, paidLeaveValidMonths)
+	if end.Day() != grantDate.Day() {
+		return end
+	}
+	return end.AddDate(0, 0, -1)
+}
+
+// UsePaidLeave 指定した日に有給休暇を取得する (days は 1 または 0.5)
+// その日に有効な付与のうち、付与日の古いものから消化する
+func UsePaidLeave(db *gorm.DB, employeeID uint, date time.Time, days float64) ([]models.PaidLeaveUsage, error) {
+	if days != 1 && days != 0.5 {
+		return nil, errors.New("days must be 1 or 0.5")
+	}
+
+	var usages []models.PaidLeaveUsage
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		usages, err = usePaidLeave(tx, employeeID, date, days, 0)
+		return err
+	})
+	return usages, err
+}
+
+// usePaidLeave 有給休暇を取得し、付与日の古いものから消化する (トランザクション内で呼び出すこと)
+// 時間単位の取得は minutes に取得時間を渡し、days には日数に換算した値を渡す
+func usePaidLeave(tx *gorm.DB, employeeID uint, date time.Time, days float64, minutes int) ([]models.PaidLeaveUsage, error) {
+	date = dateOnly(date)
+
+	var emp models.Employee
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&emp, employeeID).Error; err != nil {
+		return nil, err
+	}
+	if err := grantDuePaidLeave(tx, emp, date); err != nil {
+		return nil, err
+	}
+
+	var taken float64
+	if err := tx.Model(&models.PaidLeaveUsage{}).
+		Where("employee_id = ? AND date = ?", employeeID, date).
+		Select("COALESCE(SUM(days), 0)").
+		Scan(&taken).Error; err != nil {
+		return nil, err
+	}
+	if taken+days > 1+paidLeaveEpsilon {
+		return nil, ErrPaidLeaveAlreadyTaken
+	}
+
+	var grants []models.PaidLeaveGrant
+	if err := tx.Where("employee_id = ? AND grant_date <= ? AND expires_on >= ? AND days - used_days > ?",
+		employeeID, date, date, paidLeaveEpsilon).
+		Order("grant_date ASC").
+		Find(&grants).Error; err != nil {
+		return nil, err
+	}
+
+	var balance float64
+	for _, g := range grants {
+		balance += g.Remaining()
+	}
+	if balance+paidLeaveEpsilon < days {
+		return nil, ErrInsufficientPaidLeave
+	}
+
+	usages := allocatePaidLeave(grants, employeeID, date, days, minutes)
+	for i := range usages {
+		for j := range grants {
+			if grants[j].ID != usages[i].GrantID {
+				continue
+			}
+			if err := tx.Save(&grants[j]).Error; err != nil {
+				return nil, err
+			}
+		}
+		if err := tx.Create(&usages[i]).Error; err != nil {
+			return nil, err
+		}
+	}
+	return usages, nil
+}
+
+// allocatePaidLeave 有効な付与 (付与日の古い順) から days 日分を消化し、付与ごとの取得を返す
+// grants の取得済み日数は消化した分を加えて更新する
+func allocatePaidLeave(grants []models.PaidLeaveGrant, employeeID uint, date time.Time, days float64, minutes int) []models.PaidLeaveUsage {
+	var usages []models.PaidLeaveUsage
+	rest, restMinutes := days, minutes
+	for i := range grants {
+		if rest <= paidLeaveEpsilon {
+			break
+		}
+		g := &grants[i]
+		use := math.Min(rest, g.Remaining())
+		if use <= paidLeaveEpsilon {
+			continue
+		}
+		usage := models.PaidLeaveUsage{EmployeeID: employeeID, GrantID: g.ID, Date: date, Days: use}
+		if minutes > 0 {
+			// 時間単位の取得が付与をまたぐ場合は、日数の割合で時間を分ける (最後の付与に残りを寄せる)
+			usage.Minutes = restMinutes
+			if rest-use > paidLeaveEpsilon {
+				usage.Minutes = int(math.Round(float64(minutes) * use / days))
+			}
+			restMinutes -= usage.Minutes
+		}
+
+		g.UsedDays = math.Min(g.UsedDays+use, g.Days)
+		usages = append(usages, usage)
+		rest -= use
+	}
+	return usages
+}
+
+// scheduledDailyMinutes 1日の所定労働時間 (週の所定労働時間÷週の所定労働日数)
+func scheduledDailyMinutes(emp models.Employee) int {
+	days, minutes := emp.WeeklyWorkDays, emp.WeeklyWorkMinutes
+	if days <= 0 || minutes <= 0 {
+		days, minutes = 5, 40*60
+	}
+	return minutes / days
+}
+
+// paidLeaveHoursPerDay 時間単位の有給休暇の1日分の時間数 (1日の所定労働時間の1時間未満を切り上げ)
+func paidLeaveHoursPerDay(emp models.Employee) int {
+	return max(int(math.Ceil(float64(scheduledDailyMinutes(emp))/60)), 1)
+}
+
+// hourlyPaidLeaveTaken 指定した日を含む付与の年 (直近の付与日から1年) に時間単位で取得した時間 (分)
+func hourlyPaidLeaveTaken(tx *gorm.DB, employeeID uint, date time.Time) (int, error) {
+	var grant models.PaidLeaveGrant
+	err := tx.Where("employee_id = ? AND grant_date <= ?", employeeID, date).
+		Order("grant_date DESC").
+		First(&grant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	from := dateOnly(grant.GrantDate)
+	var minutes int
+	err = tx.Model(&models.PaidLeaveUsage{}).
+		Where("employee_id = ? AND date >= ? AND date < ?", employeeID, from, addMonthsToDate(from, 12)).
+		Select("COALESCE(SUM(minutes), 0)").
+		Scan(&minutes).Error
+	return minutes, err
+}
+
+// GetPaidLeaveLedger 基準日時点の有給休暇の付与・取得・残日数と、年5日の取得義務の達成状況
+// 付与日を過ぎてまだ付与していない有給休暇があれば、付与してから集計する
+func GetPaidLeaveLedger(db *gorm.DB, employeeID uint, asOf time.Time) (PaidLeaveLedger, error) {
+	asOf = dateOnly(asOf)
+	ledger := PaidLeaveLedger{
+		EmployeeID:  employeeID,
+		AsOf:        asOf,
+		Grants:      []PaidLeaveGrantEntry{},
+		Usages:      []models.PaidLeaveUsage{},
+		Obligations: []PaidLeaveObligation{},
+	}
+
+	var emp models.Employee
+	if err := db.First(&emp, employeeID).Error; err != nil {
+		return ledger, err
+	}
+	ledger.HireDate = emp.HireDate
+	if err := grantDuePaidLeave(db, emp, asOf); err != nil {
+		return ledger, err
+	}
+
+	var grants []models.PaidLeaveGrant
+	if err := db.Where("employee_id = ? AND grant_date <= ?", employeeID, asOf).
+		Order("grant_date ASC").
+		Find(&grants).Error; err != nil {
+		return ledger, err
+	}
+	if err := db.Where("employee_id = ?", employeeID).
+		Order("date ASC, id ASC").
+		Find(&ledger.Usages).Error; err != nil {
+		return ledger, err
+	}
+
+	for _, g := range grants {
+		entry := PaidLeaveGrantEntry{PaidLeaveGrant: g, Expired: dateOnly(g.ExpiresOn).Before(asOf)}
+		if !entry.Expired {
+			entry.Remaining = math.Round(g.Remaining()*1e6) / 1e6
+			ledger.Balance += entry.Remaining
+		}
+		ledger.Grants = append(ledger.Grants, entry)
+
+		if g.Days >= mandatoryPaidLeaveThreshold {
+			ledger.Obligations = append(ledger.Obligations, paidLeaveObligation(g, ledger.Usages, asOf))
+		}
+	}
+	ledger.Balance = math.Round(ledger.Balance*1e6) / 1e6
+	return ledger, nil
+}
+
+// paidLeaveObligation 付与日から1年以内に1日・半日単位で取得した日数 (どの付与から消化したかは問わない) で取得義務の達成状況を求める
+func paidLeaveObligation(grant models.PaidLeaveGrant, usages []models.PaidLeaveUsage, asOf time.Time) PaidLeaveObligation {
+	from := dateOnly(grant.GrantDate)
+	deadline := addMonthsToDate(from, 12).AddDate(0, 0, -1)
+
+	o := PaidLeaveObligation{GrantDate: from, Deadline: deadline, RequiredDays: mandatoryPaidLeaveDays}
+	for _, u := range usages {
+		d := dateOnly(u.Date)
+		// 時間単位の取得は取得義務の日数に含めない
+		if u.Minutes == 0 && !d.Before(from) && !d.After(deadline) {
+			o.TakenDays += u.Days
+		}
+	}
+	o.Fulfilled = o.TakenDays >= o.RequiredDays
+	o.Overdue = !o.Fulfilled && asOf.After(deadline)
+	return o
+}
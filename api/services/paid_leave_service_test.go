@@ -0,0 +1,174 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestPaidLeaveGrantDays(t *testing.T) {
+	fullTime := models.Employee{WeeklyWorkDays: 5, WeeklyWorkMinutes: 2400}
+	partTime := func(days, minutes int) models.Employee {
+		return models.Employee{WeeklyWorkDays: days, WeeklyWorkMinutes: minutes}
+	}
+	tests := []struct {
+		name string
+		emp  models.Employee
+		n    int
+		want float64
+	}{
+		{"6か月", fullTime, 0, 10},
+		{"1年6か月", fullTime, 1, 11},
+		{"3年6か月", fullTime, 3, 14},
+		{"6年6か月", fullTime, 6, 20},
+		{"6年6か月以降は20日", fullTime, 10, 20},
+		{"週4日・20時間の比例付与", partTime(4, 20*60), 0, 7},
+		{"週3日の比例付与 (3年6か月)", partTime(3, 18*60), 3, 8},
+		{"週1日の比例付与 (6年6か月以降)", partTime(1, 6*60), 8, 3},
+		{"週30時間以上は日数が少なくても通常の付与", partTime(4, 32*60), 0, 10},
+		{"週5日以上は時間が短くても通常の付与", partTime(5, 25*60), 0, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paidLeaveGrantDays(tt.emp, tt.n); got != tt.want {
+				t.Errorf("paidLeaveGrantDays(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaidLeaveGrantSchedule(t *testing.T) {
+	hireDate := localDate(2022, time.April, 1)
+	emp := models.Employee{ID: 1, HireDate: &hireDate, WeeklyWorkDays: 5, WeeklyWorkMinutes: 2400}
+
+	type grant struct {
+		grantDate time.Time
+		expiresOn time.Time
+		days      float64
+	}
+	tests := []struct {
+		name string
+		emp  models.Employee
+		asOf time.Time
+		want []grant
+	}{
+		{"最初の付与日の前日", emp, localDate(2022, time.September, 30), nil},
+		{
+			"入社から6か月後に付与", emp, localDate(2022, time.October, 1),
+			[]grant{{localDate(2022, time.October, 1), localDate(2024, time.September, 30), 10}},
+		},
+		{
+			"2年後の前日まで有効", emp, localDate(2024, time.September, 30),
+			[]grant{
+				{localDate(2022, time.October, 1), localDate(2024, time.September, 30), 10},
+				{localDate(2023, time.October, 1), localDate(2025, time.September, 30), 11},
+			},
+		},
+		{
+			"時効となった付与は含めない", emp, localDate(2024, time.October, 1),
+			[]grant{
+				{localDate(2023, time.October, 1), localDate(2025, time.September, 30), 11},
+				{localDate(2024, time.October, 1), localDate(2026, time.September, 30), 12},
+			},
+		},
+		{"入社日がない", models.Employee{ID: 2}, localDate(2024, time.October, 1), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := paidLeaveGrantSchedule(tt.emp, tt.asOf)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d grants, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if !g.GrantDate.Equal(w.grantDate) || !g.ExpiresOn.Equal(w.expiresOn) || g.Days != w.days {
+					t.Errorf("grant %d = %s..%s %v days, want %s..%s %v days", i,
+						g.GrantDate.Format("2006-01-02"), g.ExpiresOn.Format("2006-01-02"), g.Days,
+						w.grantDate.Format("2006-01-02"), w.expiresOn.Format("2006-01-02"), w.days)
+				}
+			}
+		})
+	}
+}
+
+func TestPaidLeaveGrantScheduleMonthEnd(t *testing.T) {
+	hireDate := localDate(2023, time.August, 31)
+	emp := models.Employee{HireDate: &hireDate, WeeklyWorkDays: 5, WeeklyWorkMinutes: 2400}
+
+	got := paidLeaveGrantSchedule(emp, localDate(2024, time.March, 1))
+	if len(got) != 1 {
+		t.Fatalf("got %d grants, want 1", len(got))
+	}
+	// 8月31日入社の6か月後は2月の末日
+	if want := localDate(2024, time.February, 29); !got[0].GrantDate.Equal(want) {
+		t.Errorf("grant date = %s, want %s", got[0].GrantDate.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+	// 2月29日の付与は応当日がないため2年後の2月の末日まで有効
+	if want := localDate(2026, time.February, 28); !got[0].ExpiresOn.Equal(want) {
+		t.Errorf("expires on = %s, want %s", got[0].ExpiresOn.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+}
+
+func TestAllocatePaidLeave(t *testing.T) {
+	date := localDate(2025, time.June, 2)
+	grants := func(remaining ...float64) []models.PaidLeaveGrant {
+		var gs []models.PaidLeaveGrant
+		for i, r := range remaining {
+			gs = append(gs, models.PaidLeaveGrant{ID: uint(i + 1), Days: 10, UsedDays: 10 - r})
+		}
+		return gs
+	}
+
+	type usage struct {
+		grantID uint
+		days    float64
+		minutes int
+	}
+	tests := []struct {
+		name     string
+		grants   []models.PaidLeaveGrant
+		days     float64
+		minutes  int
+		want     []usage
+		wantUsed []float64
+	}{
+		{"古い付与から消化する", grants(3, 10), 1, 0, []usage{{1, 1, 0}}, []float64{8, 0}},
+		{"残りのない付与は飛ばす", grants(0, 10), 1, 0, []usage{{2, 1, 0}}, []float64{10, 1}},
+		{"半日で古い付与を使い切る", grants(0.5, 10), 0.5, 0, []usage{{1, 0.5, 0}}, []float64{10, 0}},
+		{"付与をまたぐ全日", grants(0.5, 10), 1, 0, []usage{{1, 0.5, 0}, {2, 0.5, 0}}, []float64{10, 0.5}},
+		{"時間単位", grants(3, 10), 0.25, 120, []usage{{1, 0.25, 120}}, []float64{7.25, 0}},
+		{
+			"付与をまたぐ時間単位は日数の割合で時間を分ける", grants(0.125, 10), 0.375, 180,
+			[]usage{{1, 0.125, 60}, {2, 0.25, 120}}, []float64{10, 0.25},
+		},
+		{
+			"端数は最後の付与に寄せる", grants(0.1, 10), 0.375, 180,
+			[]usage{{1, 0.1, 48}, {2, 0.275, 132}}, []float64{10, 0.275},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocatePaidLeave(tt.grants, 1, date, tt.days, tt.minutes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d usages, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				u := got[i]
+				if u.GrantID != w.grantID || math.Abs(u.Days-w.days) > paidLeaveEpsilon || u.Minutes != w.minutes {
+					t.Errorf("usage %d = grant %d %v days %d min, want grant %d %v days %d min",
+						i, u.GrantID, u.Days, u.Minutes, w.grantID, w.days, w.minutes)
+				}
+				if u.EmployeeID != 1 || !u.Date.Equal(date) {
+					t.Errorf("usage %d = employee %d on %s", i, u.EmployeeID, u.Date.Format("2006-01-02"))
+				}
+			}
+			for i, want := range tt.wantUsed {
+				if math.Abs(tt.grants[i].UsedDays-want) > paidLeaveEpsilon {
+					t.Errorf("grant %d used days = %v, want %v", i+1, tt.grants[i].UsedDays, want)
+				}
+			}
+		})
+	}
+}
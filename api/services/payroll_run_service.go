@@ -0,0 +1,265 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrPayrollRunLocked 締められた給与計算は再計算・再度の締めができない
+	ErrPayrollRunLocked = errors.New("payroll run is locked")
+	// ErrPayrollRunNotLocked 締められていない給与計算は締めを解除できない
+	ErrPayrollRunNotLocked = errors.New("payroll run is not locked")
+	// ErrPayrollRunNotComputed 給与明細が作成されていない給与計算は締められない
+	ErrPayrollRunNotComputed = errors.New("payroll run has not been computed")
+)
+
+// OpenPayrollRun 会社・月の給与計算を開始する (既にあればそれを返す)
+func OpenPayrollRun(db *gorm.DB, companyID uint, year, month int) (models.PayrollRun, error) {
+	var run models.PayrollRun
+	err := db.Where("company_id = ? AND year = ? AND month = ?", companyID, year, month).
+		Attrs(models.PayrollRun{
+			CompanyID: companyID,
+			Year:      year,
+			Month:     month,
+			Status:    models.PayrollRunOpen,
+		}).
+		FirstOrCreate(&run).Error
+	return run, err
+}
+
+// ComputePayrollRun 会社の全従業員の給与を計算し、給与明細を作り直す
+func ComputePayrollRun(db *gorm.DB, runID uint) (models.PayrollRun, error) {
+	var run models.PayrollRun
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPayrollRun(tx, runID, &run); err != nil {
+			return err
+		}
+		if run.Status == models.PayrollRunLocked {
+			return ErrPayrollRunLocked
+		}
+
+		if err := deletePayslips(tx, run.ID); err != nil {
+			return err
+		}
+
+		var company models.Company
+		if err := tx.First(&company, run.CompanyID).Error; err != nil {
+			return err
+		}
+		tables, err := loadRateTables(tx, company, run.Year, run.Month)
+		if err != nil {
+			return err
+		}
+
+		employees, err := loadPayrollEmployees(tx, run.CompanyID, run.Year, run.Month)
+		if err != nil {
+			return err
+		}
+
+		for _, emp := range employees {
+			payslip, err := buildPayslip(tx, emp, tables, run.Year, run.Month)
+			if err != nil {
+				return fmt.Errorf("%s: %w", emp.Name, err)
+			}
+			payslip.PayrollRunID = run.ID
+			if err := tx.Create(&payslip).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		run.ComputedAt = &now
+		return tx.Save(&run).Error
+	})
+	if err != nil {
+		return run, err
+	}
+	return GetPayrollRun(db, runID)
+}
+
+// LockPayrollRun 給与計算を締め、給与明細を確定する
+func LockPayrollRun(db *gorm.DB, runID, accountID uint) (models.PayrollRun, error) {
+	var run models.PayrollRun
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPayrollRun(tx, runID, &run); err != nil {
+			return err
+		}
+		if run.Status == models.PayrollRunLocked {
+			return ErrPayrollRunLocked
+		}
+
+		var count int64
+		if err := tx.Model(&models.Payslip{}).Where("payroll_run_id = ?", run.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrPayrollRunNotComputed
+		}
+
+		// フレックスタイム制の清算期間が終わる従業員は、次の清算期間への繰り越しを確定する
+		if err := saveFlextimeCarryOvers(tx, run); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		run.Status = models.PayrollRunLocked
+		run.LockedAt = &now
+		run.LockedByAccountID = &accountID
+		return tx.Save(&run).Error
+	})
+	return run, err
+}
+
+// ReopenPayrollRun 締めを解除し、対象月の勤務記録・手当の変更と再計算をできるようにする
+// 確定していた給与明細は削除し、再計算するまで締められないようにする
+func ReopenPayrollRun(db *gorm.DB, runID uint) (models.PayrollRun, error) {
+	var run models.PayrollRun
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPayrollRun(tx, runID, &run); err != nil {
+			return err
+		}
+		if run.Status != models.PayrollRunLocked {
+			return ErrPayrollRunNotLocked
+		}
+
+		if err := deleteFlextimeCarryOvers(tx, run); err != nil {
+			return err
+		}
+		if err := deletePayslips(tx, run.ID); err != nil {
+			return err
+		}
+
+		run.Status = models.PayrollRunOpen
+		run.ComputedAt = nil
+		run.LockedAt = nil
+		run.LockedByAccountID = nil
+		return tx.Save(&run).Error
+	})
+	return run, err
+}
+
+// GetPayrollRun 給与明細と各項目を含めて給与計算を取得
+func GetPayrollRun(db *gorm.DB, runID uint) (models.PayrollRun, error) {
+	var run models.PayrollRun
+	err := db.
+		Preload("Payslips", func(db *gorm.DB) *gorm.DB { return db.Order("employee_id ASC") }).
+		Preload("Payslips.Items", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		First(&run, runID).Error
+	return run, err
+}
+
+// LockedPayroll 締められた給与計算があれば、その給与明細の計算結果を返す (なければ nil)
+func LockedPayroll(db *gorm.DB, employeeID uint, year, month int) (*PayrollCalculationResponse, error) {
+	var payslip models.Payslip
+	err := db.
+		Joins("JOIN payroll_runs ON payroll_runs.id = payslips.payroll_run_id").
+		Where("payslips.employee_id = ? AND payroll_runs.year = ? AND payroll_runs.month = ? AND payroll_runs.status = ?",
+			employeeID, year, month, models.PayrollRunLocked).
+		First(&payslip).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var resp PayrollCalculationResponse
+	if err := json.Unmarshal([]byte(payslip.Snapshot), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PayrollForMonth 給与計算が締められた月は確定した給与明細の内容を、それ以外は計算結果を返す
+func PayrollForMonth(db *gorm.DB, employeeID uint, year, month int) (PayrollCalculationResponse, error) {
+	locked, err := LockedPayroll(db, employeeID, year, month)
+	if err != nil {
+		return PayrollCalculationResponse{}, err
+	}
+	if locked != nil {
+		return *locked, nil
+	}
+	return CalculatePayroll(db, employeeID, year, month)
+}
+
+// lockPayrollRun 給与計算の行をロックして取得する (同時に再計算・締めが行われないようにする)
+func lockPayrollRun(tx *gorm.DB, runID uint, run *models.PayrollRun) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(run, runID).Error
+}
+
+// deletePayslips 給与計算の給与明細と各項目を削除
+func deletePayslips(tx *gorm.DB, runID uint) error {
+	if err := tx.Where("payslip_id IN (?)",
+		tx.Model(&models.Payslip{}).Select("id").Where("payroll_run_id = ?", runID)).
+		Delete(&models.PayslipItem{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("payroll_run_id = ?", runID).Delete(&models.Payslip{}).Error
+}
+
+// buildPayslip 従業員の給与を計算し、計算結果と支給・控除の各項目を持つ給与明細を作成する
+func buildPayslip(db *gorm.DB, emp models.Employee, tables rateTables, year, month int) (models.Payslip, error) {
+	resp, err := calculatePayroll(db, emp, tables, year, month)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+
+	snapshot, err := json.Marshal(resp)
+	if err != nil {
+		return models.Payslip{}, err
+	}
+
+	return models.Payslip{
+		EmployeeID:      emp.ID,
+		EmployeeName:    resp.EmployeeName,
+		GrossSalary:     resp.GrossSalary,
+		TotalDeductions: resp.TotalDeductions,
+		NetSalary:       resp.NetSalary,
+		Snapshot:        string(snapshot),
+		Items:           payslipItems(emp, resp),
+	}, nil
+}
+
+// payslipItems 計算結果から給与明細の支給・控除の各項目を作る (基本給以外の0円の項目は含めない)
+func payslipItems(emp models.Employee, resp PayrollCalculationResponse) []models.PayslipItem {
+	var items []models.PayslipItem
+	add := func(category models.PayslipItemCategory, code, label string, amount float64) {
+		if amount == 0 && code != "base_salary" {
+			return
+		}
+		items = append(items, models.PayslipItem{
+			Category:  category,
+			Code:      code,
+			Label:     label,
+			Amount:    amount,
+			SortOrder: len(items) + 1,
+		})
+	}
+
+	add(models.PayslipEarning, "base_salary", "基本給", resp.BaseSalary)
+	for _, ea := range emp.Allowances {
+		add(models.PayslipEarning, fmt.Sprintf("allowance_%d", ea.AllowanceTypeID), ea.AllowanceType.Name, allowanceAmount(ea))
+	}
+	add(models.PayslipEarning, "overtime", "時間外手当", resp.OvertimePay)
+	add(models.PayslipEarning, "excess_overtime", "時間外手当 (月60時間超)", resp.ExcessOvertimePay)
+	add(models.PayslipEarning, "late_night", "深夜手当", resp.LateNightPay)
+	add(models.PayslipEarning, "holiday", "休日手当", resp.HolidayPay)
+
+	add(models.PayslipDeduction, "health_insurance", "健康保険料", resp.HealthInsurance)
+	add(models.PayslipDeduction, "pension", "厚生年金保険料", resp.Pension)
+	add(models.PayslipDeduction, "employment_insurance", "雇用保険料", resp.EmploymentInsurance)
+	add(models.PayslipDeduction, "withholding_tax", "源泉所得税", resp.WithholdingTax)
+	add(models.PayslipDeduction, "resident_tax", "住民税", resp.ResidentTax)
+	if resp.YearEndAdjustment < 0 {
+		add(models.PayslipDeduction, "year_end_adjustment", "年末調整還付額", resp.YearEndAdjustment)
+	} else {
+		add(models.PayslipDeduction, "year_end_adjustment", "年末調整徴収額", resp.YearEndAdjustment)
+	}
+	return items
+}
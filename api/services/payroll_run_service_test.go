@@ -0,0 +1,110 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/db/dbtest"
+	"github.com/t2469/attendance-system.git/models"
+)
+
+// payrollRunRow 給与計算の行を返す結果
+func payrollRunRow(status models.PayrollRunStatus) dbtest.Result {
+	return dbtest.Result{
+		Columns: []string{"id", "company_id", "year", "month", "status", "computed_at", "locked_at"},
+		Rows: [][]any{{int64(7), int64(1), int64(2025), int64(5), string(status),
+			time.Date(2025, time.May, 25, 10, 0, 0, 0, time.Local), time.Date(2025, time.May, 26, 10, 0, 0, 0, time.Local)}},
+	}
+}
+
+// issued SQLに contains を含むクエリが発行された順番 (発行されていなければ -1)
+func issued(rec *dbtest.Recorder, contains string) int {
+	for i, q := range rec.Queries() {
+		if strings.Contains(q.SQL, contains) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestReopenPayrollRun(t *testing.T) {
+	db, rec := dbtest.Open(t)
+	rec.On(`FROM "payroll_runs"`, payrollRunRow(models.PayrollRunLocked))
+	rec.On(`UPDATE "payroll_runs"`, dbtest.Result{RowsAffected: 1})
+
+	run, err := ReopenPayrollRun(db, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.Status != models.PayrollRunOpen || run.LockedAt != nil || run.ComputedAt != nil {
+		t.Errorf("run = %+v, want open and not computed", run)
+	}
+
+	// 繰り越しは給与明細の従業員から求めるため、給与明細より先に削除する
+	carryOvers := issued(rec, `DELETE FROM "flextime_carry_overs"`)
+	items := issued(rec, `DELETE FROM "payslip_items"`)
+	payslips := issued(rec, `DELETE FROM "payslips"`)
+	update := issued(rec, `UPDATE "payroll_runs"`)
+	if carryOvers < 0 || items < 0 || payslips < 0 || update < 0 {
+		t.Fatalf("queries = %v", rec.Queries())
+	}
+	if !(carryOvers < items && items < payslips && payslips < update) {
+		t.Errorf("deleted in wrong order: carry overs %d, items %d, payslips %d, update %d", carryOvers, items, payslips, update)
+	}
+	if q, _ := rec.Find(`DELETE FROM "payslips"`); len(q.Args) != 1 || q.Args[0] != uint(7) {
+		t.Errorf("payslips delete args = %v, want [7]", q.Args)
+	}
+}
+
+func TestReopenPayrollRunNotLocked(t *testing.T) {
+	db, rec := dbtest.Open(t)
+	rec.On(`FROM "payroll_runs"`, payrollRunRow(models.PayrollRunOpen))
+
+	if _, err := ReopenPayrollRun(db, 7); !errors.Is(err, ErrPayrollRunNotLocked) {
+		t.Fatalf("error = %v, want ErrPayrollRunNotLocked", err)
+	}
+	if i := issued(rec, "DELETE"); i >= 0 {
+		t.Errorf("open run was modified: %v", rec.Queries()[i].SQL)
+	}
+}
+
+func TestPayrollForMonthLocked(t *testing.T) {
+	want := PayrollCalculationResponse{
+		EmployeeName:   "山田 太郎",
+		GrossSalary:    310000,
+		PayType:        models.PayTypeMonthly,
+		WageRate:       300000,
+		BaseSalary:     300000,
+		TotalAllowance: 10000,
+		WithholdingTax: 7000,
+		NetSalary:      260000,
+	}
+	snapshot, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, rec := dbtest.Open(t)
+	rec.On(`FROM "payslips"`, dbtest.Result{
+		Columns: []string{"id", "payroll_run_id", "employee_id", "snapshot"},
+		Rows:    [][]any{{int64(3), int64(7), int64(1), string(snapshot)}},
+	})
+
+	got, err := PayrollForMonth(db, 1, 2025, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("PayrollForMonth = %+v, want %+v", got, want)
+	}
+	// 締められた月は再計算しない
+	if queries := rec.Queries(); len(queries) != 1 {
+		t.Errorf("issued %d queries, want only the payslip lookup", len(queries))
+	}
+	if q, _ := rec.Find(`FROM "payslips"`); len(q.Args) < 4 || q.Args[3] != models.PayrollRunLocked {
+		t.Errorf("payslip lookup args = %v, want only locked runs", q.Args)
+	}
+}
@@ -4,83 +4,388 @@ import (
 	"github.com/t2469/attendance-system.git/models"
 	"gorm.io/gorm"
 	"log"
+	"math"
+	"sort"
 )
 
 type PayrollCalculationResponse struct {
-	EmployeeName    string  `json:"employee_name"`
-	GrossSalary     float64 `json:"gross_salary"`
-	TotalAllowance  float64 `json:"total_allowance"`
-	HealthInsurance float64 `json:"health_insurance"`
-	Pension         float64 `json:"pension"`
-	TotalDeductions float64 `json:"total_deductions"`
-	NetSalary       float64 `json:"net_salary"`
+	EmployeeName        string  `json:"employee_name"`
+	GrossSalary         float64 `json:"gross_salary"`
+	PayType             string  `json:"pay_type"`
+	WageRate            int     `json:"wage_rate"`    // 月給・日給・時給の額
+	WorkDays            int     `json:"work_days"`    // 出勤日数
+	WorkMinutes         int64   `json:"work_minutes"` // 時給の支払の対象とした労働時間 (時給者のみ)
+	BaseSalary          float64 `json:"base_salary"`
+	TotalAllowance      float64 `json:"total_allowance"`
+	NonTaxableAllowance float64 `json:"non_taxable_allowance"` // 手当のうち所得税の非課税分
+	HourlyRate          float64 `json:"hourly_rate"`
+	OvertimePay         float64 `json:"overtime_pay"`
+	ExcessOvertimePay   float64 `json:"excess_overtime_pay"`
+	LateNightPay        float64 `json:"late_night_pay"`
+	HolidayPay          float64 `json:"holiday_pay"`
+	HealthInsurance     float64 `json:"health_insurance"`
+	Pension             float64 `json:"pension"`
+	EmploymentInsurance float64 `json:"employment_insurance"`
+	TaxableSalary       float64 `json:"taxable_salary"`
+	WithholdingTax      float64 `json:"withholding_tax"`
+	ResidentTax         float64 `json:"resident_tax"`
+	YearEndAdjustment   float64 `json:"year_end_adjustment"` // 12月に精算する年末調整の過不足額 (正:徴収, 負:還付)
+	TotalDeductions     float64 `json:"total_deductions"`
+	NetSalary           float64 `json:"net_salary"`
+
+	// 事業主負担分 (給与からは控除しない)
+	EmployerHealthInsurance     float64 `json:"employer_health_insurance"`
+	EmployerPension             float64 `json:"employer_pension"`
+	EmployerEmploymentInsurance float64 `json:"employer_employment_insurance"`
 }
 
 func CalculatePayroll(db *gorm.DB, employeeID uint, year, month int) (PayrollCalculationResponse, error) {
-	var emp models.Employee
-	if err := db.
-		Preload("Company.Prefecture.HealthInsuranceRates").
-		Preload("Company.Prefecture.PensionInsuranceRates").
-		Preload("Allowances", "year = ? AND month = ?", year, month).
-		Preload("Allowances.AllowanceType").
-		First(&emp, employeeID).Error; err != nil {
+	emp, err := loadPayrollEmployee(db, employeeID, year, month)
+	if err != nil {
 		return PayrollCalculationResponse{}, err
 	}
 
-	totalAllowance := calculateTotalAllowance(emp.Allowances)
+	tables, err := loadRateTables(db, emp.Company, year, month)
+	if err != nil {
+		return PayrollCalculationResponse{}, err
+	}
+
+	return calculatePayroll(db, emp, tables, year, month)
+}
 
-	healthResp, err := CalculateInsurance(db, employeeID, year, month)
+// calculatePayroll 読み込み済みの従業員・保険料額表で給与を計算する (従業員は loadPayrollEmployee で取得しておくこと)
+func calculatePayroll(db *gorm.DB, emp models.Employee, tables rateTables, year, month int) (PayrollCalculationResponse, error) {
+	// 支給額(基本給+手当合計+割増賃金)
+	earnings, err := calculateEarnings(db, emp, year, month)
 	if err != nil {
 		return PayrollCalculationResponse{}, err
 	}
+	grossSalary := earnings.Gross()
 
-	pensionResp, err := CalculatePension(db, employeeID, year, month)
+	standard, err := StandardRemunerationInForce(db, emp.ID, year, month)
 	if err != nil {
 		return PayrollCalculationResponse{}, err
 	}
 
-	// 支給額(基本給+手当合計)
-	baseSalary := emp.MonthlySalary
-	grossSalary := float64(baseSalary) + totalAllowance
+	healthResp, err := calculateHealthInsurance(emp, standard, earnings.remuneration(), tables, year, month)
+	if err != nil {
+		return PayrollCalculationResponse{}, err
+	}
 
-	// 控除額(健康保険,介護保険,厚生年金)
-	totalDeductions := healthResp.EmployeeHealth + pensionResp.EmployeePension
+	pensionResp, err := calculatePensionInsurance(emp, standard, earnings.remuneration(), tables, year, month)
+	if err != nil {
+		return PayrollCalculationResponse{}, err
+	}
+
+	employmentResp, err := calculateEmploymentInsurance(emp, grossSalary, tables)
+	if err != nil {
+		return PayrollCalculationResponse{}, err
+	}
+
+	// 非課税の手当と社会保険料等を控除した給与等の金額から源泉所得税を求める
+	socialInsurance := healthResp.EmployeeHealth + pensionResp.EmployeePension + employmentResp.EmployeeShare
+	taxableSalary := grossSalary - earnings.NonTaxableAllowance - socialInsurance
+	withholdingTax, err := CalculateWithholdingTax(db, taxableSalary, emp.Dependents, emp.TaxTable, year)
+	if err != nil {
+		return PayrollCalculationResponse{}, err
+	}
+
+	// 住民税 (特別徴収)
+	residentTax, err := residentTaxForMonth(db, emp.ID, year, month)
+	if err != nil {
+		return PayrollCalculationResponse{}, err
+	}
+
+	// 年末調整の過不足額 (12月の給与で徴収・還付する)
+	var yearEndAdjustment int
+	if month == 12 {
+		yearEndAdjustment, err = yearEndAdjustmentDifference(db, emp, year)
+		if err != nil {
+			return PayrollCalculationResponse{}, err
+		}
+	}
+
+	// 控除額(健康保険,介護保険,厚生年金,雇用保険,源泉所得税,住民税,年末調整の過不足額)
+	totalDeductions := socialInsurance + float64(withholdingTax) + float64(residentTax) + float64(yearEndAdjustment)
 
 	// 手取り給与
 	netSalary := grossSalary - totalDeductions
 
 	resp := PayrollCalculationResponse{
-		EmployeeName:    healthResp.EmployeeName,
-		GrossSalary:     grossSalary,
-		TotalAllowance:  totalAllowance,
-		HealthInsurance: healthResp.EmployeeHealth,
-		Pension:         pensionResp.EmployeePension,
-		TotalDeductions: totalDeductions,
-		NetSalary:       netSalary,
+		EmployeeName:                healthResp.EmployeeName,
+		GrossSalary:                 grossSalary,
+		PayType:                     earnings.PayType,
+		WageRate:                    earnings.Rate,
+		WorkDays:                    earnings.WorkDays,
+		WorkMinutes:                 earnings.WorkMinutes,
+		BaseSalary:                  earnings.BaseSalary,
+		TotalAllowance:              earnings.Allowance,
+		NonTaxableAllowance:         earnings.NonTaxableAllowance,
+		HourlyRate:                  earnings.Premium.HourlyRate,
+		OvertimePay:                 earnings.Premium.OvertimePay,
+		ExcessOvertimePay:           earnings.Premium.ExcessOvertimePay,
+		LateNightPay:                earnings.Premium.LateNightPay,
+		HolidayPay:                  earnings.Premium.HolidayPay,
+		HealthInsurance:             healthResp.EmployeeHealth,
+		Pension:                     pensionResp.EmployeePension,
+		EmploymentInsurance:         employmentResp.EmployeeShare,
+		TaxableSalary:               taxableSalary,
+		WithholdingTax:              float64(withholdingTax),
+		ResidentTax:                 float64(residentTax),
+		YearEndAdjustment:           float64(yearEndAdjustment),
+		TotalDeductions:             totalDeductions,
+		NetSalary:                   netSalary,
+		EmployerHealthInsurance:     healthResp.EmployerHealth,
+		EmployerPension:             pensionResp.EmployerPension,
+		EmployerEmploymentInsurance: employmentResp.EmployerShare,
 	}
 	return resp, nil
 }
 
-func calculateTotalAllowance(allowances []models.EmployeeAllowance) float64 {
-	var total float64
+// earnings 支給額の内訳
+type earnings struct {
+	PayType     string
+	Rate        int // 月給・日給・時給の額
+	WorkDays    int
+	WorkMinutes int64 // 時給者の支払の対象とした労働時間と休暇の時間 (端数処理後)
+	BaseSalary  float64
+	Allowance   float64
+	Premium     PremiumPay
+
+	NonTaxableAllowance float64 // 手当のうち所得税の非課税分
+	ExcludedAllowance   float64 // 社会保険の報酬に含めない手当
+}
+
+func (e earnings) Gross() float64 {
+	return e.BaseSalary + e.Allowance + e.Premium.Total()
+}
+
+// remuneration 標準報酬月額の履歴がない場合に等級を求める報酬月額 (基本給+社会保険の報酬に含める手当)
+func (e earnings) remuneration() int {
+	return int(math.Round(e.BaseSalary + e.Allowance - e.ExcludedAllowance))
+}
+
+// insurableGross 社会保険の報酬とする支給額 (報酬に含めない手当を除く)
+func (e earnings) insurableGross() float64 {
+	return e.Gross() - e.ExcludedAllowance
+}
+
+// loadPayrollEmployee 給与計算に必要な会社と対象月の手当を含めて従業員を取得
+func loadPayrollEmployee(db *gorm.DB, employeeID uint, year, month int) (models.Employee, error) {
+	var emp models.Employee
+	if err := payrollEmployeeQuery(db, year, month).First(&emp, employeeID).Error; err != nil {
+		return emp, err
+	}
+	emp.Allowances = effectiveAllowances(emp, year, month)
+	return emp, nil
+}
+
+// loadPayrollEmployees 給与計算に必要な会社と対象月の手当を含めて会社の全従業員を取得
+func loadPayrollEmployees(db *gorm.DB, companyID uint, year, month int) ([]models.Employee, error) {
+	var employees []models.Employee
+	if err := payrollEmployeeQuery(db, year, month).
+		Where("company_id = ?", companyID).
+		Order("id ASC").
+		Find(&employees).Error; err != nil {
+		return nil, err
+	}
+	for i := range employees {
+		employees[i].Allowances = effectiveAllowances(employees[i], year, month)
+	}
+	return employees, nil
+}
+
+// payrollEmployeeQuery 会社と、対象月の手当・継続手当を読み込む従業員のクエリ
+func payrollEmployeeQuery(db *gorm.DB, year, month int) *gorm.DB {
+	key := year*12 + month - 1
+	return db.
+		Preload("Company").
+		Preload("Allowances", "year = ? AND month = ?", year, month).
+		Preload("Allowances.AllowanceType").
+		Preload("RecurringAllowances", "from_year * 12 + from_month - 1 <= ? AND (to_year IS NULL OR to_year * 12 + to_month - 1 >= ?)", key, key).
+		Preload("RecurringAllowances.AllowanceType")
+}
+
+// calculateEarnings 基本給・手当・割増賃金を求める (従業員は loadPayrollEmployee で取得しておくこと)
+// 月給者は月給を、日給者は日給×出勤日数を、時給者は時給×月の合計労働時間を基本給とする
+// 割増賃金の時間単価は、月給者は月給÷月平均所定労働時間、日給者は日給÷1日の法定労働時間、時給者は時給とし、
+// 割増賃金の算定基礎に含める手当があれば、その合計÷月平均所定労働時間を加える
+func calculateEarnings(db *gorm.DB, emp models.Employee, year, month int) (earnings, error) {
+	records, err := monthlyWorkRecords(db, emp.ID, year, month)
+	if err != nil {
+		return earnings{}, err
+	}
+
+	overtime, err := monthlyOvertimeMinutes(db, emp, records, year, month)
+	if err != nil {
+		return earnings{}, err
+	}
+
+	// 月給・日給・時給の額は、対象月に適用される履歴から求める (過去の月の再計算に現在の額を使わないため)
+	payType, rate, err := compensationInForce(db, emp, year, month)
+	if err != nil {
+		return earnings{}, err
+	}
+
+	allowances := calculateTotalAllowance(emp.Allowances)
+	e := earnings{
+		PayType:             payType,
+		Rate:                rate,
+		WorkDays:            workDays(records),
+		Allowance:           allowances.Total,
+		NonTaxableAllowance: allowances.NonTaxable,
+		ExcludedAllowance:   allowances.ExcludedFromRemuneration,
+	}
+	return payByType(emp.Company, e, records, overtime, allowances.OvertimeBase), nil
+}
+
+// payByType 支払形態に従って基本給と割増賃金を求める (e には支払形態・額・出勤日数・手当を設定しておくこと)
+// overtimeBase は割増賃金の算定基礎に含める手当の合計
+func payByType(company models.Company, e earnings, records []models.WorkRecord, overtime int64, overtimeBase float64) earnings {
+	// 月ごとに支払う手当の時間単価 (月の手当の額÷月平均所定労働時間)
+	var allowanceHourly float64
+	if company.ScheduledMonthlyHours > 0 {
+		allowanceHourly = overtimeBase / company.ScheduledMonthlyHours
+	}
+
+	switch e.PayType {
+	case models.PayTypeDaily:
+		e.BaseSalary = float64(e.Rate * e.WorkDays)
+		var hourly float64
+		if company.LegalDailyMinutes > 0 {
+			hourly = float64(e.Rate) / (float64(company.LegalDailyMinutes) / 60)
+		}
+		e.Premium = calculatePremiumPay(company, hourly+allowanceHourly, records, overtime, false)
+	case models.PayTypeHourly:
+		// 有給休暇・特別休暇の時間も労働時間と同じく時給を支払う
+		for _, r := range records {
+			e.WorkMinutes += r.WorkMinutes + r.LeaveMinutes
+		}
+		e.WorkMinutes = roundWorkMinutes(company, e.WorkMinutes)
+		e.BaseSalary = roundWage(company, float64(e.Rate)*float64(e.WorkMinutes)/60)
+		// 労働時間分の賃金は基本給に含まれるため、時間外・休日労働は割増分のみを支払う
+		e.Premium = calculatePremiumPay(company, float64(e.Rate)+allowanceHourly, records, overtime, true)
+	default:
+		e.BaseSalary = float64(e.Rate)
+		var hourly float64
+		if company.ScheduledMonthlyHours > 0 {
+			hourly = e.BaseSalary / company.ScheduledMonthlyHours
+		}
+		e.Premium = calculatePremiumPay(company, hourly+allowanceHourly, records, overtime, false)
+	}
+	return e
+}
+
+// workDays 勤務記録のある日数 (出勤日数と休暇の日数。日給の支払や支払基礎日数の対象となる日数)
+func workDays(records []models.WorkRecord) int {
+	days := make(map[string]bool)
+	for _, r := range records {
+		days[r.Date.Format("2006-01-02")] = true
+	}
+	return len(days)
+}
+
+// allowanceTotals 手当の合計と、所得税・社会保険・割増賃金の算定でそれぞれ扱いが異なる額
+type allowanceTotals struct {
+	Total                    float64
+	NonTaxable               float64 // 所得税の非課税分 (種類ごとの上限まで)
+	ExcludedFromRemuneration float64 // 社会保険の報酬に含めない額
+	OvertimeBase             float64 // 割増賃金の算定基礎に含める額
+}
+
+// calculateTotalAllowance 手当の合計を、手当の種類の設定に従って所得税・社会保険・割増賃金の対象ごとに集計する
+// 非課税の上限は手当の種類ごとの月の合計に対して適用する (同じ種類の手当が複数あっても上限は1回分)
+func calculateTotalAllowance(allowances []models.EmployeeAllowance) allowanceTotals {
+	var totals allowanceTotals
+	nonTaxable := make(map[uint]float64)
+	limits := make(map[uint]int)
+	var typeIDs []uint
 	for _, ea := range allowances {
-		switch ea.AllowanceType.Type {
-		case "commission":
-			// 従業員ごとに設定された割合があればそれを使用、なければデフォルトの値を使用
-			var rate float64
-			if ea.CommissionRate != nil {
-				rate = *ea.CommissionRate
-			} else if ea.AllowanceType.CommissionRate != nil {
-				rate = *ea.AllowanceType.CommissionRate
-			} else {
-				rate = 0
+		amount := allowanceAmount(ea)
+		totals.Total += amount
+
+		at := ea.AllowanceType
+		if at.NonTaxable {
+			if _, ok := nonTaxable[ea.AllowanceTypeID]; !ok {
+				typeIDs = append(typeIDs, ea.AllowanceTypeID)
 			}
-			total += float64(ea.Amount) * rate
-		case "fixed":
-			total += float64(ea.Amount)
-		default:
-			log.Printf("unknown allowance type: %s", ea.AllowanceType.Type)
+			nonTaxable[ea.AllowanceTypeID] += amount
+			limits[ea.AllowanceTypeID] = at.NonTaxableLimit
+		}
+		if at.ExcludeFromStandardRemuneration {
+			totals.ExcludedFromRemuneration += amount
+		}
+		if at.IncludeInOvertimeBase {
+			totals.OvertimeBase += amount
+		}
+	}
+
+	for _, id := range typeIDs {
+		amount := nonTaxable[id]
+		if limit := limits[id]; limit > 0 {
+			amount = min(amount, float64(limit))
+		}
+		totals.NonTaxable += amount
+	}
+	return totals
+}
+
+// effectiveAllowances 対象月に支給する手当
+// 支給期間に対象月を含む継続手当と、その月だけの手当を合わせる (同じ種類の場合は、その月だけの手当で継続手当を置き換える)
+func effectiveAllowances(emp models.Employee, year, month int) []models.EmployeeAllowance {
+	overridden := make(map[uint]bool, len(emp.Allowances))
+	for _, ea := range emp.Allowances {
+		if ea.Year == year && ea.Month == month {
+			overridden[ea.AllowanceTypeID] = true
+		}
+	}
+
+	allowances := make([]models.EmployeeAllowance, 0, len(emp.RecurringAllowances)+len(emp.Allowances))
+	for _, ra := range emp.RecurringAllowances {
+		if !ra.Covers(year, month) || overridden[ra.AllowanceTypeID] {
+			continue
+		}
+		allowances = append(allowances, models.EmployeeAllowance{
+			EmployeeID:      ra.EmployeeID,
+			AllowanceTypeID: ra.AllowanceTypeID,
+			Amount:          ra.Amount,
+			CommissionRate:  ra.CommissionRate,
+			Year:            year,
+			Month:           month,
+			AllowanceType:   ra.AllowanceType,
+		})
+	}
+	for _, ea := range emp.Allowances {
+		if ea.Year == year && ea.Month == month {
+			allowances = append(allowances, ea)
+		}
+	}
+
+	// 給与明細の支給項目の並びを手当の種類で揃える
+	sort.SliceStable(allowances, func(i, j int) bool {
+		return allowances[i].AllowanceTypeID < allowances[j].AllowanceTypeID
+	})
+	return allowances
+}
+
+// allowanceAmount 手当1件の支給額
+func allowanceAmount(ea models.EmployeeAllowance) float64 {
+	switch ea.AllowanceType.Type {
+	case "commission":
+		// 従業員ごとに設定された割合があればそれを使用、なければデフォルトの値を使用
+		var rate float64
+		if ea.CommissionRate != nil {
+			rate = *ea.CommissionRate
+		} else if ea.AllowanceType.CommissionRate != nil {
+			rate = *ea.AllowanceType.CommissionRate
+		} else {
+			rate = 0
 		}
+		return float64(ea.Amount) * rate
+	case "fixed":
+		return float64(ea.Amount)
+	default:
+		log.Printf("unknown allowance type: %s", ea.AllowanceType.Type)
+		return 0
 	}
-	return total
 }
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestPayByType(t *testing.T) {
+	records := []models.WorkRecord{
+		{Date: localDate(2025, time.June, 2), WorkMinutes: 487},
+		{Date: localDate(2025, time.June, 3), LeaveType: models.WorkRecordPaidLeave, LeaveMinutes: 480},
+		{Date: localDate(2025, time.June, 4), WorkMinutes: 245},
+	}
+	tests := []struct {
+		name         string
+		payType      string
+		rate         int
+		unit         int
+		mode         string
+		overtime     int64
+		overtimeBase float64
+		wantMinutes  int64
+		wantBase     float64
+		wantHourly   float64
+		wantOvertime float64
+	}{
+		// 1234円×1212分÷60 = 24926.8円
+		{"時給は休暇の時間を含めた月の合計に支払う", models.PayTypeHourly, 1234, 1, models.WageRoundingRound, 0, 0, 1212, 24927, 1234, 0},
+		// 1212分は15分単位で1215分、1234円×1215分÷60 = 24988.5円
+		{"時給の労働時間を15分単位で丸める", models.PayTypeHourly, 1234, 15, models.WageRoundingRound, 0, 0, 1215, 24989, 1234, 0},
+		{"時給の1円未満を切り捨てる", models.PayTypeHourly, 1234, 15, models.WageRoundingFloor, 0, 0, 1215, 24988, 1234, 0},
+		{"時給の1円未満を切り上げる", models.PayTypeHourly, 1234, 1, models.WageRoundingCeil, 0, 0, 1212, 24927, 1234, 0},
+		// (1234円+16000円÷160時間)×0.25×60分 = 333.5円
+		{"時給者の時間外は割増分のみ", models.PayTypeHourly, 1234, 1, models.WageRoundingRound, 60, 16000, 1212, 24927, 1334, 334},
+		// 10000円÷8時間×1.25×60分 = 1562.5円
+		{"日給は出勤日数分", models.PayTypeDaily, 10000, 1, models.WageRoundingRound, 60, 0, 0, 30000, 1250, 1563},
+		// (320000円+16000円)÷160時間×1.25×60分 = 2625円
+		{"月給は額のまま", models.PayTypeMonthly, 320000, 1, models.WageRoundingRound, 60, 16000, 0, 320000, 2100, 2625},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			company := testCompany()
+			company.ScheduledMonthlyHours = 160
+			company.WorkMinutesRoundingUnit = tt.unit
+			company.WageRoundingMode = tt.mode
+
+			e := earnings{PayType: tt.payType, Rate: tt.rate, WorkDays: workDays(records)}
+			got := payByType(company, e, records, tt.overtime, tt.overtimeBase)
+			if got.WorkMinutes != tt.wantMinutes || got.BaseSalary != tt.wantBase {
+				t.Errorf("work minutes = %d, base salary = %v, want %d, %v", got.WorkMinutes, got.BaseSalary, tt.wantMinutes, tt.wantBase)
+			}
+			if got.Premium.HourlyRate != tt.wantHourly || got.Premium.OvertimePay != tt.wantOvertime {
+				t.Errorf("hourly rate = %v, overtime pay = %v, want %v, %v",
+					got.Premium.HourlyRate, got.Premium.OvertimePay, tt.wantHourly, tt.wantOvertime)
+			}
+		})
+	}
+}
+
+func TestCalculateTotalAllowance(t *testing.T) {
+	commuting := models.AllowanceType{ID: 1, Type: "fixed", NonTaxable: true, NonTaxableLimit: 150000}
+	unlimited := models.AllowanceType{ID: 2, Type: "fixed", NonTaxable: true}
+	position := models.AllowanceType{ID: 3, Type: "fixed", IncludeInOvertimeBase: true}
+	condolence := models.AllowanceType{ID: 4, Type: "fixed", ExcludeFromStandardRemuneration: true}
+	allowance := func(at models.AllowanceType, amount int) models.EmployeeAllowance {
+		return models.EmployeeAllowance{AllowanceTypeID: at.ID, Amount: amount, AllowanceType: at}
+	}
+
+	tests := []struct {
+		name           string
+		allowances     []models.EmployeeAllowance
+		wantTotal      float64
+		wantNonTaxable float64
+		wantExcluded   float64
+		wantOvertime   float64
+	}{
+		{"上限以内の非課税", []models.EmployeeAllowance{allowance(commuting, 20000)}, 20000, 20000, 0, 0},
+		{"上限を超えた分は課税", []models.EmployeeAllowance{allowance(commuting, 160000)}, 160000, 150000, 0, 0},
+		{
+			// 継続手当とその月だけの手当など、同じ種類の合計に上限を1回だけ適用する
+			"同じ種類の手当は合計に上限を適用する",
+			[]models.EmployeeAllowance{allowance(commuting, 100000), allowance(commuting, 100000)},
+			200000, 150000, 0, 0,
+		},
+		{
+			"種類ごとに上限を適用する",
+			[]models.EmployeeAllowance{allowance(commuting, 100000), allowance(commuting, 100000), allowance(unlimited, 30000)},
+			230000, 180000, 0, 0,
+		},
+		{
+			"課税・社会保険・割増賃金の対象を分ける",
+			[]models.EmployeeAllowance{allowance(commuting, 10000), allowance(position, 30000), allowance(condolence, 5000)},
+			45000, 10000, 5000, 30000,
+		},
+		{"手当なし", nil, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateTotalAllowance(tt.allowances)
+			want := allowanceTotals{
+				Total:                    tt.wantTotal,
+				NonTaxable:               tt.wantNonTaxable,
+				ExcludedFromRemuneration: tt.wantExcluded,
+				OvertimeBase:             tt.wantOvertime,
+			}
+			if got != want {
+				t.Errorf("calculateTotalAllowance = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
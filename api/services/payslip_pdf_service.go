@@ -0,0 +1,307 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/pdf"
+	"gorm.io/gorm"
+)
+
+// AttendanceSummary 給与明細に記載する月の勤怠の集計
+type AttendanceSummary struct {
+	ScheduledDays    int // 会社カレンダーの所定労働日数
+	WorkDays         int
+	WorkMinutes      int64
+	OvertimeMinutes  int64
+	LateNightMinutes int64
+	HolidayMinutes   int64
+	PaidLeaveDays    float64 // 年次有給休暇の取得日数 (時間単位の取得は日数に換算)
+	SpecialLeaveDays int
+}
+
+// tableRow 帳票の表の1行
+type tableRow struct {
+	Label string
+	Value string
+}
+
+const (
+	payslipMargin   = 50.0
+	tableRowHeight  = 18.0
+	payslipFontSize = 9.5
+)
+
+// RenderPayslipPDF 従業員の指定した年・月の給与明細をPDFで出力する
+// 給与計算が締められた月は確定した給与明細を、それ以外はその時点の計算結果を記載する
+func RenderPayslipPDF(db *gorm.DB, employeeID uint, year, month int) ([]byte, error) {
+	emp, err := loadPayrollEmployee(db, employeeID, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	payslip, err := lockedPayslip(db, employeeID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	if payslip == nil {
+		tables, err := loadRateTables(db, emp.Company, year, month)
+		if err != nil {
+			return nil, err
+		}
+		built, err := buildPayslip(db, emp, tables, year, month)
+		if err != nil {
+			return nil, err
+		}
+		payslip = &built
+	}
+
+	attendance, err := monthlyAttendanceSummary(db, emp, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New()
+	drawPayslip(doc.AddPage(), emp.Company.Name, *payslip, attendance, year, month)
+	return doc.Bytes()
+}
+
+// RenderCompanyPayslipsZIP 会社の全従業員の給与明細PDFをまとめたZIPを出力する
+// 給与計算が締められた月は確定した給与明細がある従業員のみを含める
+func RenderCompanyPayslipsZIP(db *gorm.DB, companyID uint, year, month int) ([]byte, error) {
+	var company models.Company
+	if err := db.First(&company, companyID).Error; err != nil {
+		return nil, err
+	}
+
+	employees, err := loadPayrollEmployees(db, companyID, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	var locked map[uint]models.Payslip
+	var run models.PayrollRun
+	err = db.Where("company_id = ? AND year = ? AND month = ? AND status = ?", companyID, year, month, models.PayrollRunLocked).
+		Preload("Payslips.Items", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		First(&run).Error
+	if err == nil {
+		locked = make(map[uint]models.Payslip, len(run.Payslips))
+		for _, p := range run.Payslips {
+			locked[p.EmployeeID] = p
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var tables rateTables
+	if locked == nil {
+		tables, err = loadRateTables(db, company, year, month)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, emp := range employees {
+		payslip, ok := locked[emp.ID]
+		if locked == nil {
+			payslip, err = buildPayslip(db, emp, tables, year, month)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", emp.Name, err)
+			}
+		} else if !ok {
+			continue
+		}
+
+		attendance, err := monthlyAttendanceSummary(db, emp, year, month)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := pdf.New()
+		drawPayslip(doc.AddPage(), company.Name, payslip, attendance, year, month)
+
+		w, err := zw.Create(PayslipFileName(emp.ID, year, month))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := doc.WriteTo(w); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PayslipFileName 給与明細PDFのファイル名
+func PayslipFileName(employeeID uint, year, month int) string {
+	return fmt.Sprintf("payslip_%04d%02d_%d.pdf", year, month, employeeID)
+}
+
+// lockedPayslip 締められた給与計算の給与明細を各項目を含めて取得する (なければ nil)
+func lockedPayslip(db *gorm.DB, employeeID uint, year, month int) (*models.Payslip, error) {
+	var payslip models.Payslip
+	err := db.
+		Joins("JOIN payroll_runs ON payroll_runs.id = payslips.payroll_run_id").
+		Where("payslips.employee_id = ? AND payroll_runs.year = ? AND payroll_runs.month = ? AND payroll_runs.status = ?",
+			employeeID, year, month, models.PayrollRunLocked).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		First(&payslip).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &payslip, nil
+}
+
+// monthlyAttendanceSummary 指定した年・月の勤務記録から出勤日数・各労働時間・休暇の日数を集計する
+func monthlyAttendanceSummary(db *gorm.DB, emp models.Employee, year, month int) (AttendanceSummary, error) {
+	records, err := monthlyWorkRecords(db, emp.ID, year, month)
+	if err != nil {
+		return AttendanceSummary{}, err
+	}
+	calendar, err := GetCompanyCalendar(db, emp.Company, year, month)
+	if err != nil {
+		return AttendanceSummary{}, err
+	}
+
+	s := AttendanceSummary{ScheduledDays: calendar.ScheduledDays}
+	worked := make([]models.WorkRecord, 0, len(records))
+	for _, r := range records {
+		switch r.LeaveType {
+		case models.WorkRecordSpecialLeave:
+			s.SpecialLeaveDays++
+			continue
+		case models.WorkRecordPaidLeave:
+			continue
+		}
+		worked = append(worked, r)
+		s.WorkMinutes += r.WorkMinutes
+		s.LateNightMinutes += r.LateNightMinutes
+		s.HolidayMinutes += r.HolidayMinutes
+	}
+	s.WorkDays = workDays(worked)
+	if s.OvertimeMinutes, err = monthlyOvertimeMinutes(db, emp, worked, year, month); err != nil {
+		return AttendanceSummary{}, err
+	}
+
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	if err := db.Model(&models.PaidLeaveUsage{}).
+		Where("employee_id = ? AND date >= ? AND date < ?", emp.ID, from, from.AddDate(0, 1, 0)).
+		Select("COALESCE(SUM(days), 0)").
+		Scan(&s.PaidLeaveDays).Error; err != nil {
+		return AttendanceSummary{}, err
+	}
+	return s, nil
+}
+
+// drawPayslip 給与明細を1ページに描画する
+// 上段の左に支給、右に控除、下段に勤怠を並べ、最下部に差引支給額を記載する
+func drawPayslip(page *pdf.Page, companyName string, payslip models.Payslip, attendance AttendanceSummary, year, month int) {
+	left := payslipMargin
+	right := pdf.PageWidth - payslipMargin
+
+	page.TextCenter(pdf.PageWidth/2, 790, 18, "給与明細書")
+	page.TextCenter(pdf.PageWidth/2, 770, 11, fmt.Sprintf("%d年%d月分", year, month))
+
+	page.Text(left, 735, 11, companyName)
+	page.TextRight(right, 735, 11, payslip.EmployeeName+" 様")
+	page.TextRight(right, 719, payslipFontSize, "従業員番号 "+strconv.FormatUint(uint64(payslip.EmployeeID), 10))
+	page.Line(left, 710, right, 710, 0.8)
+
+	var earnings, deductions []tableRow
+	for _, item := range payslip.Items {
+		row := tableRow{Label: item.Label, Value: formatYen(item.Amount)}
+		if item.Category == models.PayslipDeduction {
+			deductions = append(deductions, row)
+		} else {
+			earnings = append(earnings, row)
+		}
+	}
+
+	gap := 20.0
+	colWidth := (right - left - gap) / 2
+	top := 690.0
+	earningsBottom := drawTable(page, left, top, colWidth, "支給", earnings,
+		tableRow{Label: "総支給額", Value: formatYen(payslip.GrossSalary)})
+	deductionsBottom := drawTable(page, left+colWidth+gap, top, colWidth, "控除", deductions,
+		tableRow{Label: "控除合計", Value: formatYen(payslip.TotalDeductions)})
+
+	attendanceRows := []tableRow{
+		{Label: "所定労働日数", Value: fmt.Sprintf("%d日", attendance.ScheduledDays)},
+		{Label: "出勤日数", Value: fmt.Sprintf("%d日", attendance.WorkDays)},
+		{Label: "労働時間", Value: formatMinutes(attendance.WorkMinutes)},
+		{Label: "時間外労働", Value: formatMinutes(attendance.OvertimeMinutes)},
+		{Label: "深夜労働", Value: formatMinutes(attendance.LateNightMinutes)},
+		{Label: "休日労働", Value: formatMinutes(attendance.HolidayMinutes)},
+		{Label: "有給休暇", Value: fmt.Sprintf("%s日", strconv.FormatFloat(math.Round(attendance.PaidLeaveDays*100)/100, 'f', -1, 64))},
+	}
+	if attendance.SpecialLeaveDays > 0 {
+		attendanceRows = append(attendanceRows, tableRow{Label: "特別休暇", Value: fmt.Sprintf("%d日", attendance.SpecialLeaveDays)})
+	}
+	top = math.Min(earningsBottom, deductionsBottom) - gap
+	bottom := drawTable(page, left, top, colWidth, "勤怠", attendanceRows, tableRow{})
+
+	netTop := bottom - gap
+	netHeight := tableRowHeight * 1.6
+	netLeft := left + colWidth + gap
+	page.FillRect(netLeft, netTop-netHeight, colWidth, netHeight, 0.9)
+	page.Rect(netLeft, netTop-netHeight, colWidth, netHeight, 1)
+	page.Text(netLeft+8, netTop-netHeight+9, 12, "差引支給額")
+	page.TextRight(right-8, netTop-netHeight+9, 12, formatYen(payslip.NetSalary)+" 円")
+}
+
+// drawTable 見出し・各行・合計行 (Label が空なら省略) からなる表を描画し、表の下端の y 座標を返す
+func drawTable(page *pdf.Page, x, top, width float64, title string, rows []tableRow, total tableRow) float64 {
+	y := top - tableRowHeight
+	page.FillRect(x, y, width, tableRowHeight, 0.85)
+	page.Rect(x, y, width, tableRowHeight, 0.8)
+	page.TextCenter(x+width/2, y+5.5, 10, title)
+
+	drawRow := func(row tableRow) {
+		y -= tableRowHeight
+		page.Rect(x, y, width, tableRowHeight, 0.5)
+		page.Text(x+6, y+5.5, payslipFontSize, row.Label)
+		page.TextRight(x+width-6, y+5.5, payslipFontSize, row.Value)
+	}
+
+	for _, row := range rows {
+		drawRow(row)
+	}
+	if total.Label != "" {
+		drawRow(total)
+		page.Line(x, y+tableRowHeight, x+width, y+tableRowHeight, 1.2)
+	}
+	return y
+}
+
+// formatYen 金額を3桁区切りの整数で表す (1円未満は四捨五入)
+func formatYen(amount float64) string {
+	n := int64(math.Round(amount))
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	s := strconv.FormatInt(n, 10)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+// formatMinutes 分を「時間:分」で表す
+func formatMinutes(minutes int64) string {
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
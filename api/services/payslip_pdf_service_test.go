@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/pdf"
+)
+
+// pdfTexts PDFに描画された文字列と、その左端の x 座標
+func pdfTexts(t *testing.T, out []byte) map[string]float64 {
+	t.Helper()
+	texts := make(map[string]float64)
+	for _, m := range regexp.MustCompile(`([\d.]+) [\d.]+ Td <([0-9A-F]*)> Tj`).FindAllSubmatch(out, -1) {
+		x, _ := strconv.ParseFloat(string(m[1]), 64)
+		units := make([]uint16, 0, len(m[2])/4)
+		for i := 0; i+4 <= len(m[2]); i += 4 {
+			u, _ := strconv.ParseUint(string(m[2][i:i+4]), 16, 16)
+			units = append(units, uint16(u))
+		}
+		texts[string(utf16.Decode(units))] = x
+	}
+	return texts
+}
+
+func TestDrawPayslip(t *testing.T) {
+	payslip := models.Payslip{
+		EmployeeID:      12,
+		EmployeeName:    "山田 太郎",
+		GrossSalary:     331000,
+		TotalDeductions: 71234.4,
+		NetSalary:       259765.6,
+		Items: []models.PayslipItem{
+			{Category: models.PayslipEarning, Label: "基本給", Amount: 300000},
+			{Category: models.PayslipEarning, Label: "通勤手当", Amount: 31000},
+			{Category: models.PayslipDeduction, Label: "健康保険料", Amount: 16450},
+			{Category: models.PayslipDeduction, Label: "源泉所得税", Amount: 7000},
+		},
+	}
+	attendance := AttendanceSummary{
+		ScheduledDays:    21,
+		WorkDays:         20,
+		WorkMinutes:      9630,
+		OvertimeMinutes:  630,
+		PaidLeaveDays:    1.125,
+		SpecialLeaveDays: 1,
+	}
+
+	doc := pdf.New()
+	drawPayslip(doc.AddPage(), "株式会社サンプル", payslip, attendance, 2025, 6)
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts := pdfTexts(t, out)
+
+	for _, want := range []string{
+		"給与明細書", "2025年6月分", "株式会社サンプル", "山田 太郎 様", "従業員番号 12",
+		"300,000", "31,000", "331,000", "16,450", "71,234", "259,766 円",
+		"21日", "20日", "160:30", "10:30", "1.13日", "特別休暇",
+	} {
+		if _, ok := texts[want]; !ok {
+			t.Errorf("%q is not drawn", want)
+		}
+	}
+
+	// 支給は左の列、控除は右の列に記載する
+	middle := pdf.PageWidth / 2
+	for _, label := range []string{"基本給", "通勤手当", "総支給額"} {
+		if x, ok := texts[label]; !ok || x >= middle {
+			t.Errorf("earning %q is drawn at x=%v, want left column", label, x)
+		}
+	}
+	for _, label := range []string{"健康保険料", "源泉所得税", "控除合計", "差引支給額"} {
+		if x, ok := texts[label]; !ok || x <= middle {
+			t.Errorf("deduction %q is drawn at x=%v, want right column", label, x)
+		}
+	}
+}
+
+func TestDrawPayslipWithoutSpecialLeave(t *testing.T) {
+	doc := pdf.New()
+	drawPayslip(doc.AddPage(), "株式会社サンプル", models.Payslip{EmployeeName: "山田 太郎"}, AttendanceSummary{}, 2025, 6)
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 特別休暇を取得していない月は記載しない
+	if _, ok := pdfTexts(t, out)["特別休暇"]; ok {
+		t.Error("special leave row is drawn without special leave")
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Error("not a PDF")
+	}
+}
+
+func TestFormatYen(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1234567, "1,234,567"},
+		{259765.6, "259,766"},
+		{-12345, "-12,345"},
+		{-999.4, "-999"},
+	}
+	for _, tt := range tests {
+		if got := formatYen(tt.amount); got != tt.want {
+			t.Errorf("formatYen(%v) = %s, want %s", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		minutes int64
+		want    string
+	}{
+		{0, "0:00"},
+		{5, "0:05"},
+		{9630, "160:30"},
+	}
+	for _, tt := range tests {
+		if got := formatMinutes(tt.minutes); got != tt.want {
+			t.Errorf("formatMinutes(%d) = %s, want %s", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestPayslipFileName(t *testing.T) {
+	if got, want := PayslipFileName(12, 2025, 6), "payslip_202506_12.pdf"; got != want {
+		t.Errorf("PayslipFileName = %s, want %s", got, want)
+	}
+}
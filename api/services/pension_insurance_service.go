@@ -4,9 +4,11 @@ import (
 	"errors"
 	"github.com/t2469/attendance-system.git/models"
 	"gorm.io/gorm"
-	"math"
 )
 
+// pensionMaxAge 厚生年金保険の被保険者資格を喪失する年齢
+const pensionMaxAge = 70
+
 type PensionInsuranceResponse struct {
 	EmployeeName            string  `json:"employee_name"`
 	CompanyName             string  `json:"company_name"`
@@ -17,41 +19,60 @@ type PensionInsuranceResponse struct {
 	EmployeePension         float64 `json:"employee_pension"`
 	EmployerPension         float64 `json:"employer_pension"`
 	Age                     int     `json:"age"`
+	Eligible                bool    `json:"eligible"` // 厚生年金保険の被保険者か (70歳に達した日の属する月以降は false)
 }
 
 // CalculatePension は、指定された年・月をもとに年金保険料を計算する関数
+// 定時決定・随時改定で決まった標準報酬月額があればその等級を使い、なければ当月の報酬から等級を求める
 func CalculatePension(db *gorm.DB, employeeID uint, calcYear, calcMonth int) (PensionInsuranceResponse, error) {
-	var employee models.Employee
-	if err := db.Preload("Company").Preload("Allowances", "year = ? AND month = ?", calcYear, calcMonth).Preload("Allowances.AllowanceType").First(&employee, employeeID).Error; err != nil {
+	employee, err := loadPayrollEmployee(db, employeeID, calcYear, calcMonth)
+	if err != nil {
 		return PensionInsuranceResponse{}, err
 	}
 
-	age := calculateAge(employee.DateOfBirth)
-	prefID := employee.Company.PrefectureID
-	if prefID == 0 {
-		return PensionInsuranceResponse{}, errors.New("prefecture ID not set for employee's company")
+	tables, err := loadRateTables(db, employee.Company, calcYear, calcMonth)
+	if err != nil {
+		return PensionInsuranceResponse{}, err
 	}
 
-	totalAllowance := calculateTotalAllowance(employee.Allowances)
-	standardMonthlyAmount := employee.MonthlySalary + int(math.Round(totalAllowance))
+	standard, err := StandardRemunerationInForce(db, employeeID, calcYear, calcMonth)
+	if err != nil {
+		return PensionInsuranceResponse{}, err
+	}
 
-	var rate models.PensionInsuranceRate
-	err := db.Where(
-		"prefecture_id = ? AND min_monthly_amount <= ? AND max_monthly_amount >= ? "+
-			"AND ((from_year < ? OR (from_year = ? AND from_month <= ?)) "+
-			"AND (to_year > ? OR (to_year = ? AND to_month >= ?)))",
-		prefID, standardMonthlyAmount, standardMonthlyAmount,
-		calcYear, calcYear, calcMonth,
-		calcYear, calcYear, calcMonth,
-	).Order("from_year desc, from_month desc").
-		First(&rate).Error
+	earnings, err := calculateEarnings(db, employee, calcYear, calcMonth)
 	if err != nil {
-		return PensionInsuranceResponse{}, errors.New("no matching rate found for employee's company for the specified calculation date")
+		return PensionInsuranceResponse{}, err
 	}
 
-	var pref models.Prefecture
-	if err := db.First(&pref, prefID).Error; err != nil {
-		return PensionInsuranceResponse{}, errors.New("prefecture not found")
+	return calculatePensionInsurance(employee, standard, earnings.remuneration(), tables, calcYear, calcMonth)
+}
+
+// calculatePensionInsurance 読み込み済みの保険料額表から厚生年金保険料を求める
+// 標準報酬月額の履歴がない場合は remuneration (当月の報酬月額) から等級を求める
+func calculatePensionInsurance(employee models.Employee, standard *models.StandardRemuneration, remuneration int, tables rateTables, calcYear, calcMonth int) (PensionInsuranceResponse, error) {
+	age := calculateAge(employee.DateOfBirth, calcYear, calcMonth)
+
+	// 70歳に達した日の属する月から保険料は徴収しない
+	if age >= pensionMaxAge {
+		return PensionInsuranceResponse{
+			EmployeeName:   employee.Name,
+			CompanyName:    employee.Company.Name,
+			PrefectureName: tables.Prefecture.Name,
+			Age:            age,
+			Eligible:       false,
+		}, nil
+	}
+
+	var rate models.PensionInsuranceRate
+	var ok bool
+	if standard != nil {
+		rate, ok = tables.pensionByGrade(standard.PensionGrade)
+	} else {
+		rate, ok = tables.pensionByAmount(remuneration)
+	}
+	if !ok {
+		return PensionInsuranceResponse{}, errors.New("no matching rate found for employee's company for the specified calculation date")
 	}
 
 	total := rate.PensionTotal
@@ -61,13 +82,26 @@ func CalculatePension(db *gorm.DB, employeeID uint, calcYear, calcMonth int) (Pe
 	resp := PensionInsuranceResponse{
 		EmployeeName:            employee.Name,
 		CompanyName:             employee.Company.Name,
-		PrefectureName:          pref.Name,
+		PrefectureName:          tables.Prefecture.Name,
 		Grade:                   rate.Grade,
 		CalculatedMonthlyAmount: rate.MonthlyAmount,
 		PensionTotal:            total,
 		EmployeePension:         employeePension,
 		EmployerPension:         employerPension,
 		Age:                     age,
+		Eligible:                true,
 	}
 	return resp, nil
 }
+
+// pensionRatePeriod 指定した年・月に適用される厚生年金保険料額表に絞り込む
+func pensionRatePeriod(db *gorm.DB, prefID uint, calcYear, calcMonth int) *gorm.DB {
+	return db.Where(
+		"prefecture_id = ? "+
+			"AND ((from_year < ? OR (from_year = ? AND from_month <= ?)) "+
+			"AND (to_year > ? OR (to_year = ? AND to_month >= ?)))",
+		prefID,
+		calcYear, calcYear, calcMonth,
+		calcYear, calcYear, calcMonth,
+	).Order("from_year desc, from_month desc")
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// PremiumPay 割増賃金の内訳
+type PremiumPay struct {
+	HourlyRate        float64
+	OvertimePay       float64
+	ExcessOvertimePay float64
+	LateNightPay      float64
+	HolidayPay        float64
+}
+
+func (p PremiumPay) Total() float64 {
+	return p.OvertimePay + p.ExcessOvertimePay + p.LateNightPay + p.HolidayPay
+}
+
+// monthlyWorkRecords 指定した年・月を営業日とする勤務記録を取得
+func monthlyWorkRecords(db *gorm.DB, employeeID uint, year, month int) ([]models.WorkRecord, error) {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, 0)
+
+	var records []models.WorkRecord
+	if err := db.
+		Where("employee_id = ? AND date >= ? AND date < ?", employeeID, from, to).
+		Order("date ASC").
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// monthlyOvertimeMinutes 指定した年・月の給与で支払う時間外労働の合計
+// フレックスタイム制の月は勤務記録の時間外労働に代えて、清算期間の清算で求めた時間とする
+func monthlyOvertimeMinutes(db *gorm.DB, emp models.Employee, records []models.WorkRecord, year, month int) (int64, error) {
+	minutes, ok, err := flextimeOvertimeMinutes(db, emp, year, month)
+	if err != nil || ok {
+		return minutes, err
+	}
+
+	var overtime int64
+	for _, r := range records {
+		overtime += r.OvertimeMinutes
+	}
+	return overtime, nil
+}
+
+// calculatePremiumPay 時間単価に、時間外・深夜・休日の時間と割増率を掛けて割増賃金を求める
+// 時間外労働は月の合計が閾値 (60時間) を超えた分に高い割増率を適用する
+// overtime は月の時間外労働の合計 (monthlyOvertimeMinutes で求める)
+// premiumOnly は時給者のように労働時間分の賃金を別に支払う場合で、割増分 (0.25など) のみを支払う
+func calculatePremiumPay(company models.Company, hourly float64, records []models.WorkRecord, overtime int64, premiumOnly bool) PremiumPay {
+	if hourly <= 0 {
+		return PremiumPay{}
+	}
+
+	var lateNight, holiday int64
+	for _, r := range records {
+		lateNight += r.LateNightMinutes
+		holiday += r.HolidayMinutes
+	}
+	overtime = roundWorkMinutes(company, overtime)
+	lateNight = roundWorkMinutes(company, lateNight)
+	holiday = roundWorkMinutes(company, holiday)
+
+	normal := min(overtime, int64(company.ExcessOvertimeThresholdMinutes))
+	excess := overtime - normal
+
+	base := 1.0
+	if premiumOnly {
+		base = 0
+	}
+
+	return PremiumPay{
+		HourlyRate:        hourly,
+		OvertimePay:       premiumAmount(company, hourly, base+company.OvertimePremiumRate, normal),
+		ExcessOvertimePay: premiumAmount(company, hourly, base+company.ExcessOvertimePremiumRate, excess),
+		LateNightPay:      premiumAmount(company, hourly, company.LateNightPremiumRate, lateNight),
+		HolidayPay:        premiumAmount(company, hourly, base+company.HolidayPremiumRate, holiday),
+	}
+}
+
+// premiumAmount 時間単価×倍率×時間 (1円未満は会社の端数処理に従う)
+func premiumAmount(company models.Company, hourly, multiplier float64, minutes int64) float64 {
+	return roundWage(company, hourly*multiplier*float64(minutes)/60)
+}
+
+// roundWorkMinutes 月の合計労働時間を会社の端数処理の単位で丸める (単位の半分以上は切り上げ)
+func roundWorkMinutes(company models.Company, minutes int64) int64 {
+	unit := int64(company.WorkMinutesRoundingUnit)
+	if unit <= 1 {
+		return minutes
+	}
+	return (minutes + unit/2) / unit * unit
+}
+
+// roundWage 賃金の1円未満の端数を会社の端数処理に従って処理する
+func roundWage(company models.Company, amount float64) float64 {
+	// 浮動小数点の誤差で切り捨て・切り上げの結果が変わらないよう、先に小数第6位で丸める
+	amount = math.Round(amount*1e6) / 1e6
+	switch company.WageRoundingMode {
+	case models.WageRoundingFloor:
+		return math.Floor(amount)
+	case models.WageRoundingCeil:
+		return math.Ceil(amount)
+	default:
+		return math.Round(amount)
+	}
+}
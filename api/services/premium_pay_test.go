@@ -0,0 +1,120 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestCalculatePremiumPay(t *testing.T) {
+	company := testCompany()
+	records := []models.WorkRecord{
+		{LateNightMinutes: 60, HolidayMinutes: 480},
+		{LateNightMinutes: 60},
+	}
+	tests := []struct {
+		name        string
+		hourly      float64
+		records     []models.WorkRecord
+		overtime    int64
+		premiumOnly bool
+		want        PremiumPay
+	}{
+		{
+			"60時間以内の時間外", 2000, nil, 3000, false,
+			PremiumPay{HourlyRate: 2000, OvertimePay: 125000},
+		},
+		{
+			"60時間ちょうど", 2000, nil, 3600, false,
+			PremiumPay{HourlyRate: 2000, OvertimePay: 150000},
+		},
+		{
+			"60時間を超えた分は5割増", 2000, records, 4200, false,
+			PremiumPay{HourlyRate: 2000, OvertimePay: 150000, ExcessOvertimePay: 30000, LateNightPay: 1000, HolidayPay: 21600},
+		},
+		{
+			"時給者は割増分のみ", 2000, records, 4200, true,
+			PremiumPay{HourlyRate: 2000, OvertimePay: 30000, ExcessOvertimePay: 10000, LateNightPay: 1000, HolidayPay: 5600},
+		},
+		{"時間単価がない", 0, records, 4200, false, PremiumPay{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculatePremiumPay(company, tt.hourly, tt.records, tt.overtime, tt.premiumOnly); got != tt.want {
+				t.Errorf("calculatePremiumPay = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatePremiumPayRounding(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+		unit int
+		want PremiumPay
+	}{
+		// 1234円×1.25×25分 = 642.708…円、1234円×0.25×60分 = 308.5円
+		{"四捨五入", models.WageRoundingRound, 1, PremiumPay{HourlyRate: 1234, OvertimePay: 643, LateNightPay: 309}},
+		{"切り捨て", models.WageRoundingFloor, 1, PremiumPay{HourlyRate: 1234, OvertimePay: 642, LateNightPay: 308}},
+		{"切り上げ", models.WageRoundingCeil, 1, PremiumPay{HourlyRate: 1234, OvertimePay: 643, LateNightPay: 309}},
+		// 時間外25分は30分未満のため切り捨て
+		{"月の合計を1時間単位で丸める", models.WageRoundingRound, 60, PremiumPay{HourlyRate: 1234, LateNightPay: 309}},
+	}
+	records := []models.WorkRecord{{LateNightMinutes: 60}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			company := testCompany()
+			company.WageRoundingMode = tt.mode
+			company.WorkMinutesRoundingUnit = tt.unit
+			if got := calculatePremiumPay(company, 1234, records, 25, false); got != tt.want {
+				t.Errorf("calculatePremiumPay = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundWorkMinutes(t *testing.T) {
+	tests := []struct {
+		unit    int
+		minutes int64
+		want    int64
+	}{
+		{1, 7, 7},
+		{0, 7, 7},
+		{15, 7, 0},
+		{15, 8, 15},
+		{60, 29, 0},
+		{60, 30, 60},
+		{60, 89, 60},
+		{60, 90, 120},
+	}
+	for _, tt := range tests {
+		company := models.Company{WorkMinutesRoundingUnit: tt.unit}
+		if got := roundWorkMinutes(company, tt.minutes); got != tt.want {
+			t.Errorf("roundWorkMinutes(unit %d, %d) = %d, want %d", tt.unit, tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestRoundWage(t *testing.T) {
+	tests := []struct {
+		mode   string
+		amount float64
+		want   float64
+	}{
+		{models.WageRoundingRound, 1562.4, 1562},
+		{models.WageRoundingRound, 1562.5, 1563},
+		{models.WageRoundingFloor, 1562.9, 1562},
+		{models.WageRoundingCeil, 1562.1, 1563},
+		// 浮動小数点の誤差で切り上げ・切り捨ての結果が変わらない
+		{models.WageRoundingCeil, 3300.0000000000005, 3300},
+		{models.WageRoundingFloor, 769.9999999999999, 770},
+	}
+	for _, tt := range tests {
+		company := models.Company{WageRoundingMode: tt.mode}
+		if got := roundWage(company, tt.amount); got != tt.want {
+			t.Errorf("roundWage(%s, %v) = %v, want %v", tt.mode, tt.amount, got, tt.want)
+		}
+	}
+}
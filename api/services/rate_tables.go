@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// rateTables 会社の都道府県・事業の種類について、指定した年・月に適用される保険料額表と料率
+// 複数の従業員の給与をまとめて計算する場合に、保険料額表を一度だけ読み込むために使う
+type rateTables struct {
+	Prefecture models.Prefecture
+	Health     []models.HealthInsuranceRate
+	Pension    []models.PensionInsuranceRate
+	Employment *models.EmploymentInsuranceRate // 料率が登録されていない場合は nil
+}
+
+// loadRateTables 会社に適用される保険料額表と雇用保険料率を読み込む
+func loadRateTables(db *gorm.DB, company models.Company, year, month int) (rateTables, error) {
+	var tables rateTables
+
+	prefID := company.PrefectureID
+	if prefID == 0 {
+		return tables, errors.New("prefecture ID not set for employee's company")
+	}
+	if err := db.First(&tables.Prefecture, prefID).Error; err != nil {
+		return tables, errors.New("prefecture not found")
+	}
+
+	if err := healthRatePeriod(db, prefID, year, month).Find(&tables.Health).Error; err != nil {
+		return tables, err
+	}
+	if err := pensionRatePeriod(db, prefID, year, month).Find(&tables.Pension).Error; err != nil {
+		return tables, err
+	}
+
+	industry := company.IndustryClass
+	if industry == "" {
+		industry = models.IndustryGeneral
+	}
+	var employment models.EmploymentInsuranceRate
+	err := employmentRatePeriod(db, industry, year, month).First(&employment).Error
+	if err == nil {
+		tables.Employment = &employment
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return tables, err
+	}
+
+	return tables, nil
+}
+
+// healthByAmount 報酬月額が含まれる等級の健康保険料額
+func (t rateTables) healthByAmount(amount int) (models.HealthInsuranceRate, bool) {
+	for _, r := range t.Health {
+		if r.MinMonthlyAmount <= amount && amount <= r.MaxMonthlyAmount {
+			return r, true
+		}
+	}
+	return models.HealthInsuranceRate{}, false
+}
+
+// healthByGrade 等級を指定した健康保険料額
+func (t rateTables) healthByGrade(grade string) (models.HealthInsuranceRate, bool) {
+	for _, r := range t.Health {
+		if r.Grade == grade {
+			return r, true
+		}
+	}
+	return models.HealthInsuranceRate{}, false
+}
+
+// pensionByAmount 報酬月額が含まれる等級の厚生年金保険料額
+func (t rateTables) pensionByAmount(amount int) (models.PensionInsuranceRate, bool) {
+	for _, r := range t.Pension {
+		if r.MinMonthlyAmount <= amount && amount <= r.MaxMonthlyAmount {
+			return r, true
+		}
+	}
+	return models.PensionInsuranceRate{}, false
+}
+
+// pensionByGrade 等級を指定した厚生年金保険料額
+func (t rateTables) pensionByGrade(grade string) (models.PensionInsuranceRate, bool) {
+	for _, r := range t.Pension {
+		if r.Grade == grade {
+			return r, true
+		}
+	}
+	return models.PensionInsuranceRate{}, false
+}
+
+// lowestPension 厚生年金保険の第1級 (報酬月額の下限が最も低い等級)
+func (t rateTables) lowestPension() (models.PensionInsuranceRate, bool) {
+	if len(t.Pension) == 0 {
+		return models.PensionInsuranceRate{}, false
+	}
+	lowest := t.Pension[0]
+	for _, r := range t.Pension[1:] {
+		if r.MinMonthlyAmount < lowest.MinMonthlyAmount {
+			lowest = r
+		}
+	}
+	return lowest, true
+}
+
+// healthRate 健康保険の保険料率 (%)。同じ期間の等級はすべて同じ料率のため、登録済みの料率を返す
+func (t rateTables) healthRate(withCare bool) (float64, bool) {
+	for _, r := range t.Health {
+		rate := r.HealthRateNoCare
+		if withCare {
+			rate = r.HealthRateWithCare
+		}
+		if rate > 0 {
+			return rate, true
+		}
+	}
+	return 0, false
+}
+
+// pensionRate 厚生年金保険の保険料率 (%)
+func (t rateTables) pensionRate() (float64, bool) {
+	for _, r := range t.Pension {
+		if r.PensionRate > 0 {
+			return r.PensionRate, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// splitResidentTax 年税額を12回に分割する
+// 7月分以降は100円未満を切り捨てた額とし、端数はすべて6月分に含める
+func splitResidentTax(annual int) (june, monthly int) {
+	monthly = annual / 12 / 100 * 100
+	june = annual - monthly*11
+	return june, monthly
+}
+
+// GenerateResidentTaxInstallments 通知書の内容から6月〜翌年5月の12回分の控除額を作成する
+// 6月分・7月以降の月額が指定されていない場合は年税額から算出する
+func GenerateResidentTaxInstallments(notice *models.ResidentTaxNotice) ([]models.ResidentTaxInstallment, error) {
+	if notice.JuneAmount == 0 && notice.MonthlyAmount == 0 {
+		notice.JuneAmount, notice.MonthlyAmount = splitResidentTax(notice.AnnualAmount)
+	}
+	if notice.JuneAmount+notice.MonthlyAmount*11 != notice.AnnualAmount {
+		return nil, errors.New("june amount and monthly amount do not add up to the annual amount")
+	}
+
+	installments := make([]models.ResidentTaxInstallment, 0, 12)
+	for i := 0; i < 12; i++ {
+		year := notice.FiscalYear
+		month := 6 + i
+		if month > 12 {
+			year++
+			month -= 12
+		}
+
+		amount := notice.MonthlyAmount
+		if i == 0 {
+			amount = notice.JuneAmount
+		}
+
+		installments = append(installments, models.ResidentTaxInstallment{
+			NoticeID:   notice.ID,
+			EmployeeID: notice.EmployeeID,
+			Year:       year,
+			Month:      month,
+			Amount:     amount,
+		})
+	}
+	return installments, nil
+}
+
+// SaveResidentTaxNotice 通知書を保存し、各月の控除額を作り直す
+func SaveResidentTaxNotice(db *gorm.DB, notice *models.ResidentTaxNotice) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		installments, err := GenerateResidentTaxInstallments(notice)
+		if err != nil {
+			return err
+		}
+
+		notice.Installments = nil
+		if err := tx.Save(notice).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("notice_id = ?", notice.ID).Delete(&models.ResidentTaxInstallment{}).Error; err != nil {
+			return err
+		}
+
+		for i := range installments {
+			installments[i].NoticeID = notice.ID
+		}
+		if err := tx.Create(&installments).Error; err != nil {
+			return err
+		}
+
+		notice.Installments = installments
+		return nil
+	})
+}
+
+// residentTaxForMonth 指定した年・月に控除する住民税額 (通知書が未登録の場合は0)
+func residentTaxForMonth(db *gorm.DB, employeeID uint, year, month int) (int, error) {
+	var inst models.ResidentTaxInstallment
+	err := db.Where("employee_id = ? AND year = ? AND month = ?", employeeID, year, month).First(&inst).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return inst.Amount, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestSplitResidentTax(t *testing.T) {
+	tests := []struct {
+		name        string
+		annual      int
+		wantJune    int
+		wantMonthly int
+	}{
+		{"12で割り切れる", 120000, 10000, 10000},
+		// 月額は100円未満を切り捨て、端数は6月分に含める
+		{"100円未満の端数は6月分", 123456, 11256, 10200},
+		{"月額が100円未満", 1000, 1000, 0},
+		{"年税額なし", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			june, monthly := splitResidentTax(tt.annual)
+			if june != tt.wantJune || monthly != tt.wantMonthly {
+				t.Errorf("splitResidentTax(%d) = %d, %d, want %d, %d", tt.annual, june, monthly, tt.wantJune, tt.wantMonthly)
+			}
+			if june+monthly*11 != tt.annual {
+				t.Errorf("june %d + monthly %d x 11 != annual %d", june, monthly, tt.annual)
+			}
+		})
+	}
+}
+
+func TestGenerateResidentTaxInstallments(t *testing.T) {
+	notice := models.ResidentTaxNotice{ID: 3, EmployeeID: 1, FiscalYear: 2025, AnnualAmount: 123456}
+	installments, err := GenerateResidentTaxInstallments(&notice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notice.JuneAmount != 11256 || notice.MonthlyAmount != 10200 {
+		t.Errorf("notice amounts = %d, %d, want 11256, 10200", notice.JuneAmount, notice.MonthlyAmount)
+	}
+	if len(installments) != 12 {
+		t.Fatalf("got %d installments, want 12", len(installments))
+	}
+
+	// 6月〜翌年5月の12回で、6月分が端数を含む
+	total := 0
+	for i, inst := range installments {
+		wantYear, wantMonth, wantAmount := 2025, 6+i, 10200
+		if wantMonth > 12 {
+			wantYear, wantMonth = 2026, wantMonth-12
+		}
+		if i == 0 {
+			wantAmount = 11256
+		}
+		if inst.Year != wantYear || inst.Month != wantMonth || inst.Amount != wantAmount {
+			t.Errorf("installment %d = %d/%d %d, want %d/%d %d", i, inst.Year, inst.Month, inst.Amount, wantYear, wantMonth, wantAmount)
+		}
+		if inst.NoticeID != 3 || inst.EmployeeID != 1 {
+			t.Errorf("installment %d = notice %d employee %d", i, inst.NoticeID, inst.EmployeeID)
+		}
+		total += inst.Amount
+	}
+	if total != notice.AnnualAmount {
+		t.Errorf("total = %d, want %d", total, notice.AnnualAmount)
+	}
+}
+
+func TestGenerateResidentTaxInstallmentsMismatch(t *testing.T) {
+	// 通知書の月額を指定した場合は年税額と一致しなければならない
+	notice := models.ResidentTaxNotice{FiscalYear: 2025, AnnualAmount: 120000, JuneAmount: 10000, MonthlyAmount: 9000}
+	if _, err := GenerateResidentTaxInstallments(&notice); err == nil {
+		t.Error("GenerateResidentTaxInstallments should fail when the amounts do not add up")
+	}
+
+	notice = models.ResidentTaxNotice{FiscalYear: 2025, AnnualAmount: 120000, JuneAmount: 10110, MonthlyAmount: 9990}
+	if _, err := GenerateResidentTaxInstallments(&notice); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// ScheduleDay 1日の勤務予定と実績の比較
+type ScheduleDay struct {
+	Date              time.Time            `json:"date"`
+	ShiftPattern      *models.ShiftPattern `json:"shift_pattern,omitempty"`
+	DayOff            bool                 `json:"day_off"`     // 休日として予定されている
+	WorkingDay        bool                 `json:"working_day"` // 会社カレンダーの所定労働日
+	PlannedStart      *time.Time           `json:"planned_start,omitempty"`
+	PlannedEnd        *time.Time           `json:"planned_end,omitempty"`
+	ClockIn           *time.Time           `json:"clock_in,omitempty"`
+	ClockOut          *time.Time           `json:"clock_out,omitempty"`
+	WorkMinutes       int64                `json:"work_minutes"`
+	LeaveType         string               `json:"leave_type,omitempty"`
+	LateMinutes       int64                `json:"late_minutes"`
+	EarlyLeaveMinutes int64                `json:"early_leave_minutes"`
+	Absent            bool                 `json:"absent"` // 勤務予定 (勤務予定を使わない月は所定労働日) があるが勤務・休暇の記録がない
+	Unscheduled       bool                 `json:"unscheduled"`
+}
+
+// GetMonthlyShifts 会社の指定した年・月の勤務予定 (employeeID が0なら全従業員)
+func GetMonthlyShifts(db *gorm.DB, companyID, employeeID uint, year, month int) ([]models.ShiftAssignment, error) {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	query := db.
+		Joins("JOIN employees ON employees.id = shift_assignments.employee_id").
+		Where("employees.company_id = ? AND shift_assignments.date >= ? AND shift_assignments.date < ?",
+			companyID, from, from.AddDate(0, 1, 0))
+	if employeeID != 0 {
+		query = query.Where("shift_assignments.employee_id = ?", employeeID)
+	}
+
+	assignments := []models.ShiftAssignment{}
+	err := query.Preload("ShiftPattern").
+		Order("shift_assignments.employee_id ASC, shift_assignments.date ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// ReplaceMonthlyShifts 指定した年・月の勤務予定を、含まれる従業員 (employeeIDs で指定した従業員を含む) ごとに置き換える
+// 置き換えた後、その月の勤務記録の遅刻・早退・予定外の勤務を判定し直す
+func ReplaceMonthlyShifts(db *gorm.DB, companyID uint, year, month int, employeeIDs []uint, assignments []models.ShiftAssignment) error {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, 0)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		employees, err := companyIDSet(tx, &models.Employee{}, companyID)
+		if err != nil {
+			return err
+		}
+		patterns, err := companyIDSet(tx, &models.ShiftPattern{}, companyID)
+		if err != nil {
+			return err
+		}
+
+		targets := make(map[uint]bool)
+		for _, id := range employeeIDs {
+			if !employees[id] {
+				return fmt.Errorf("employee %d not found", id)
+			}
+			targets[id] = true
+		}
+		for i := range assignments {
+			a := &assignments[i]
+			a.ID = 0
+			a.Date = dateOnly(a.Date)
+			if !employees[a.EmployeeID] {
+				return fmt.Errorf("employee %d not found", a.EmployeeID)
+			}
+			if a.Date.Before(from) || !a.Date.Before(to) {
+				return fmt.Errorf("date %s is not in %d/%d", a.Date.Format("2006-01-02"), year, month)
+			}
+			if a.ShiftPatternID != nil && !patterns[*a.ShiftPatternID] {
+				return fmt.Errorf("shift pattern %d not found", *a.ShiftPatternID)
+			}
+			targets[a.EmployeeID] = true
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(targets))
+		for id := range targets {
+			ids = append(ids, id)
+		}
+		if err := tx.Where("employee_id IN ? AND date >= ? AND date < ?", ids, from, to).
+			Delete(&models.ShiftAssignment{}).Error; err != nil {
+			return err
+		}
+		if len(assignments) > 0 {
+			if err := tx.Omit("ShiftPattern").Create(&assignments).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, id := range ids {
+			var dates []time.Time
+			if err := tx.Model(&models.WorkRecord{}).
+				Where("employee_id = ? AND date >= ? AND date < ?", id, from, to).
+				Distinct("date").
+				Pluck("date", &dates).Error; err != nil {
+				return err
+			}
+			for _, date := range dates {
+				if err := annotateWorkDate(tx, id, date); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// companyIDSet 会社に属するレコードのIDの集合
+func companyIDSet(tx *gorm.DB, model any, companyID uint) (map[uint]bool, error) {
+	var ids []uint
+	if err := tx.Model(model).Where("company_id = ?", companyID).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// annotateWorkDate 指定した日の勤務記録を勤務予定と比べ、遅刻・早退・予定外の勤務を設定する
+// 1日に複数の勤務がある場合は、最初の勤務の出勤で遅刻を、最後の勤務の退勤で早退を判定する
+// 勤務予定が1件も登録されていない月は、会社カレンダーの所定労働日でない日の勤務を予定外とする
+func annotateWorkDate(tx *gorm.DB, employeeID uint, date time.Time) error {
+	date = dateOnly(date)
+
+	var records []models.WorkRecord
+	if err := tx.Where("employee_id = ? AND date = ? AND leave_type = ''", employeeID, date).
+		Order("clock_in ASC").
+		Find(&records).Error; err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	assignment, err := shiftAssignmentOn(tx, employeeID, date)
+	if err != nil {
+		return err
+	}
+	unscheduled := false
+	if assignment == nil {
+		unscheduled, err = hasMonthlySchedule(tx, employeeID, date)
+		if err != nil {
+			return err
+		}
+		if !unscheduled {
+			// 勤務予定を使わない月は会社カレンダーの所定労働日でない日の勤務を予定外とする
+			var emp models.Employee
+			if err := tx.Preload("Company").First(&emp, employeeID).Error; err != nil {
+				return err
+			}
+			working, err := IsWorkingDay(tx, emp.Company, date)
+			if err != nil {
+				return err
+			}
+			unscheduled = !working
+		}
+	} else if assignment.ShiftPattern == nil {
+		unscheduled = true
+	}
+
+	for i := range records {
+		wr := &records[i]
+		var late, early int64
+		if assignment != nil && assignment.ShiftPattern != nil {
+			start, end := assignment.ShiftPattern.Span(date)
+			if i == 0 && wr.ClockIn.After(start) {
+				late = int64(wr.ClockIn.Sub(start).Minutes())
+			}
+			if i == len(records)-1 && !wr.ClockOut.IsZero() && wr.ClockOut.Before(end) {
+				early = int64(end.Sub(wr.ClockOut).Minutes())
+			}
+		}
+
+		if wr.LateMinutes == late && wr.EarlyLeaveMinutes == early && wr.Unscheduled == unscheduled {
+			continue
+		}
+		if err := tx.Model(wr).Updates(map[string]any{
+			"late_minutes":        late,
+			"early_leave_minutes": early,
+			"unscheduled":         unscheduled,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shiftAssignmentOn 指定した日の勤務予定 (登録されていなければ nil)
+func shiftAssignmentOn(tx *gorm.DB, employeeID uint, date time.Time) (*models.ShiftAssignment, error) {
+	var assignment models.ShiftAssignment
+	err := tx.Preload("ShiftPattern").
+		Where("employee_id = ? AND date = ?", employeeID, date).
+		First(&assignment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// hasMonthlySchedule 指定した日の月に勤務予定が登録されているか
+func hasMonthlySchedule(tx *gorm.DB, employeeID uint, date time.Time) (bool, error) {
+	from := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+	var count int64
+	err := tx.Model(&models.ShiftAssignment{}).
+		Where("employee_id = ? AND date >= ? AND date < ?", employeeID, from, from.AddDate(0, 1, 0)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// MonthlySchedule 従業員の指定した年・月の勤務予定と実績を日ごとに比べる (予定も記録もない日は含めない)
+// 勤務予定が1件も登録されていない月は会社カレンダーの所定労働日を予定とする。当日以降の日は欠勤としない
+func MonthlySchedule(db *gorm.DB, employeeID uint, year, month int) ([]ScheduleDay, error) {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	today := dateOnly(time.Now())
+
+	var emp models.Employee
+	if err := db.Preload("Company").First(&emp, employeeID).Error; err != nil {
+		return nil, err
+	}
+	cal, err := loadCompanyCalendar(db, emp.Company, from, from.AddDate(0, 1, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []models.ShiftAssignment
+	if err := db.Preload("ShiftPattern").
+		Where("employee_id = ? AND date >= ? AND date < ?", employeeID, from, from.AddDate(0, 1, 0)).
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	planned := make(map[string]models.ShiftAssignment, len(assignments))
+	for _, a := range assignments {
+		planned[a.Date.Format("2006-01-02")] = a
+	}
+
+	records, err := monthlyWorkRecords(db, employeeID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	actual := make(map[string][]models.WorkRecord)
+	for _, r := range records {
+		key := r.Date.Format("2006-01-02")
+		actual[key] = append(actual[key], r)
+	}
+
+	days := []ScheduleDay{}
+	for d := from; d.Month() == from.Month(); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		a, hasPlan := planned[key]
+		recs := actual[key]
+		working := cal.workingDay(d)
+		if !hasPlan && len(recs) == 0 && (len(planned) > 0 || !working) {
+			continue
+		}
+
+		day := ScheduleDay{Date: d, WorkingDay: working}
+		if hasPlan {
+			day.ShiftPattern = a.ShiftPattern
+			day.DayOff = a.ShiftPattern == nil
+			if a.ShiftPattern != nil {
+				start, end := a.ShiftPattern.Span(d)
+				day.PlannedStart, day.PlannedEnd = &start, &end
+			}
+		}
+
+		worked := false
+		for _, r := range recs {
+			if r.LeaveType != "" {
+				day.LeaveType = r.LeaveType
+				continue
+			}
+			worked = true
+			if day.ClockIn == nil {
+				clockIn := r.ClockIn
+				day.ClockIn = &clockIn
+			}
+			if !r.ClockOut.IsZero() {
+				clockOut := r.ClockOut
+				day.ClockOut = &clockOut
+			}
+			day.WorkMinutes += r.WorkMinutes
+			day.LateMinutes += r.LateMinutes
+			day.EarlyLeaveMinutes += r.EarlyLeaveMinutes
+			day.Unscheduled = day.Unscheduled || r.Unscheduled
+		}
+		expected := day.PlannedStart != nil || (len(planned) == 0 && working)
+		day.Absent = expected && !worked && day.LeaveType == "" && d.Before(today)
+		days = append(days, day)
+	}
+	return days, nil
+}
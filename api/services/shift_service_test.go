@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/db/dbtest"
+)
+
+func TestAnnotateWorkDate(t *testing.T) {
+	type record struct {
+		id          int64
+		clockIn     time.Time
+		clockOut    time.Time
+		late, early int64
+		unscheduled bool
+	}
+	type annotation struct {
+		late, early int64
+		unscheduled bool
+	}
+	tuesday := localDate(2025, 6, 10)
+	sunday := localDate(2025, 6, 15)
+	at := func(day, hour, min int) time.Time { return localTime(2025, 6, day, hour, min) }
+
+	tests := []struct {
+		name      string
+		date      time.Time
+		records   []record
+		assigned  bool                 // その日の勤務予定がある
+		pattern   [2]string            // 勤務予定の開始・終了時刻 (空なら休日の予定)
+		scheduled int64                // その月の勤務予定の件数
+		want      map[int64]annotation // 更新する勤務記録 (含まれない記録は更新しない)
+	}{
+		{name: "開始時刻より後の出勤は遅刻", date: tuesday, assigned: true, pattern: [2]string{"09:00", "18:00"}, scheduled: 20,
+			records: []record{{id: 1, clockIn: at(10, 9, 15), clockOut: at(10, 18, 0)}},
+			want:    map[int64]annotation{1: {late: 15}}},
+		{name: "終了時刻より前の退勤は早退", date: tuesday, assigned: true, pattern: [2]string{"09:00", "18:00"}, scheduled: 20,
+			records: []record{{id: 1, clockIn: at(10, 8, 55), clockOut: at(10, 17, 30)}},
+			want:    map[int64]annotation{1: {early: 30}}},
+		{name: "複数の勤務は最初の出勤で遅刻、最後の退勤で早退を判定", date: tuesday, assigned: true, pattern: [2]string{"09:00", "18:00"}, scheduled: 20,
+			records: []record{
+				{id: 1, clockIn: at(10, 9, 10), clockOut: at(10, 12, 0)},
+				{id: 2, clockIn: at(10, 13, 0), clockOut: at(10, 17, 0)},
+			},
+			want: map[int64]annotation{1: {late: 10}, 2: {early: 60}}},
+		{name: "退勤前は早退としない", date: tuesday, assigned: true, pattern: [2]string{"09:00", "18:00"}, scheduled: 20,
+			records: []record{{id: 1, clockIn: at(10, 9, 0)}},
+			want:    map[int64]annotation{}},
+		{name: "日をまたぐ勤務予定は翌日の終了時刻で判定", date: tuesday, assigned: true, pattern: [2]string{"22:00", "06:00"}, scheduled: 20,
+			records: []record{{id: 1, clockIn: at(10, 22, 0), clockOut: at(11, 5, 0)}},
+			want:    map[int64]annotation{1: {early: 60}}},
+		{name: "休日の予定の日の勤務は予定外", date: tuesday, assigned: true, scheduled: 20,
+			records: []record{{id: 1, clockIn: at(10, 9, 30), clockOut: at(10, 12, 0)}},
+			want:    map[int64]annotation{1: {unscheduled: true}}},
+		{name: "勤務予定のある月の予定のない日は予定外", date: tuesday, scheduled: 20,
+			records: []record{{id: 1, clockIn: at(10, 9, 30), clockOut: at(10, 12, 0)}},
+			want:    map[int64]annotation{1: {unscheduled: true}}},
+		{name: "勤務予定を使わない月は所定休日の勤務を予定外", date: sunday,
+			records: []record{{id: 1, clockIn: at(15, 9, 30), clockOut: at(15, 12, 0)}},
+			want:    map[int64]annotation{1: {unscheduled: true}}},
+		{name: "勤務予定を使わない月の所定労働日は予定外でなく遅刻もない", date: tuesday,
+			records: []record{{id: 1, clockIn: at(10, 11, 0), clockOut: at(10, 15, 0), late: 5, unscheduled: true}},
+			want:    map[int64]annotation{1: {}}},
+		{name: "判定が変わらなければ更新しない", date: tuesday, assigned: true, pattern: [2]string{"09:00", "18:00"}, scheduled: 20,
+			records: []record{{id: 1, clockIn: at(10, 9, 15), clockOut: at(10, 17, 50), late: 15, early: 10}},
+			want:    map[int64]annotation{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := dbtest.Open(t)
+			rows := make([][]any, len(tt.records))
+			for i, r := range tt.records {
+				rows[i] = []any{r.id, int64(1), tt.date, r.clockIn, r.clockOut, r.late, r.early, r.unscheduled}
+			}
+			rec.On(`FROM "work_records"`, dbtest.Result{
+				Columns: []string{"id", "employee_id", "date", "clock_in", "clock_out", "late_minutes", "early_leave_minutes", "unscheduled"},
+				Rows:    rows,
+			})
+			rec.On(`count(*) FROM "shift_assignments"`, dbtest.Result{Columns: []string{"count"}, Rows: [][]any{{tt.scheduled}}})
+			if tt.assigned {
+				var patternID any
+				if tt.pattern[0] != "" {
+					patternID = int64(4)
+				}
+				rec.On(`FROM "shift_assignments"`, dbtest.Result{
+					Columns: []string{"id", "employee_id", "date", "shift_pattern_id"},
+					Rows:    [][]any{{int64(8), int64(1), tt.date, patternID}},
+				})
+				rec.On(`FROM "shift_patterns"`, dbtest.Result{
+					Columns: []string{"id", "company_id", "name", "start_time", "end_time", "break_minutes"},
+					Rows:    [][]any{{int64(4), int64(1), "通常", tt.pattern[0], tt.pattern[1], int64(60)}},
+				})
+			}
+			rec.On(`FROM "employees"`, dbtest.Result{Columns: []string{"id", "company_id"}, Rows: [][]any{{int64(1), int64(1)}}})
+			rec.On(`FROM "companies"`, dbtest.Result{Columns: []string{"id", "legal_holiday_weekday"}, Rows: [][]any{{int64(1), int64(0)}}})
+			rec.On(`UPDATE "work_records"`, dbtest.Result{RowsAffected: 1})
+
+			if err := annotateWorkDate(db, 1, tt.date); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make(map[int64]annotation)
+			for _, q := range rec.Queries() {
+				set := q.Updated()
+				if set == nil {
+					continue
+				}
+				id, _ := q.Args[len(q.Args)-1].(uint)
+				got[int64(id)] = annotation{
+					late:        set["late_minutes"].(int64),
+					early:       set["early_leave_minutes"].(int64),
+					unscheduled: set["unscheduled"].(bool),
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("updated = %+v, want %+v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if a, ok := got[id]; !ok || a != want {
+					t.Errorf("record %d = %+v, want %+v", id, got[id], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// minPaymentBaseDays 報酬月額の算定に含める月の支払基礎日数の下限
+	minPaymentBaseDays = 17
+	// zuijiGradeDifference 随時改定の対象となる等級差
+	zuijiGradeDifference = 2
+	// teijiFromMonth 定時決定による標準報酬月額を適用する月 (9月〜翌年8月)
+	teijiFromMonth = 9
+	// teijiHireMonth この月の1日以降に入社した者は、その年の定時決定の対象外
+	teijiHireMonth = 6
+	// teijiZuijiFromMonth, teijiZuijiToMonth この期間に随時改定が行われる者は、その年の定時決定の対象外
+	teijiZuijiFromMonth = 7
+	teijiZuijiToMonth   = 9
+)
+
+// MonthlyRemuneration 標準報酬月額の算定に用いる各月の報酬
+type MonthlyRemuneration struct {
+	Year        int  `json:"year"`
+	Month       int  `json:"month"`
+	Amount      int  `json:"amount"`       // 報酬の総額 (基本給+手当+割増賃金)
+	FixedWage   int  `json:"fixed_wage"`   // 固定的賃金 (基本給+固定手当)
+	PaymentDays int  `json:"payment_days"` // 支払基礎日数
+	Counted     bool `json:"counted"`      // 支払基礎日数が17日以上で平均の算定に含めるか
+}
+
+// ZuijiKaiteiResult 随時改定の判定結果
+type ZuijiKaiteiResult struct {
+	Triggered    bool                  `json:"triggered"`
+	Reason       string                `json:"reason,omitempty"` // 対象とならない理由
+	Months       []MonthlyRemuneration `json:"months"`
+	Average      int                   `json:"average"`
+	CurrentGrade string                `json:"current_grade"`
+	NewGrade     string                `json:"new_grade"`
+	FromYear     int                   `json:"from_year"`
+	FromMonth    int                   `json:"from_month"`
+}
+
+// CompanyTeijiResult 従業員ごとの定時決定の結果 (対象外の場合は Skipped に理由を、決定できなかった場合は Error を設定)
+type CompanyTeijiResult struct {
+	EmployeeID           uint                         `json:"employee_id"`
+	EmployeeName         string                       `json:"employee_name"`
+	StandardRemuneration *models.StandardRemuneration `json:"standard_remuneration,omitempty"`
+	Skipped              string                       `json:"skipped,omitempty"`
+	Error                string                       `json:"error,omitempty"`
+}
+
+// StandardRemunerationInForce 指定した年・月に適用される標準報酬月額 (履歴がない場合は nil)
+func StandardRemunerationInForce(db *gorm.DB, employeeID uint, year, month int) (*models.StandardRemuneration, error) {
+	var sr models.StandardRemuneration
+	err := db.Where("employee_id = ? AND (from_year < ? OR (from_year = ? AND from_month <= ?))",
+		employeeID, year, year, month).
+		Order("from_year desc, from_month desc").
+		First(&sr).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &sr, nil
+}
+
+// monthlyRemuneration 指定した年・月の報酬を求める
+// 支払基礎日数は、月給者は暦日数、日給・時給者は出勤日数とする
+func monthlyRemuneration(db *gorm.DB, employeeID uint, year, month int) (MonthlyRemuneration, error) {
+	emp, err := loadPayrollEmployee(db, employeeID, year, month)
+	if err != nil {
+		return MonthlyRemuneration{}, err
+	}
+
+	// 給与計算が締められた月は、確定した給与明細の支給額を報酬とする
+	locked, err := LockedPayroll(db, employeeID, year, month)
+	if err != nil {
+		return MonthlyRemuneration{}, err
+	}
+	var e earnings
+	if locked != nil {
+		e = lockedEarnings(*locked, emp.Allowances)
+	} else if e, err = calculateEarnings(db, emp, year, month); err != nil {
+		return MonthlyRemuneration{}, err
+	}
+
+	// 固定的賃金の変動 (昇給・時給の変更など) を判定するため、月給・日給・時給の額と固定手当の合計とする
+	fixedWage := e.Rate
+	for _, ea := range emp.Allowances {
+		if ea.AllowanceType.Type == "fixed" && !ea.AllowanceType.ExcludeFromStandardRemuneration {
+			fixedWage += ea.Amount
+		}
+	}
+
+	days := daysInMonth(year, month)
+	if e.PayType != models.PayTypeMonthly {
+		days = e.WorkDays
+	}
+	return MonthlyRemuneration{
+		Year:        year,
+		Month:       month,
+		Amount:      int(math.Round(e.insurableGross())),
+		FixedWage:   fixedWage,
+		PaymentDays: days,
+		Counted:     days >= minPaymentBaseDays,
+	}, nil
+}
+
+// lockedEarnings 確定した給与明細の内容から支給額を求める
+// 給与明細には社会保険の報酬に含めない手当の額がないため、対象月の手当から求める (締めた月の手当は変更できない)
+func lockedEarnings(payroll PayrollCalculationResponse, allowances []models.EmployeeAllowance) earnings {
+	return earnings{
+		PayType:    payroll.PayType,
+		Rate:       payroll.WageRate,
+		WorkDays:   payroll.WorkDays,
+		BaseSalary: payroll.BaseSalary,
+		Allowance:  payroll.TotalAllowance,
+		Premium: PremiumPay{
+			HourlyRate:        payroll.HourlyRate,
+			OvertimePay:       payroll.OvertimePay,
+			ExcessOvertimePay: payroll.ExcessOvertimePay,
+			LateNightPay:      payroll.LateNightPay,
+			HolidayPay:        payroll.HolidayPay,
+		},
+		NonTaxableAllowance: payroll.NonTaxableAllowance,
+		ExcludedAllowance:   calculateTotalAllowance(allowances).ExcludedFromRemuneration,
+	}
+}
+
+// remunerationsFrom 指定した年・月から3か月分の報酬を求める
+func remunerationsFrom(db *gorm.DB, employeeID uint, year, month int) ([]MonthlyRemuneration, error) {
+	months := make([]MonthlyRemuneration, 0, 3)
+	for i := 0; i < 3; i++ {
+		y, m := addMonths(year, month, i)
+		r, err := monthlyRemuneration(db, employeeID, y, m)
+		if err != nil {
+			return nil, err
+		}
+		months = append(months, r)
+	}
+	return months, nil
+}
+
+// averageRemuneration 支払基礎日数が17日以上の月の報酬の平均 (1円未満切り捨て)
+func averageRemuneration(months []MonthlyRemuneration) (int, bool) {
+	var total, n int
+	for _, r := range months {
+		if r.Counted {
+			total += r.Amount
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return total / n, true
+}
+
+// determineStandardRemuneration 報酬月額から健康保険・厚生年金の等級を決める
+// 保険料額表は算定の基礎とした最終月のものを使用する
+func determineStandardRemuneration(db *gorm.DB, employeeID uint, average, year, month int) (models.StandardRemuneration, error) {
+	var emp models.Employee
+	if err := db.Preload("Company").First(&emp, employeeID).Error; err != nil {
+		return models.StandardRemuneration{}, err
+	}
+
+	tables, err := loadRateTables(db, emp.Company, year, month)
+	if err != nil {
+		return models.StandardRemuneration{}, err
+	}
+
+	health, ok := tables.healthByAmount(average)
+	if !ok {
+		return models.StandardRemuneration{}, errors.New("no matching health insurance grade found for the average remuneration")
+	}
+
+	pension, ok := tables.pensionByAmount(average)
+	if !ok {
+		// 厚生年金の第1級の下限より低い報酬は第1級とする
+		pension, ok = tables.lowestPension()
+	}
+	if !ok {
+		return models.StandardRemuneration{}, errors.New("no matching pension grade found for the average remuneration")
+	}
+
+	return models.StandardRemuneration{
+		EmployeeID:           employeeID,
+		AverageRemuneration:  average,
+		HealthGrade:          health.Grade,
+		HealthMonthlyAmount:  health.MonthlyAmount,
+		PensionGrade:         pension.Grade,
+		PensionMonthlyAmount: pension.MonthlyAmount,
+	}, nil
+}
+
+// saveStandardRemuneration 適用開始月が同じ履歴があれば上書きして保存する
+func saveStandardRemuneration(db *gorm.DB, sr *models.StandardRemuneration) error {
+	var existing models.StandardRemuneration
+	err := db.Where("employee_id = ? AND from_year = ? AND from_month = ?", sr.EmployeeID, sr.FromYear, sr.FromMonth).
+		First(&existing).Error
+	if err == nil {
+		sr.ID = existing.ID
+		sr.CreatedAt = existing.CreatedAt
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Save(sr).Error
+}
+
+// teijiExclusion 定時決定の対象とならない理由 (対象の場合は空文字)
+// その年の6月1日以降に入社した者と、7月〜9月に随時改定が行われる者は対象外
+func teijiExclusion(hireDate *time.Time, revisions []models.StandardRemuneration, year int) string {
+	if hireDate != nil && !hireDate.Before(time.Date(year, teijiHireMonth, 1, 0, 0, 0, 0, time.Local)) {
+		return "hired on or after June 1"
+	}
+	for _, r := range revisions {
+		if r.Reason == models.RemunerationZuiji && r.FromYear == year &&
+			r.FromMonth >= teijiZuijiFromMonth && r.FromMonth <= teijiZuijiToMonth {
+			return "standard remuneration is revised by zuiji kaitei from July to September"
+		}
+	}
+	return ""
+}
+
+// teijiExclusionOf 従業員の入社日と随時改定の履歴から、定時決定の対象とならない理由を求める
+func teijiExclusionOf(db *gorm.DB, emp models.Employee, year int) (string, error) {
+	var revisions []models.StandardRemuneration
+	if err := db.Where("employee_id = ? AND reason = ? AND from_year = ? AND from_month BETWEEN ? AND ?",
+		emp.ID, models.RemunerationZuiji, year, teijiZuijiFromMonth, teijiZuijiToMonth).
+		Find(&revisions).Error; err != nil {
+		return "", err
+	}
+	return teijiExclusion(emp.HireDate, revisions, year), nil
+}
+
+// RunTeijiKettei 4月〜6月の報酬の平均から定時決定を行い、その年の9月から適用する標準報酬月額を保存する
+// 定時決定の対象外の従業員はエラーとする
+func RunTeijiKettei(db *gorm.DB, employeeID uint, year int) (models.StandardRemuneration, error) {
+	var emp models.Employee
+	if err := db.First(&emp, employeeID).Error; err != nil {
+		return models.StandardRemuneration{}, err
+	}
+	reason, err := teijiExclusionOf(db, emp, year)
+	if err != nil {
+		return models.StandardRemuneration{}, err
+	}
+	if reason != "" {
+		return models.StandardRemuneration{}, errors.New("not subject to teiji kettei: " + reason)
+	}
+	return runTeijiKettei(db, employeeID, year)
+}
+
+// runTeijiKettei 定時決定の対象であることを確認した従業員について定時決定を行う
+func runTeijiKettei(db *gorm.DB, employeeID uint, year int) (models.StandardRemuneration, error) {
+	months, err := remunerationsFrom(db, employeeID, year, 4)
+	if err != nil {
+		return models.StandardRemuneration{}, err
+	}
+
+	average, ok := averageRemuneration(months)
+	if !ok {
+		return models.StandardRemuneration{}, errors.New("no month from April to June has enough payment base days")
+	}
+
+	sr, err := determineStandardRemuneration(db, employeeID, average, year, 6)
+	if err != nil {
+		return models.StandardRemuneration{}, err
+	}
+	sr.Reason = models.RemunerationTeiji
+	sr.FromYear = year
+	sr.FromMonth = teijiFromMonth
+
+	if err := saveStandardRemuneration(db, &sr); err != nil {
+		return models.StandardRemuneration{}, err
+	}
+	return sr, nil
+}
+
+// RunCompanyTeijiKettei 会社の全従業員について定時決定を行う
+// 従業員ごとに決定して保存し、対象外の従業員は理由を、決定できなかった従業員はエラーを結果に含める
+func RunCompanyTeijiKettei(db *gorm.DB, companyID uint, year int) ([]CompanyTeijiResult, error) {
+	var employees []models.Employee
+	if err := db.Where("company_id = ?", companyID).Order("id ASC").Find(&employees).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]CompanyTeijiResult, 0, len(employees))
+	for _, emp := range employees {
+		result := CompanyTeijiResult{EmployeeID: emp.ID, EmployeeName: emp.Name}
+		reason, err := teijiExclusionOf(db, emp, year)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case reason != "":
+			result.Skipped = reason
+		default:
+			sr, err := runTeijiKettei(db, emp.ID, year)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.StandardRemuneration = &sr
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// DetectZuijiKaitei 指定した年・月に固定的賃金が変動した場合の随時改定の要否を判定する
+// 変動月から3か月の報酬の平均による等級が現在の等級と2等級以上異なり、
+// 固定的賃金の増減と同じ方向に変わる場合に、変動月から4か月目以降の標準報酬月額を改定する
+func DetectZuijiKaitei(db *gorm.DB, employeeID uint, year, month int) (ZuijiKaiteiResult, error) {
+	fromYear, fromMonth := addMonths(year, month, 3)
+	result := ZuijiKaiteiResult{FromYear: fromYear, FromMonth: fromMonth}
+
+	prevYear, prevMonth := addMonths(year, month, -1)
+	prev, err := monthlyRemuneration(db, employeeID, prevYear, prevMonth)
+	if err != nil {
+		return result, err
+	}
+
+	months, err := remunerationsFrom(db, employeeID, year, month)
+	if err != nil {
+		return result, err
+	}
+	result.Months = months
+
+	fixedDiff := months[0].FixedWage - prev.FixedWage
+	if fixedDiff == 0 {
+		result.Reason = "fixed wage did not change"
+		return result, nil
+	}
+
+	for _, r := range months {
+		if !r.Counted {
+			result.Reason = "a month has fewer than 17 payment base days"
+			return result, nil
+		}
+	}
+	result.Average, _ = averageRemuneration(months)
+
+	lastYear, lastMonth := addMonths(year, month, 2)
+	current, err := StandardRemunerationInForce(db, employeeID, lastYear, lastMonth)
+	if err != nil {
+		return result, err
+	}
+	if current == nil {
+		result.Reason = "no standard remuneration is registered"
+		return result, nil
+	}
+	result.CurrentGrade = current.HealthGrade
+
+	next, err := determineStandardRemuneration(db, employeeID, result.Average, lastYear, lastMonth)
+	if err != nil {
+		return result, err
+	}
+	result.NewGrade = next.HealthGrade
+
+	currentGrade, err := strconv.Atoi(current.HealthGrade)
+	if err != nil {
+		return result, errors.New("invalid health grade: " + current.HealthGrade)
+	}
+	newGrade, err := strconv.Atoi(next.HealthGrade)
+	if err != nil {
+		return result, errors.New("invalid health grade: " + next.HealthGrade)
+	}
+
+	result.Triggered, result.Reason = zuijiKaiteiTriggered(fixedDiff, newGrade-currentGrade)
+	return result, nil
+}
+
+// zuijiKaiteiTriggered 固定的賃金の増減と等級差から随時改定の対象となるかを判定する (対象外の場合はその理由)
+func zuijiKaiteiTriggered(fixedDiff, gradeDiff int) (bool, string) {
+	switch {
+	case fixedDiff == 0:
+		return false, "fixed wage did not change"
+	case gradeDiff > -zuijiGradeDifference && gradeDiff < zuijiGradeDifference:
+		return false, "grade difference is less than 2"
+	case (gradeDiff > 0) != (fixedDiff > 0):
+		return false, "grade changed in the opposite direction of the fixed wage"
+	default:
+		return true, ""
+	}
+}
+
+// ApplyZuijiKaitei 随時改定の対象であれば、改定後の標準報酬月額を保存する
+func ApplyZuijiKaitei(db *gorm.DB, employeeID uint, year, month int) (ZuijiKaiteiResult, *models.StandardRemuneration, error) {
+	result, err := DetectZuijiKaitei(db, employeeID, year, month)
+	if err != nil || !result.Triggered {
+		return result, nil, err
+	}
+
+	lastYear, lastMonth := addMonths(year, month, 2)
+	sr, err := determineStandardRemuneration(db, employeeID, result.Average, lastYear, lastMonth)
+	if err != nil {
+		return result, nil, err
+	}
+	sr.Reason = models.RemunerationZuiji
+	sr.FromYear = result.FromYear
+	sr.FromMonth = result.FromMonth
+
+	if err := saveStandardRemuneration(db, &sr); err != nil {
+		return result, nil, err
+	}
+	return result, &sr, nil
+}
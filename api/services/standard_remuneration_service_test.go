@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestAverageRemuneration(t *testing.T) {
+	tests := []struct {
+		name   string
+		months []MonthlyRemuneration
+		want   int
+		wantOK bool
+	}{
+		{
+			"3か月の平均 (1円未満切り捨て)",
+			[]MonthlyRemuneration{{Amount: 300000, Counted: true}, {Amount: 310000, Counted: true}, {Amount: 305001, Counted: true}},
+			305000, true,
+		},
+		{
+			"支払基礎日数が17日未満の月を除く",
+			[]MonthlyRemuneration{{Amount: 300000, Counted: true}, {Amount: 120000, Counted: false}, {Amount: 320000, Counted: true}},
+			310000, true,
+		},
+		{
+			"算定に含める月がない",
+			[]MonthlyRemuneration{{Amount: 100000}, {Amount: 100000}, {Amount: 100000}},
+			0, false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := averageRemuneration(tt.months)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("averageRemuneration = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestZuijiKaiteiTriggered(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixedDiff int
+		gradeDiff int
+		want      bool
+	}{
+		{"昇給で2等級上がる", 30000, 2, true},
+		{"昇給で3等級上がる", 50000, 3, true},
+		{"降給で2等級下がる", -30000, -2, true},
+		{"1等級の変動は対象外", 10000, 1, false},
+		{"等級が変わらない", 10000, 0, false},
+		{"固定的賃金が変わらない", 0, 3, false},
+		{"昇給したが残業代の減少で2等級下がる", 10000, -2, false},
+		{"降給したが残業代の増加で2等級上がる", -10000, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := zuijiKaiteiTriggered(tt.fixedDiff, tt.gradeDiff)
+			if got != tt.want {
+				t.Errorf("zuijiKaiteiTriggered(%d, %d) = %v (%s), want %v", tt.fixedDiff, tt.gradeDiff, got, reason, tt.want)
+			}
+			if !got && reason == "" {
+				t.Errorf("zuijiKaiteiTriggered(%d, %d) returned no reason", tt.fixedDiff, tt.gradeDiff)
+			}
+		})
+	}
+}
+
+func TestTeijiExclusion(t *testing.T) {
+	hired := func(y int, m time.Month, d int) *time.Time {
+		date := localDate(y, m, d)
+		return &date
+	}
+	zuiji := func(year, month int) models.StandardRemuneration {
+		return models.StandardRemuneration{Reason: models.RemunerationZuiji, FromYear: year, FromMonth: month}
+	}
+	tests := []struct {
+		name      string
+		hireDate  *time.Time
+		revisions []models.StandardRemuneration
+		want      bool
+	}{
+		{"前年入社", hired(2024, time.April, 1), nil, false},
+		{"5月31日入社", hired(2025, time.May, 31), nil, false},
+		{"6月1日入社は対象外", hired(2025, time.June, 1), nil, true},
+		{"7月入社は対象外", hired(2025, time.July, 15), nil, true},
+		{"入社日がない", nil, nil, false},
+		{"7月の随時改定は対象外", nil, []models.StandardRemuneration{zuiji(2025, 7)}, true},
+		{"9月の随時改定は対象外", nil, []models.StandardRemuneration{zuiji(2025, 9)}, true},
+		{"6月の随時改定は対象", nil, []models.StandardRemuneration{zuiji(2025, 6)}, false},
+		{"10月の随時改定は対象", nil, []models.StandardRemuneration{zuiji(2025, 10)}, false},
+		{"前年の随時改定は対象", nil, []models.StandardRemuneration{zuiji(2024, 8)}, false},
+		{
+			"定時決定の履歴は対象", nil,
+			[]models.StandardRemuneration{{Reason: models.RemunerationTeiji, FromYear: 2025, FromMonth: 9}}, false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teijiExclusion(tt.hireDate, tt.revisions, 2025); (got != "") != tt.want {
+				t.Errorf("teijiExclusion = %q, want excluded %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockedEarnings(t *testing.T) {
+	payroll := PayrollCalculationResponse{
+		GrossSalary:    331000,
+		PayType:        models.PayTypeMonthly,
+		WageRate:       300000,
+		WorkDays:       20,
+		BaseSalary:     300000,
+		TotalAllowance: 25000,
+		OvertimePay:    5000,
+		LateNightPay:   1000,
+	}
+	allowances := []models.EmployeeAllowance{
+		{Amount: 20000, AllowanceType: models.AllowanceType{Type: "fixed"}},
+		{Amount: 5000, AllowanceType: models.AllowanceType{Type: "fixed", ExcludeFromStandardRemuneration: true}},
+	}
+
+	e := lockedEarnings(payroll, allowances)
+	if e.Gross() != payroll.GrossSalary {
+		t.Errorf("gross = %v, want %v", e.Gross(), payroll.GrossSalary)
+	}
+	// 社会保険の報酬に含めない手当は確定した支給額から除く
+	if got := e.insurableGross(); got != 326000 {
+		t.Errorf("insurable gross = %v, want 326000", got)
+	}
+	if e.PayType != models.PayTypeMonthly || e.Rate != 300000 || e.WorkDays != 20 {
+		t.Errorf("earnings = %+v", e)
+	}
+}
@@ -1,26 +1,99 @@
 package services
 
 import (
+	"errors"
+	"time"
+
 	"github.com/t2469/attendance-system.git/db"
 	"github.com/t2469/attendance-system.git/models"
-	"time"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func RecordTimeClock(empId uint, clockType models.TimeClockType, timestamp time.Time) (models.TimeClock, error) {
+// RecordTimeClock 勤務状態を検証したうえで打刻を登録し、勤務記録を再集計する
+// force が true の場合は状態の検証を行わない (管理者による後からの打刻登録など)
+// 出勤の打刻で notifyAt を指定した場合は、退勤打刻忘れの通知ジョブも同じトランザクションで登録する
+func RecordTimeClock(empId uint, clockType models.TimeClockType, timestamp time.Time, force bool, notifyAt *string) (models.TimeClock, error) {
 	timeClock := models.TimeClock{
 		EmployeeID: empId,
 		Type:       clockType,
 		Timestamp:  timestamp,
 	}
 
-	if err := db.DB.Create(&timeClock).Error; err != nil {
-		return timeClock, err
-	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// 同じ従業員の打刻が同時に登録されないよう従業員の行をロック
+		var emp models.Employee
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Company").First(&emp, empId).Error; err != nil {
+			return err
+		}
 
-	day := timestamp.Truncate(24 * time.Hour)
-	if err := UpsertWorkRecord(empId, day); err != nil {
+		if !force {
+			if err := validateClockTransition(tx, emp, clockType, timestamp); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&timeClock).Error; err != nil {
+			return err
+		}
+
+		if notifyAt != nil && clockType == models.ClockIn {
+			if _, err := ScheduleClockOutReminder(tx, empId, timestamp, *notifyAt); err != nil {
+				return err
+			}
+		}
+
+		return upsertWorkRecord(tx, empId, timestamp)
+	})
+	if err != nil {
 		return timeClock, err
 	}
 
 	return timeClock, nil
 }
+
+// CurrentWorkState 指定時刻時点の勤務状態を直前の打刻から求める
+// 出勤から最大勤務時間を過ぎても退勤していない場合は、打刻忘れとして勤務外に戻す
+func CurrentWorkState(tx *gorm.DB, emp models.Employee, at time.Time) (WorkState, error) {
+	var last models.TimeClock
+	err := tx.Where("employee_id = ? AND timestamp <= ?", emp.ID, at).
+		Order("timestamp DESC, id DESC").
+		First(&last).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return OffDuty, nil
+	} else if err != nil {
+		return OffDuty, err
+	}
+
+	state := stateAfter(last.Type)
+	if state == OffDuty {
+		return state, nil
+	}
+
+	_, err = findShiftStart(tx, emp.ID, at, maxShiftDuration(emp.Company))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return OffDuty, nil
+	} else if err != nil {
+		return OffDuty, err
+	}
+	return state, nil
+}
+
+// validateClockTransition 打刻が直前の状態から遷移可能か、また遡って打刻した場合に直後の打刻と矛盾しないかを確認する
+func validateClockTransition(tx *gorm.DB, emp models.Employee, clockType models.TimeClockType, at time.Time) error {
+	state, err := CurrentWorkState(tx, emp, at)
+	if err != nil {
+		return err
+	}
+
+	var following models.TimeClock
+	err = tx.Where("employee_id = ? AND timestamp > ?", emp.ID, at).
+		Order("timestamp ASC, id ASC").
+		First(&following).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return checkClockTransition(state, clockType, nil)
+	} else if err != nil {
+		return err
+	}
+	return checkClockTransition(state, clockType, &following.Type)
+}
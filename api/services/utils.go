@@ -2,11 +2,44 @@ package services
 
 import "time"
 
-func calculateAge(dob time.Time) int {
-	now := time.Now()
-	age := now.Year() - dob.Year()
-	if now.YearDay() < dob.YearDay() {
+// calculateAge 指定した年・月の末日時点の年齢
+// 「年齢計算ニ関スル法律」により誕生日の前日に年齢が加算されるため、
+// 誕生日の前日が属する月からその年齢として扱う (社会保険の資格の得喪と同じ基準)
+func calculateAge(dob time.Time, year, month int) int {
+	endOfMonth := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.Local)
+	// 1月1日生まれは前年の12月31日に年齢が加算されるため、翌年の年齢から確認する
+	age := year - dob.Year() + 1
+	for age > 0 && attainedAgeDate(dob, age).After(endOfMonth) {
 		age--
 	}
 	return age
 }
+
+// attainedAgeDate 指定した年齢に達する日 (誕生日の前日)
+// 2月29日生まれの場合、平年でも2月28日となる
+func attainedAgeDate(dob time.Time, age int) time.Time {
+	return time.Date(dob.Year()+age, dob.Month(), dob.Day()-1, 0, 0, 0, 0, time.Local)
+}
+
+// addMonths 年・月に指定した月数を加算した年・月を返す
+func addMonths(year, month, n int) (int, int) {
+	t := time.Date(year, time.Month(month)+time.Month(n), 1, 0, 0, 0, 0, time.Local)
+	return t.Year(), int(t.Month())
+}
+
+// daysInMonth 指定した年・月の暦日数
+func daysInMonth(year, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.Local).Day()
+}
+
+// addMonthsToDate 日付に指定した月数を加算する (応当日がない場合はその月の末日)
+func addMonthsToDate(t time.Time, n int) time.Time {
+	year, month := addMonths(t.Year(), int(t.Month()), n)
+	day := min(t.Day(), daysInMonth(year, month))
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+}
+
+// dateOnly 日付の部分のみ (時刻を0時にする)
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
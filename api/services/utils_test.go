@@ -0,0 +1,132 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func localDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestAttainedAgeDate(t *testing.T) {
+	tests := []struct {
+		name string
+		dob  time.Time
+		age  int
+		want time.Time
+	}{
+		{"誕生日の前日", localDate(1986, time.June, 15), 40, localDate(2026, time.June, 14)},
+		{"1日生まれは前月の末日", localDate(1986, time.May, 1), 40, localDate(2026, time.April, 30)},
+		{"1月1日生まれは前年の12月31日", localDate(1987, time.January, 1), 40, localDate(2026, time.December, 31)},
+		{"2月29日生まれは平年でも2月28日", localDate(2000, time.February, 29), 25, localDate(2025, time.February, 28)},
+		{"2月29日生まれのうるう年", localDate(2000, time.February, 29), 24, localDate(2024, time.February, 28)},
+		{"3月1日生まれはうるう年に2月29日", localDate(2000, time.March, 1), 24, localDate(2024, time.February, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attainedAgeDate(tt.dob, tt.age); !got.Equal(tt.want) {
+				t.Errorf("attainedAgeDate(%s, %d) = %s, want %s",
+					tt.dob.Format("2006-01-02"), tt.age, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestCalculateAge(t *testing.T) {
+	tests := []struct {
+		name  string
+		dob   time.Time
+		year  int
+		month int
+		want  int
+	}{
+		{"誕生月の前月", localDate(1986, time.June, 15), 2026, 5, 39},
+		{"誕生月", localDate(1986, time.June, 15), 2026, 6, 40},
+		{"2日生まれは誕生月から", localDate(1986, time.June, 2), 2026, 6, 40},
+		{"1日生まれは前月から", localDate(1986, time.May, 1), 2026, 4, 40},
+		{"1日生まれの前々月", localDate(1986, time.May, 1), 2026, 3, 39},
+		{"1月1日生まれは前年の12月から", localDate(1987, time.January, 1), 2026, 12, 40},
+		{"1月1日生まれの前年の11月", localDate(1987, time.January, 1), 2026, 11, 39},
+		{"2月29日生まれの平年の2月", localDate(2000, time.February, 29), 2025, 2, 25},
+		{"2月29日生まれの平年の1月", localDate(2000, time.February, 29), 2025, 1, 24},
+		{"2月29日生まれのうるう年の2月", localDate(2000, time.February, 29), 2024, 2, 24},
+		{"3月1日生まれはうるう年の2月から", localDate(2000, time.March, 1), 2024, 2, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateAge(tt.dob, tt.year, tt.month); got != tt.want {
+				t.Errorf("calculateAge(%s, %d, %d) = %d, want %d",
+					tt.dob.Format("2006-01-02"), tt.year, tt.month, got, tt.want)
+			}
+		})
+	}
+}
+
+// 介護保険料は40歳に達した日の属する月から、65歳に達した日の属する月の前月まで徴収する
+func TestCareInsuranceMonths(t *testing.T) {
+	tests := []struct {
+		name     string
+		dob      time.Time
+		year     int
+		month    int
+		wantCare bool
+	}{
+		{"40歳の前月", localDate(1986, time.July, 15), 2026, 6, false},
+		{"40歳に達した月", localDate(1986, time.July, 15), 2026, 7, true},
+		{"1日生まれは40歳の前月から", localDate(1986, time.August, 1), 2026, 7, true},
+		{"1日生まれの40歳の前々月", localDate(1986, time.August, 1), 2026, 6, false},
+		{"65歳の前月", localDate(1961, time.July, 15), 2026, 6, true},
+		{"65歳に達した月", localDate(1961, time.July, 15), 2026, 7, false},
+		{"1日生まれは65歳の前月で終了", localDate(1961, time.August, 1), 2026, 7, false},
+		{"2月29日生まれは平年の2月に40歳", localDate(1988, time.February, 29), 2028, 2, true},
+		{"2月29日生まれの40歳の前月", localDate(1988, time.February, 29), 2028, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age := calculateAge(tt.dob, tt.year, tt.month)
+			got := age >= careInsuranceMinAge && age < careInsuranceMaxAge
+			if got != tt.wantCare {
+				t.Errorf("care insurance for %s in %d/%d = %v (age %d), want %v",
+					tt.dob.Format("2006-01-02"), tt.year, tt.month, got, age, tt.wantCare)
+			}
+		})
+	}
+}
+
+// 厚生年金保険料は70歳に達した日の属する月から徴収しない
+func TestPensionEligibilityAt70(t *testing.T) {
+	tests := []struct {
+		name         string
+		dob          time.Time
+		year         int
+		month        int
+		wantEligible bool
+	}{
+		{"70歳の前月", localDate(1956, time.October, 20), 2026, 9, true},
+		{"70歳に達した月", localDate(1956, time.October, 20), 2026, 10, false},
+		{"1日生まれは70歳の前月から対象外", localDate(1956, time.November, 1), 2026, 10, false},
+		{"1日生まれの70歳の前々月", localDate(1956, time.November, 1), 2026, 9, true},
+	}
+	tables := rateTables{Pension: []models.PensionInsuranceRate{
+		{Grade: "22", MinMonthlyAmount: 290000, MaxMonthlyAmount: 310000, MonthlyAmount: 300000, PensionTotal: 54900, PensionHalf: 27450},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emp := models.Employee{DateOfBirth: tt.dob}
+			resp, err := calculatePensionInsurance(emp, nil, 300000, tables, tt.year, tt.month)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Eligible != tt.wantEligible {
+				t.Errorf("pension eligibility for %s in %d/%d = %v (age %d), want %v",
+					tt.dob.Format("2006-01-02"), tt.year, tt.month, resp.Eligible, resp.Age, tt.wantEligible)
+			}
+			if tt.wantEligible && resp.EmployeePension != 27450 {
+				t.Errorf("employee pension = %v, want 27450", resp.EmployeePension)
+			}
+		})
+	}
+}
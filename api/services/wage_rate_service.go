@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+// ErrWageRateInEffect 既に適用が始まった賃金の履歴は削除できない
+var ErrWageRateInEffect = errors.New("wage rate has already taken effect")
+
+// WageRateInForce 指定した年・月に適用される支払形態と賃金の額 (履歴がない場合は nil)
+func WageRateInForce(db *gorm.DB, employeeID uint, year, month int) (*models.WageRate, error) {
+	var rates []models.WageRate
+	if err := db.Where("employee_id = ?", employeeID).Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return wageRateAt(rates, year, month), nil
+}
+
+// wageRateAt 履歴のうち、指定した年・月以前に適用が始まった最も新しいもの (なければ nil)
+func wageRateAt(rates []models.WageRate, year, month int) *models.WageRate {
+	var inForce *models.WageRate
+	for i, r := range rates {
+		if r.FromYear > year || (r.FromYear == year && r.FromMonth > month) {
+			continue
+		}
+		if inForce == nil || r.FromYear > inForce.FromYear || (r.FromYear == inForce.FromYear && r.FromMonth > inForce.FromMonth) {
+			inForce = &rates[i]
+		}
+	}
+	return inForce
+}
+
+// SaveWageRate 適用開始月が同じ履歴があれば上書きして保存する
+func SaveWageRate(db *gorm.DB, rate *models.WageRate) error {
+	var existing models.WageRate
+	err := db.Where("employee_id = ? AND from_year = ? AND from_month = ?", rate.EmployeeID, rate.FromYear, rate.FromMonth).
+		First(&existing).Error
+	if err == nil {
+		rate.ID = existing.ID
+		rate.CreatedAt = existing.CreatedAt
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Save(rate).Error
+}
+
+// DeleteScheduledWageRate 適用開始前 (翌月以降から適用) の賃金の履歴を取り消す
+func DeleteScheduledWageRate(db *gorm.DB, employeeID, rateID uint, now time.Time) error {
+	var rate models.WageRate
+	if err := db.Where("employee_id = ?", employeeID).First(&rate, rateID).Error; err != nil {
+		return err
+	}
+	if rate.FromYear < now.Year() || (rate.FromYear == now.Year() && rate.FromMonth <= int(now.Month())) {
+		return ErrWageRateInEffect
+	}
+	return db.Delete(&rate).Error
+}
+
+// compensationInForce 指定した年・月の支払形態と月給・日給・時給の額
+// 履歴がない月は従業員に登録された支払形態と月給を使う (日給・時給者は履歴が必要)
+func compensationInForce(db *gorm.DB, emp models.Employee, year, month int) (string, int, error) {
+	rate, err := WageRateInForce(db, emp.ID, year, month)
+	if err != nil {
+		return "", 0, err
+	}
+	if rate != nil {
+		return rate.PayType, rate.Rate, nil
+	}
+
+	switch emp.PayType {
+	case models.PayTypeDaily, models.PayTypeHourly:
+		return "", 0, errors.New("wage rate not registered for the specified month")
+	default:
+		return models.PayTypeMonthly, emp.MonthlySalary, nil
+	}
+}
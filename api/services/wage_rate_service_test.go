@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/db/dbtest"
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func TestWageRateAt(t *testing.T) {
+	// 登録順は適用開始月の順とは限らない
+	rates := []models.WageRate{
+		{ID: 3, PayType: models.PayTypeHourly, Rate: 1300, FromYear: 2025, FromMonth: 4},
+		{ID: 1, PayType: models.PayTypeHourly, Rate: 1100, FromYear: 2024, FromMonth: 4},
+		{ID: 2, PayType: models.PayTypeHourly, Rate: 1200, FromYear: 2024, FromMonth: 12},
+	}
+	tests := []struct {
+		name  string
+		year  int
+		month int
+		want  uint // 0 は履歴なし
+	}{
+		{"最初の履歴の前月", 2024, 3, 0},
+		{"最初の履歴の適用開始月", 2024, 4, 1},
+		{"次の履歴の前月", 2024, 11, 1},
+		{"年末からの改定", 2024, 12, 2},
+		{"改定は翌年にも続く", 2025, 1, 2},
+		{"改定の前月", 2025, 3, 2},
+		{"改定の適用開始月", 2025, 4, 3},
+		{"最後の履歴以降", 2030, 1, 3},
+		{"前年の同じ月は前の履歴", 2024, 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wageRateAt(rates, tt.year, tt.month)
+			var gotID uint
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.want {
+				t.Errorf("wageRateAt(%d/%d) = rate %d, want %d", tt.year, tt.month, gotID, tt.want)
+			}
+		})
+	}
+	if wageRateAt(nil, 2025, 4) != nil {
+		t.Error("wageRateAt without history should be nil")
+	}
+}
+
+func TestCompensationInForce(t *testing.T) {
+	hourly := dbtest.Result{
+		Columns: []string{"id", "employee_id", "pay_type", "rate", "from_year", "from_month"},
+		Rows:    [][]any{{int64(1), int64(1), models.PayTypeHourly, int64(1200), int64(2025), int64(4)}},
+	}
+	tests := []struct {
+		name        string
+		history     bool
+		emp         models.Employee
+		month       int
+		wantPayType string
+		wantRate    int
+		wantErr     bool
+	}{
+		{"履歴のある月は履歴の額", true, models.Employee{ID: 1, PayType: models.PayTypeHourly}, 4, models.PayTypeHourly, 1200, false},
+		{"履歴のない月の月給者は従業員の月給", false, models.Employee{ID: 1, PayType: models.PayTypeMonthly, MonthlySalary: 300000}, 4, models.PayTypeMonthly, 300000, false},
+		{"適用開始前の月の月給者は従業員の月給", true, models.Employee{ID: 1, MonthlySalary: 300000}, 3, models.PayTypeMonthly, 300000, false},
+		{"適用開始前の月の時給者はエラー", true, models.Employee{ID: 1, PayType: models.PayTypeHourly}, 3, "", 0, true},
+		{"履歴のない日給者はエラー", false, models.Employee{ID: 1, PayType: models.PayTypeDaily}, 4, "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := dbtest.Open(t)
+			if tt.history {
+				rec.On(`FROM "wage_rates"`, hourly)
+			}
+			payType, rate, err := compensationInForce(db, tt.emp, 2025, tt.month)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if payType != tt.wantPayType || rate != tt.wantRate {
+				t.Errorf("compensationInForce = %s %d, want %s %d", payType, rate, tt.wantPayType, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestDeleteScheduledWageRate(t *testing.T) {
+	now := time.Date(2025, time.June, 30, 23, 0, 0, 0, time.Local)
+	tests := []struct {
+		name      string
+		fromYear  int
+		fromMonth int
+		wantErr   error
+	}{
+		{"翌月からの履歴は取り消せる", 2025, 7, nil},
+		{"翌年からの履歴は取り消せる", 2026, 1, nil},
+		{"今月からの履歴は適用済み", 2025, 6, ErrWageRateInEffect},
+		{"前年の同じ月以降の履歴は適用済み", 2024, 12, ErrWageRateInEffect},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := dbtest.Open(t)
+			rec.On(`FROM "wage_rates"`, dbtest.Result{
+				Columns: []string{"id", "employee_id", "pay_type", "rate", "from_year", "from_month"},
+				Rows:    [][]any{{int64(5), int64(1), models.PayTypeMonthly, int64(320000), int64(tt.fromYear), int64(tt.fromMonth)}},
+			})
+
+			err := DeleteScheduledWageRate(db, 1, 5, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			_, deleted := rec.Find(`DELETE FROM "wage_rates"`)
+			if deleted != (tt.wantErr == nil) {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/t2469/attendance-system.git/models"
+	"github.com/t2469/attendance-system.git/pdf"
+	"gorm.io/gorm"
+)
+
+// WithholdingSlip 給与所得の源泉徴収票の記載内容
+type WithholdingSlip struct {
+	Year                  int    `json:"year"`
+	PayerName             string `json:"payer_name"` // 支払者
+	EmployeeID            uint   `json:"employee_id"`
+	EmployeeName          string `json:"employee_name"` // 支払を受ける者
+	PaymentType           string `json:"payment_type"`  // 種別
+	PaymentAmount         int    `json:"payment_amount"`
+	SalaryIncome          int    `json:"salary_income"`            // 給与所得控除後の金額
+	TotalDeductions       int    `json:"total_deductions"`         // 所得控除の額の合計額
+	WithholdingTax        int    `json:"withholding_tax"`          // 源泉徴収税額 (年調年税額)
+	HasSpouse             bool   `json:"has_spouse"`               // 源泉控除対象配偶者の有無
+	ElderlySpouse         bool   `json:"elderly_spouse"`           // 老人控除対象配偶者
+	SpouseDeduction       int    `json:"spouse_deduction"`         // 配偶者 (特別) 控除の額
+	SpecificDependents    int    `json:"specific_dependents"`      // 控除対象扶養親族の数 (特定)
+	ElderlyDependents     int    `json:"elderly_dependents"`       // 控除対象扶養親族の数 (老人)
+	ElderlyParents        int    `json:"elderly_parents"`          // うち同居老親等
+	OtherDependents       int    `json:"other_dependents"`         // 控除対象扶養親族の数 (その他)
+	DependentsUnder16     int    `json:"dependents_under_16"`      // 16歳未満扶養親族の数
+	SocialInsurance       int    `json:"social_insurance"`         // 社会保険料等の金額
+	LifeInsurance         int    `json:"life_insurance"`           // 生命保険料の控除額
+	EarthquakeInsurance   int    `json:"earthquake_insurance"`     // 地震保険料の控除額
+	HousingLoanDeduction  int    `json:"housing_loan_deduction"`   // 住宅借入金等特別控除の額
+	BasicDeduction        int    `json:"basic_deduction"`          // 基礎控除の額
+	YearEndAdjustmentDiff int    `json:"year_end_adjustment_diff"` // 年末調整の過不足額 (正:徴収, 負:還付)
+}
+
+// BuildWithholdingSlip 計算済みの年末調整から源泉徴収票の記載内容を作る
+func BuildWithholdingSlip(db *gorm.DB, employeeID uint, year int) (WithholdingSlip, error) {
+	adj, err := GetYearEndAdjustment(db, employeeID, year)
+	if err != nil {
+		return WithholdingSlip{}, err
+	}
+	if adj.CalculatedAt == nil {
+		return WithholdingSlip{}, ErrYearEndAdjustmentNotCalculated
+	}
+
+	var emp models.Employee
+	if err := db.Preload("Company").First(&emp, employeeID).Error; err != nil {
+		return WithholdingSlip{}, err
+	}
+
+	slip := WithholdingSlip{
+		Year:                  year,
+		PayerName:             emp.Company.Name,
+		EmployeeID:            emp.ID,
+		EmployeeName:          emp.Name,
+		PaymentType:           "給料・賞与",
+		PaymentAmount:         adj.TotalPay,
+		SalaryIncome:          adj.SalaryIncome,
+		TotalDeductions:       adj.TotalDeductions,
+		WithholdingTax:        adj.AnnualTax,
+		HasSpouse:             adj.SpouseDeduction > 0,
+		SpouseDeduction:       adj.SpouseDeduction,
+		SocialInsurance:       adj.SocialInsuranceDeduction,
+		LifeInsurance:         adj.LifeInsuranceDeduction,
+		EarthquakeInsurance:   adj.EarthquakeInsuranceDeduction,
+		HousingLoanDeduction:  adj.HousingLoanDeduction,
+		BasicDeduction:        adj.BasicDeduction,
+		YearEndAdjustmentDiff: adj.Difference,
+	}
+	if slip.HasSpouse && adj.SpouseDateOfBirth != nil {
+		slip.ElderlySpouse = calculateAge(*adj.SpouseDateOfBirth, year, 12) >= 70
+	}
+
+	for _, d := range adj.Dependents {
+		switch dependentCategory(year, d) {
+		case dependentUnder16:
+			slip.DependentsUnder16++
+		case dependentSpecific:
+			slip.SpecificDependents++
+		case dependentElderlyParent:
+			slip.ElderlyDependents++
+			slip.ElderlyParents++
+		case dependentElderly:
+			slip.ElderlyDependents++
+		case dependentGeneral:
+			slip.OtherDependents++
+		}
+	}
+	return slip, nil
+}
+
+// RenderWithholdingSlipPDF 源泉徴収票をPDFで出力する
+func RenderWithholdingSlipPDF(db *gorm.DB, employeeID uint, year int) ([]byte, error) {
+	slip, err := BuildWithholdingSlip(db, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New()
+	drawWithholdingSlip(doc.AddPage(), slip)
+	return doc.Bytes()
+}
+
+// WithholdingSlipFileName 源泉徴収票PDFのファイル名
+func WithholdingSlipFileName(employeeID uint, year int) string {
+	return fmt.Sprintf("withholding_slip_%04d_%d.pdf", year, employeeID)
+}
+
+// drawWithholdingSlip 源泉徴収票を1ページに描画する
+// 上段の左に支払金額と税額、右に扶養親族等の数、下段に所得控除の内訳を並べる
+func drawWithholdingSlip(page *pdf.Page, slip WithholdingSlip) {
+	left := payslipMargin
+	right := pdf.PageWidth - payslipMargin
+
+	page.TextCenter(pdf.PageWidth/2, 790, 18, "給与所得の源泉徴収票")
+	page.TextCenter(pdf.PageWidth/2, 770, 11, fmt.Sprintf("%d年分", slip.Year))
+
+	page.Text(left, 735, 11, "支払を受ける者　"+slip.EmployeeName)
+	page.TextRight(right, 735, 11, "支払者　"+slip.PayerName)
+	page.Line(left, 725, right, 725, 0.8)
+
+	gap := 20.0
+	colWidth := (right - left - gap) / 2
+	top := 705.0
+
+	amountRows := []tableRow{
+		{Label: "種別", Value: slip.PaymentType},
+		{Label: "支払金額", Value: formatYen(float64(slip.PaymentAmount))},
+		{Label: "給与所得控除後の金額", Value: formatYen(float64(slip.SalaryIncome))},
+		{Label: "所得控除の額の合計額", Value: formatYen(float64(slip.TotalDeductions))},
+		{Label: "源泉徴収税額", Value: formatYen(float64(slip.WithholdingTax))},
+	}
+	amountBottom := drawTable(page, left, top, colWidth, "支払金額・税額", amountRows, tableRow{})
+
+	spouse := "無"
+	if slip.HasSpouse {
+		spouse = "有"
+		if slip.ElderlySpouse {
+			spouse = "有 (老人)"
+		}
+	}
+	dependentRows := []tableRow{
+		{Label: "控除対象配偶者", Value: spouse},
+		{Label: "特定扶養親族", Value: fmt.Sprintf("%d人", slip.SpecificDependents)},
+		{Label: "老人扶養親族", Value: fmt.Sprintf("%d人 (うち同居老親等 %d人)", slip.ElderlyDependents, slip.ElderlyParents)},
+		{Label: "その他の扶養親族", Value: fmt.Sprintf("%d人", slip.OtherDependents)},
+		{Label: "16歳未満扶養親族", Value: fmt.Sprintf("%d人", slip.DependentsUnder16)},
+	}
+	dependentBottom := drawTable(page, left+colWidth+gap, top, colWidth, "配偶者・扶養親族", dependentRows, tableRow{})
+
+	deductionRows := []tableRow{
+		{Label: "社会保険料等の金額", Value: formatYen(float64(slip.SocialInsurance))},
+		{Label: "生命保険料の控除額", Value: formatYen(float64(slip.LifeInsurance))},
+		{Label: "地震保険料の控除額", Value: formatYen(float64(slip.EarthquakeInsurance))},
+		{Label: "配偶者 (特別) 控除の額", Value: formatYen(float64(slip.SpouseDeduction))},
+		{Label: "基礎控除の額", Value: formatYen(float64(slip.BasicDeduction))},
+		{Label: "住宅借入金等特別控除の額", Value: formatYen(float64(slip.HousingLoanDeduction))},
+	}
+	top = min(amountBottom, dependentBottom) - gap
+	drawTable(page, left, top, right-left, "控除の内訳", deductionRows, tableRow{})
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"math"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxTableDependents 税額表に列がある扶養親族等の最大人数
+	maxTableDependents = 7
+	// extraDependentDeduction 扶養親族等が7人を超える場合に1人ごとに控除する税額
+	extraDependentDeduction = 1610
+	// otsuMinimumRate 乙欄で甲欄の最低の金額未満の場合の税率
+	otsuMinimumRate = 0.03063
+	// otsuExcessRate 乙欄で税額表の上限を超える部分に掛ける税率
+	otsuExcessRate = 0.4084
+)
+
+// withholdingParams 年分ごとの電算機計算の特例の控除額と、甲欄で税額が発生する最低の金額
+type withholdingParams struct {
+	FromYear               int     // 適用を開始する年分
+	KouMinimumAmount       int     // 甲欄で税額が発生する最低の金額 (乙欄はこれ未満で3.063%)
+	MinEmploymentDeduction float64 // 給与所得控除の最低額 (月額)
+	BasicDeduction         float64 // 基礎控除の額 (月額)
+}
+
+// withholdingParamsByYear 適用を開始する年分の昇順
+// 令和8年分からは令和7年度税制改正 (給与所得控除の最低額65万円・基礎控除58万円) を反映する
+var withholdingParamsByYear = []withholdingParams{
+	{FromYear: 2020, KouMinimumAmount: 88000, MinEmploymentDeduction: 45834, BasicDeduction: 40000},
+	{FromYear: 2026, KouMinimumAmount: 105000, MinEmploymentDeduction: 54167, BasicDeduction: 48334},
+}
+
+// withholdingParamsFor 指定した年分に適用する控除額 (最初の年分より前は最初の年分の額)
+func withholdingParamsFor(year int) withholdingParams {
+	params := withholdingParamsByYear[0]
+	for _, p := range withholdingParamsByYear {
+		if p.FromYear <= year {
+			params = p
+		}
+	}
+	return params
+}
+
+// ErrOtsuWithholdingTableNotRegistered 乙欄の税額を求めるための月額表が登録されていない
+var ErrOtsuWithholdingTableNotRegistered = errors.New("withholding tax table for otsu is not registered")
+
+// CalculateWithholdingTax 社会保険料等控除後の給与等の金額から源泉徴収税額を求める
+// 国税庁の月額表が登録されている場合はそれを使用し、指定年の表がなければそれより前で最新の年の表を使用する
+// 甲欄は月額表が登録されていない場合、電算機計算の特例で求める
+func CalculateWithholdingTax(db *gorm.DB, taxableAmount float64, dependents int, taxTable string, year int) (int, error) {
+	amount := int(math.Floor(taxableAmount))
+	if amount <= 0 {
+		return 0, nil
+	}
+	if taxTable == "" {
+		taxTable = models.TaxTableKou
+	}
+
+	tableYear, found, err := withholdingTableYear(db, taxTable, year)
+	if err != nil {
+		return 0, err
+	}
+
+	if taxTable == models.TaxTableOtsu {
+		if !found {
+			if amount < withholdingParamsFor(year).KouMinimumAmount {
+				return int(math.Floor(float64(amount) * otsuMinimumRate)), nil
+			}
+			return 0, ErrOtsuWithholdingTableNotRegistered
+		}
+		return otsuWithholdingTax(db, tableYear, amount)
+	}
+	if !found {
+		return ElectronicWithholdingTax(year, float64(amount), dependents), nil
+	}
+	return kouWithholdingTax(db, year, tableYear, amount, dependents)
+}
+
+// withholdingTableYear 指定年に適用する月額表の年 (登録がなければ found は false)
+func withholdingTableYear(db *gorm.DB, taxTable string, year int) (tableYear int, found bool, err error) {
+	var rate models.WithholdingTaxRate
+	if err := db.Where("tax_table = ? AND year <= ?", taxTable, year).Order("year desc").First(&rate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return rate.Year, true, nil
+}
+
+func kouWithholdingTax(db *gorm.DB, year, tableYear, amount, dependents int) (int, error) {
+	if amount < withholdingParamsFor(year).KouMinimumAmount {
+		return 0, nil
+	}
+
+	cols := min(dependents, maxTableDependents)
+	var rate models.WithholdingTaxRate
+	err := db.Where("year = ? AND tax_table = ? AND dependents = ? AND min_amount <= ? AND max_amount > ?",
+		tableYear, models.TaxTableKou, cols, amount, amount).
+		First(&rate).Error
+
+	var tax int
+	switch {
+	case err == nil:
+		tax = rate.Tax
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// 税額表の範囲を超える金額は電算機計算の特例で求める
+		tax = ElectronicWithholdingTax(year, float64(amount), cols)
+	default:
+		return 0, err
+	}
+
+	if dependents > maxTableDependents {
+		tax -= extraDependentDeduction * (dependents - maxTableDependents)
+	}
+	return max(tax, 0), nil
+}
+
+func otsuWithholdingTax(db *gorm.DB, tableYear, amount int) (int, error) {
+	var rate models.WithholdingTaxRate
+	err := db.Where("year = ? AND tax_table = ? AND min_amount <= ? AND max_amount > ?",
+		tableYear, models.TaxTableOtsu, amount, amount).
+		First(&rate).Error
+	if err == nil {
+		return rate.Tax, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	var first, last models.WithholdingTaxRate
+	if err := db.Where("year = ? AND tax_table = ?", tableYear, models.TaxTableOtsu).
+		Order("min_amount asc").First(&first).Error; err != nil {
+		return 0, err
+	}
+	if amount < first.MinAmount {
+		return int(math.Floor(float64(amount) * otsuMinimumRate)), nil
+	}
+
+	if err := db.Where("year = ? AND tax_table = ?", tableYear, models.TaxTableOtsu).
+		Order("max_amount desc").First(&last).Error; err != nil {
+		return 0, err
+	}
+	excess := float64(amount - last.MaxAmount)
+	return last.Tax + int(math.Floor(excess*otsuExcessRate)), nil
+}
+
+// ElectronicWithholdingTax 「月額表の甲欄を適用する給与等に対する源泉徴収税額の電算機計算の特例」による税額
+// amount は社会保険料等控除後の給与等の金額。控除額と税率は令和2年分以後の財務省告示により、年分ごとの控除額は withholdingParamsByYear による
+func ElectronicWithholdingTax(year int, amount float64, dependents int) int {
+	params := withholdingParamsFor(year)
+	if amount < float64(params.KouMinimumAmount) {
+		return 0
+	}
+
+	// 基礎控除の額
+	var basicDeduction float64
+	switch {
+	case amount <= 2162499:
+		basicDeduction = params.BasicDeduction
+	case amount <= 2204166:
+		basicDeduction = 26667
+	case amount <= 2245833:
+		basicDeduction = 13334
+	}
+
+	// 配偶者控除・扶養控除の額
+	dependentDeduction := 31667 * float64(dependents)
+
+	taxable := amount - MonthlyEmploymentIncomeDeduction(year, amount) - basicDeduction - dependentDeduction
+	return MonthlyIncomeTax(taxable)
+}
+
+// MonthlyEmploymentIncomeDeduction 電算機計算の特例における給与所得控除の額 (1円未満切り上げ、年分ごとの最低額を下回らない)
+func MonthlyEmploymentIncomeDeduction(year int, amount float64) float64 {
+	var deduction float64
+	switch {
+	case amount <= 149999:
+		deduction = math.Ceil(amount*0.4 - 8333)
+	case amount <= 299999:
+		deduction = math.Ceil(amount*0.3 + 6667)
+	case amount <= 549999:
+		deduction = math.Ceil(amount*0.2 + 36667)
+	case amount <= 708330:
+		deduction = math.Ceil(amount*0.1 + 91667)
+	default:
+		deduction = 162500
+	}
+	return max(deduction, withholdingParamsFor(year).MinEmploymentDeduction)
+}
+
+// MonthlyIncomeTax 電算機計算の特例における課税給与所得金額に対する税額 (復興特別所得税を含み、10円未満四捨五入)
+func MonthlyIncomeTax(taxable float64) int {
+	if taxable <= 0 {
+		return 0
+	}
+
+	var tax float64
+	switch {
+	case taxable <= 162500:
+		tax = taxable * 0.05105
+	case taxable <= 275000:
+		tax = taxable*0.1021 - 8296
+	case taxable <= 579166:
+		tax = taxable*0.2042 - 36374
+	case taxable <= 750000:
+		tax = taxable*0.23483 - 54113
+	case taxable <= 1500000:
+		tax = taxable*0.33693 - 130688
+	case taxable <= 3333333:
+		tax = taxable*0.4084 - 237893
+	default:
+		tax = taxable*0.45945 - 408061
+	}
+
+	return int(math.Round(tax/10) * 10)
+}
@@ -0,0 +1,90 @@
+package services
+
+import "testing"
+
+func TestMonthlyEmploymentIncomeDeduction(t *testing.T) {
+	tests := []struct {
+		year   int
+		amount float64
+		want   float64
+	}{
+		{2025, 88000, 45834},
+		{2025, 135416, 45834},
+		{2025, 140000, 47667},
+		{2025, 150000, 51667},
+		{2025, 300000, 96667},
+		{2025, 550000, 146667},
+		{2025, 708330, 162500},
+		{2025, 1000000, 162500},
+		{2026, 105000, 54167},
+		{2026, 150000, 54167},
+		{2026, 158333, 54167},
+		{2026, 200000, 66667},
+		{2026, 300000, 96667},
+		{2026, 1000000, 162500},
+	}
+	for _, tt := range tests {
+		if got := MonthlyEmploymentIncomeDeduction(tt.year, tt.amount); got != tt.want {
+			t.Errorf("MonthlyEmploymentIncomeDeduction(%d, %v) = %v, want %v", tt.year, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestMonthlyIncomeTax(t *testing.T) {
+	tests := []struct {
+		taxable float64
+		want    int
+	}{
+		{0, 0},
+		{-1000, 0},
+		{58333, 2980},
+		{162500, 8300},
+		{275000, 19780},
+		{579166, 81890},
+		{750000, 122010},
+		{1500000, 374710},
+		{3333333, 1123440},
+		{4000000, 1429740},
+	}
+	for _, tt := range tests {
+		if got := MonthlyIncomeTax(tt.taxable); got != tt.want {
+			t.Errorf("MonthlyIncomeTax(%v) = %d, want %d", tt.taxable, got, tt.want)
+		}
+	}
+}
+
+func TestElectronicWithholdingTax(t *testing.T) {
+	tests := []struct {
+		name       string
+		year       int
+		amount     float64
+		dependents int
+		want       int
+	}{
+		{"最低額", 2025, 88000, 0, 110},
+		{"最低額未満", 2025, 87999, 0, 0},
+		{"給与所得控除が30%の区分", 2025, 150000, 0, 2980},
+		{"扶養1人", 2025, 200000, 1, 3150},
+		{"扶養0人", 2025, 300000, 0, 8380},
+		{"扶養2人", 2025, 300000, 2, 5100},
+		{"扶養3人", 2025, 500000, 3, 15020},
+		{"給与所得控除の上限", 2025, 1000000, 2, 118290},
+		{"基礎控除なし", 2025, 2500000, 0, 716740},
+		{"控除が給与を上回る", 2025, 120000, 3, 0},
+		{"令和8年分: 令和7年分の最低額は課税されない", 2026, 88000, 0, 0},
+		{"令和8年分: 最低額未満", 2026, 104999, 0, 0},
+		{"令和8年分: 最低額", 2026, 105000, 0, 130},
+		{"令和8年分: 給与所得控除の最低額の区分", 2026, 150000, 0, 2420},
+		{"令和8年分: 扶養1人", 2026, 200000, 1, 2720},
+		{"令和8年分: 扶養0人", 2026, 300000, 0, 7910},
+		{"令和8年分: 基礎控除なし", 2026, 2500000, 0, 716740},
+		{"令和2年分より前は令和2年分の額", 2019, 88000, 0, 110},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ElectronicWithholdingTax(tt.year, tt.amount, tt.dependents); got != tt.want {
+				t.Errorf("ElectronicWithholdingTax(%d, %v, %d) = %d, want %d", tt.year, tt.amount, tt.dependents, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+// timeRange 勤務時間・休憩時間などの区間 [start, end)
+type timeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+// overlap 2つの区間が重なる時間
+func (r timeRange) overlap(o timeRange) time.Duration {
+	start := r.start
+	if o.start.After(start) {
+		start = o.start
+	}
+	end := r.end
+	if o.end.Before(end) {
+		end = o.end
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// workRanges 出勤から退勤までの区間から休憩を除いた実労働の区間
+func workRanges(clockIn, clockOut time.Time, breaks []timeRange) []timeRange {
+	if clockIn.IsZero() || clockOut.IsZero() || !clockOut.After(clockIn) {
+		return nil
+	}
+
+	var ranges []timeRange
+	cur := clockIn
+	for _, b := range breaks {
+		if b.start.After(cur) {
+			ranges = append(ranges, timeRange{cur, b.start})
+		}
+		if b.end.After(cur) {
+			cur = b.end
+		}
+	}
+	if clockOut.After(cur) {
+		ranges = append(ranges, timeRange{cur, clockOut})
+	}
+	return ranges
+}
+
+// eachDay 区間に含まれる各日の0時を順に返す (前日から始まる深夜帯も含めるため1日前から)
+func eachDay(ranges []timeRange) []time.Time {
+	if len(ranges) == 0 {
+		return nil
+	}
+	first := ranges[0].start.In(time.Local)
+	last := ranges[len(ranges)-1].end.In(time.Local)
+
+	var days []time.Time
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+	for !day.After(last) {
+		days = append(days, day)
+		day = day.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+// lateNightMinutes 実労働のうち深夜帯 (会社設定の開始時刻〜終了時刻) に含まれる分数
+func lateNightMinutes(ranges []timeRange, company models.Company) int64 {
+	var total time.Duration
+	for _, day := range eachDay(ranges) {
+		night := timeRange{
+			start: day.Add(time.Duration(company.LateNightStartHour) * time.Hour),
+			end:   day.Add(time.Duration(company.LateNightEndHour) * time.Hour),
+		}
+		// 開始時刻が終了時刻より遅い場合は日付をまたぐ
+		if company.LateNightStartHour >= company.LateNightEndHour {
+			night.end = night.end.AddDate(0, 0, 1)
+		}
+		for _, r := range ranges {
+			total += r.overlap(night)
+		}
+	}
+	return int64(total.Minutes())
+}
+
+// holidayMinutes 実労働のうち法定休日 (暦日の0:00〜24:00) に含まれる分数
+func holidayMinutes(ranges []timeRange, cal *companyCalendar) int64 {
+	var total time.Duration
+	for _, day := range eachDay(ranges) {
+		if !cal.legalHoliday(day) {
+			continue
+		}
+		holiday := timeRange{day, day.AddDate(0, 0, 1)}
+		for _, r := range ranges {
+			total += r.overlap(holiday)
+		}
+	}
+	return int64(total.Minutes())
+}
+
+// weekStart 会社の週の起算曜日をもとに、指定日が属する週の初日を返す
+func weekStart(date time.Time, company models.Company) time.Time {
+	d := date.In(time.Local)
+	offset := (int(d.Weekday()) - company.WeekStartWeekday + 7) % 7
+	return time.Date(d.Year(), d.Month(), d.Day()-offset, 0, 0, 0, 0, time.Local)
+}
+
+// dailyOvertimeMinutes 同じ日の勤務で既に workedToday 分働いている場合に、
+// 法定休日労働を除いた minutes 分の勤務のうち1日の法定労働時間を超える分数
+func dailyOvertimeMinutes(workedToday, minutes int64, company models.Company) int64 {
+	limit := int64(company.LegalDailyMinutes)
+	if workedToday+minutes <= limit {
+		return 0
+	}
+	return workedToday + minutes - max(workedToday, limit)
+}
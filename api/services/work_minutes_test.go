@@ -0,0 +1,147 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+)
+
+func testCompany() models.Company {
+	return models.Company{
+		LegalDailyMinutes:              480,
+		LegalWeeklyMinutes:             2400,
+		LateNightStartHour:             22,
+		LateNightEndHour:               5,
+		ExcessOvertimeThresholdMinutes: 3600,
+		OvertimePremiumRate:            0.25,
+		ExcessOvertimePremiumRate:      0.5,
+		LateNightPremiumRate:           0.25,
+		HolidayPremiumRate:             0.35,
+		WorkMinutesRoundingUnit:        1,
+		WageRoundingMode:               models.WageRoundingRound,
+	}
+}
+
+func localTime(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestDailyOvertimeMinutes(t *testing.T) {
+	company := testCompany()
+	tests := []struct {
+		name        string
+		workedToday int64
+		minutes     int64
+		want        int64
+	}{
+		{"法定労働時間ちょうど", 0, 480, 0},
+		{"1時間超過", 0, 540, 60},
+		{"同じ日の2回目の勤務で超過", 300, 300, 120},
+		{"既に超過している日の勤務はすべて時間外", 500, 60, 60},
+		{"合計が法定労働時間以内", 200, 200, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dailyOvertimeMinutes(tt.workedToday, tt.minutes, company); got != tt.want {
+				t.Errorf("dailyOvertimeMinutes(%d, %d) = %d, want %d", tt.workedToday, tt.minutes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegalOvertimeMinutes(t *testing.T) {
+	company := testCompany()
+	day := func(d int) time.Time { return localTime(2025, time.June, d, 0, 0) }
+	record := func(d int, work, holiday int64) models.WorkRecord {
+		return models.WorkRecord{Date: day(d), WorkMinutes: work, HolidayMinutes: holiday}
+	}
+
+	tests := []struct {
+		name    string
+		records []models.WorkRecord
+		setting *models.FlextimeSetting
+		want    []int64
+	}{
+		{
+			"同じ日の分割勤務は合計で1日の法定労働時間を判定",
+			[]models.WorkRecord{record(2, 300, 0), record(2, 300, 0)},
+			nil,
+			[]int64{0, 120},
+		},
+		{
+			"別の日の勤務は合算しない",
+			[]models.WorkRecord{record(2, 300, 0), record(3, 300, 0)},
+			nil,
+			[]int64{0, 0},
+		},
+		{
+			"週40時間を超えた6日目",
+			[]models.WorkRecord{record(2, 480, 0), record(3, 480, 0), record(4, 480, 0), record(5, 480, 0), record(6, 480, 0), record(7, 480, 0)},
+			nil,
+			[]int64{0, 0, 0, 0, 0, 480},
+		},
+		{
+			"1日の時間外は週の累計に含めない",
+			[]models.WorkRecord{record(2, 540, 0), record(3, 480, 0), record(4, 480, 0), record(5, 480, 0), record(6, 480, 0), record(7, 240, 0)},
+			nil,
+			[]int64{60, 0, 0, 0, 0, 240},
+		},
+		{
+			"法定休日労働は時間外に含めない",
+			[]models.WorkRecord{record(1, 600, 600), record(2, 540, 0)},
+			nil,
+			[]int64{0, 60},
+		},
+		{
+			"フレックスタイム制の期間は0",
+			[]models.WorkRecord{record(2, 600, 0)},
+			&models.FlextimeSetting{StartYear: 2025, StartMonth: 6, SettlementMonths: 1},
+			[]int64{0},
+		},
+		{
+			"フレックスタイム制の適用前は通常どおり",
+			[]models.WorkRecord{record(2, 600, 0)},
+			&models.FlextimeSetting{StartYear: 2025, StartMonth: 7, SettlementMonths: 1},
+			[]int64{120},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := legalOvertimeMinutes(tt.records, company, tt.setting); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("legalOvertimeMinutes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLateNightMinutes(t *testing.T) {
+	company := testCompany()
+	tests := []struct {
+		name     string
+		clockIn  time.Time
+		clockOut time.Time
+		breaks   []timeRange
+		want     int64
+	}{
+		{"日中のみ", localTime(2025, time.June, 2, 9, 0), localTime(2025, time.June, 2, 18, 0), nil, 0},
+		{"22時以降", localTime(2025, time.June, 2, 18, 0), localTime(2025, time.June, 2, 23, 30), nil, 90},
+		{"日付をまたぐ", localTime(2025, time.June, 2, 21, 0), localTime(2025, time.June, 3, 6, 0), nil, 420},
+		{"早朝", localTime(2025, time.June, 2, 4, 0), localTime(2025, time.June, 2, 9, 0), nil, 60},
+		{
+			"深夜の休憩を除く",
+			localTime(2025, time.June, 2, 21, 0), localTime(2025, time.June, 3, 6, 0),
+			[]timeRange{{localTime(2025, time.June, 3, 0, 0), localTime(2025, time.June, 3, 1, 0)}},
+			360,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := workRanges(tt.clockIn, tt.clockOut, tt.breaks)
+			if got := lateNightMinutes(ranges, company); got != tt.want {
+				t.Errorf("lateNightMinutes = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,68 +9,281 @@ import (
 	"gorm.io/gorm"
 )
 
-func UpsertWorkRecord(empID uint, date time.Time) error {
-	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
-	to := from.AddDate(0, 0, 1)
+// UpsertWorkRecord 指定時刻を含む勤務 (出勤から退勤まで) の勤務記録を再集計する
+func UpsertWorkRecord(empID uint, at time.Time) error {
+	return upsertWorkRecord(db.DB, empID, at)
+}
+
+// upsertWorkRecord トランザクション内から呼び出せるようtxを受け取る
+func upsertWorkRecord(tx *gorm.DB, empID uint, at time.Time) error {
+	var emp models.Employee
+	if err := tx.Preload("Company").First(&emp, empID).Error; err != nil {
+		return err
+	}
+
+	start, err := findShiftStart(tx, empID, at, maxShiftDuration(emp.Company))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 対応する出勤打刻がなければ集計対象の勤務はない
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return aggregateShift(tx, emp, start)
+}
+
+// ApplyClockCorrection 打刻の種別・時刻を修正し、修正前後の勤務記録を再集計する
+// 給与計算が締められた月の勤務記録に影響する場合は打刻も変更しない
+func ApplyClockCorrection(clock *models.TimeClock, clockType models.TimeClockType, timestamp time.Time) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		oldTimestamp := clock.Timestamp
+		clock.Type = clockType
+		clock.Timestamp = timestamp
+		if err := tx.Save(clock).Error; err != nil {
+			return err
+		}
+		return refreshWorkRecordsForClock(tx, *clock, oldTimestamp)
+	})
+}
+
+// refreshWorkRecordsForClock 打刻の種別・時刻を修正した後に、修正前後の勤務記録を再集計する
+func refreshWorkRecordsForClock(tx *gorm.DB, clock models.TimeClock, oldTimestamp time.Time) error {
+	// 出勤打刻でなくなった場合は、その打刻を起点とする勤務記録を削除
+	// (給与計算が締められた月でないかを確認するため1件ずつ削除する)
+	if clock.Type != models.ClockIn {
+		var records []models.WorkRecord
+		if err := tx.Where("clock_in_id = ?", clock.ID).Find(&records).Error; err != nil {
+			return err
+		}
+		for i := range records {
+			if err := tx.Delete(&records[i]).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := upsertWorkRecord(tx, clock.EmployeeID, oldTimestamp); err != nil {
+		return err
+	}
+	return upsertWorkRecord(tx, clock.EmployeeID, clock.Timestamp)
+}
+
+// maxShiftDuration 1回の勤務として扱う最大の長さ
+func maxShiftDuration(company models.Company) time.Duration {
+	hours := company.MaxShiftHours
+	if hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// BusinessDate 会社の日付切り替え時刻をもとに、指定時刻が属する営業日を返す
+// 例: 切り替え時刻が5時なら、翌日4:59までの勤務は前日の営業日として扱う
+func BusinessDate(t time.Time, startHour int) time.Time {
+	shifted := t.In(time.Local).Add(-time.Duration(startHour) * time.Hour)
+	return time.Date(shifted.Year(), shifted.Month(), shifted.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// findShiftStart 指定時刻を含む勤務の出勤打刻を探す (最大勤務時間より前の出勤打刻は対象外)
+func findShiftStart(tx *gorm.DB, empID uint, at time.Time, maxShift time.Duration) (models.TimeClock, error) {
+	var start models.TimeClock
+	err := tx.Where("employee_id = ? AND type = ? AND timestamp <= ? AND timestamp > ?",
+		empID, models.ClockIn, at, at.Add(-maxShift)).
+		Order("timestamp DESC, id DESC").
+		First(&start).Error
+	return start, err
+}
+
+// aggregateShift 出勤打刻から、次の出勤打刻・退勤打刻・最大勤務時間のいずれかまでを1つの勤務として集計する
+func aggregateShift(tx *gorm.DB, emp models.Employee, start models.TimeClock) error {
+	limit := start.Timestamp.Add(maxShiftDuration(emp.Company))
+
+	var next models.TimeClock
+	err := tx.Where("employee_id = ? AND type = ? AND timestamp > ? AND timestamp < ?",
+		emp.ID, models.ClockIn, start.Timestamp, limit).
+		Order("timestamp ASC").
+		First(&next).Error
+	if err == nil {
+		limit = next.Timestamp
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
 
 	var clocks []models.TimeClock
-	if err := db.DB.
-		Where("employee_id = ? AND timestamp >= ? AND timestamp < ?", empID, from, to).
+	if err := tx.
+		Where("employee_id = ? AND timestamp > ? AND timestamp < ?", emp.ID, start.Timestamp, limit).
 		Order("timestamp ASC").
 		Find(&clocks).Error; err != nil {
 		return err
 	}
 
-	var clockIn, clockOut time.Time
-	var breakDur time.Duration
+	clockIn := start.Timestamp
+	var clockOut time.Time
+	var breaks []timeRange
 	var breakStart *time.Time
 
 	for _, clock := range clocks {
-		switch clock.Type {
-		case models.ClockIn:
-			if clockIn.IsZero() {
-				clockIn = clock.Timestamp
-			}
-		case models.ClockOut:
+		if clock.Type == models.ClockOut {
 			clockOut = clock.Timestamp
+			break
+		}
+		switch clock.Type {
 		case models.BreakBegin:
 			breakStart = &clock.Timestamp
 		case models.BreakEnd:
 			if breakStart != nil {
-				breakDur += clock.Timestamp.Sub(*breakStart)
+				breaks = append(breaks, timeRange{*breakStart, clock.Timestamp})
 				breakStart = nil
 			}
 		}
 	}
 
+	var breakDur time.Duration
+	for _, b := range breaks {
+		breakDur += b.end.Sub(b.start)
+	}
+
 	workDur := time.Duration(0)
-	if !clockIn.IsZero() && !clockOut.IsZero() && clockOut.After(clockIn) {
+	if !clockOut.IsZero() && clockOut.After(clockIn) {
 		workDur = clockOut.Sub(clockIn) - breakDur
 	}
 
-	breakMin := int64(breakDur.Minutes())
-	workMin := int64(workDur.Minutes())
+	ranges := workRanges(clockIn, clockOut, breaks)
+	date := BusinessDate(clockIn, emp.Company.BusinessDayStartHour)
+	cal, err := calendarForRanges(tx, emp.Company, ranges)
+	if err != nil {
+		return err
+	}
 
-	var wr models.WorkRecord
-	err := db.DB.Where("employee_id = ? AND date = ?", empID, from).First(&wr).Error
+	wr, err := findShiftWorkRecord(tx, emp.ID, start)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		wr = models.WorkRecord{
-			EmployeeID:   empID,
-			Date:         from,
-			ClockIn:      clockIn,
-			ClockOut:     clockOut,
-			BreakMinutes: breakMin,
-			WorkMinutes:  workMin,
-		}
-		return db.DB.Create(&wr).Error
+		wr = models.WorkRecord{EmployeeID: emp.ID}
 	} else if err != nil {
 		return err
 	}
+	wr.ClockInID = &start.ID
+
+	// 勤務日が変わった場合は変更前の週の時間外労働も再計算する
+	prevDate := wr.Date
 
+	wr.Date = date
 	wr.ClockIn = clockIn
 	wr.ClockOut = clockOut
-	wr.BreakMinutes = breakMin
-	wr.WorkMinutes = workMin
+	wr.BreakMinutes = int64(breakDur.Minutes())
+	wr.WorkMinutes = int64(workDur.Minutes())
+	wr.LateNightMinutes = lateNightMinutes(ranges, emp.Company)
+	wr.HolidayMinutes = holidayMinutes(ranges, cal)
 
-	return db.DB.Save(&wr).Error
+	if err := tx.Save(&wr).Error; err != nil {
+		return err
+	}
+
+	if !prevDate.IsZero() && !weekStart(prevDate, emp.Company).Equal(weekStart(date, emp.Company)) {
+		if err := recalculateOvertime(tx, emp, prevDate); err != nil {
+			return err
+		}
+	}
+	if err := recalculateOvertime(tx, emp, date); err != nil {
+		return err
+	}
+
+	// 勤務予定と比べた遅刻・早退・予定外の勤務を判定する (勤務日が変わった場合は変更前の日も)
+	if !prevDate.IsZero() && !dateOnly(prevDate).Equal(date) {
+		if err := annotateWorkDate(tx, emp.ID, prevDate); err != nil {
+			return err
+		}
+	}
+	return annotateWorkDate(tx, emp.ID, date)
+}
+
+// findShiftWorkRecord 出勤打刻を起点とする勤務記録を探す
+// 出勤打刻と紐付いていない以前の勤務記録 (暦日ごとに1件) があれば、それを引き継ぐ
+func findShiftWorkRecord(tx *gorm.DB, empID uint, start models.TimeClock) (models.WorkRecord, error) {
+	var wr models.WorkRecord
+	err := tx.Where("employee_id = ? AND clock_in_id = ?", empID, start.ID).First(&wr).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return wr, err
+	}
+	err = tx.Where("employee_id = ? AND clock_in_id IS NULL AND leave_type = '' AND date = ?",
+		empID, dateOnly(start.Timestamp.In(time.Local))).
+		First(&wr).Error
+	return wr, err
+}
+
+// LinkLegacyWorkRecords 出勤打刻と紐付いていない以前の勤務記録を、出勤時刻が一致する出勤打刻に紐付ける
+// 勤務単位で集計するようになる前の記録を再集計したときに、同じ日の勤務記録が重複しないようにする
+func LinkLegacyWorkRecords(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE work_records SET clock_in_id = (
+			SELECT MIN(time_clocks.id) FROM time_clocks
+			WHERE time_clocks.employee_id = work_records.employee_id
+				AND time_clocks.type = ? AND time_clocks.timestamp = work_records.clock_in
+		)
+		WHERE clock_in_id IS NULL AND leave_type = '' AND EXISTS (
+			SELECT 1 FROM time_clocks
+			WHERE time_clocks.employee_id = work_records.employee_id
+				AND time_clocks.type = ? AND time_clocks.timestamp = work_records.clock_in
+		)`, models.ClockIn, models.ClockIn).Error
+}
+
+// recalculateOvertime 指定日が属する週の勤務記録について法定時間外労働を再計算する
+func recalculateOvertime(tx *gorm.DB, emp models.Employee, date time.Time) error {
+	from := weekStart(date, emp.Company)
+	to := from.AddDate(0, 0, 7)
+
+	var records []models.WorkRecord
+	if err := tx.
+		Where("employee_id = ? AND date >= ? AND date < ?", emp.ID, from, to).
+		Order("date ASC, clock_in ASC").
+		Find(&records).Error; err != nil {
+		return err
+	}
+
+	// フレックスタイム制の勤務は1日・1週で判定せず、清算期間の総労働時間で判定する
+	setting, err := flextimeSettingFor(tx, emp)
+	if err != nil {
+		return err
+	}
+
+	overtimes := legalOvertimeMinutes(records, emp.Company, setting)
+	for i, wr := range records {
+		overtime := overtimes[i]
+		if wr.OvertimeMinutes == overtime {
+			continue
+		}
+		if err := tx.Model(&wr).Update("overtime_minutes", overtime).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legalOvertimeMinutes 1週間分の勤務記録 (日付・出勤時刻の順) それぞれの法定時間外労働の分数
+// 1日の法定労働時間は同じ日の勤務の合計で判定し、超えた分を後の勤務に割り当てる
+// さらに週の法定労働時間を超えた分を週の後半の勤務に割り当てる
+// フレックスタイム制の勤務は0とする (時間外労働は CalculateFlextimeSettlement で清算期間ごとに求める)
+func legalOvertimeMinutes(records []models.WorkRecord, company models.Company, setting *models.FlextimeSetting) []int64 {
+	overtimes := make([]int64, len(records))
+	weeklyLimit := int64(company.LegalWeeklyMinutes)
+	workedByDate := make(map[time.Time]int64)
+	var cumulative int64
+	for i, wr := range records {
+		if setting != nil && setting.Covers(wr.Date) {
+			continue
+		}
+
+		date := dateOnly(wr.Date.In(time.Local))
+		minutes := wr.WorkMinutes - wr.HolidayMinutes
+		daily := dailyOvertimeMinutes(workedByDate[date], minutes, company)
+		workedByDate[date] += minutes
+		regular := minutes - daily
+
+		var weekly int64
+		if cumulative+regular > weeklyLimit {
+			weekly = cumulative + regular - max(cumulative, weeklyLimit)
+		}
+		cumulative += regular
+		overtimes[i] = daily + weekly
+	}
+	return overtimes
 }
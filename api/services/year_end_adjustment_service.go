@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrYearEndAdjustmentNotCalculated 年末調整が計算されていない
+	ErrYearEndAdjustmentNotCalculated = errors.New("year-end adjustment has not been calculated")
+	// ErrYearEndAdjustmentNotEligible 乙欄の適用者と給与等の収入金額が2,000万円を超える者は年末調整の対象外
+	ErrYearEndAdjustmentNotEligible = errors.New("employee is not eligible for year-end adjustment")
+)
+
+// yearEndAdjustmentPayLimit 年末調整の対象となる給与等の収入金額の上限
+const yearEndAdjustmentPayLimit = 20000000
+
+// annualPay その年の給与・賞与の合計
+type annualPay struct {
+	TotalPay        float64
+	SocialInsurance float64
+	WithheldTax     float64
+}
+
+// SaveYearEndDeclaration 年末調整の申告内容を保存する (扶養親族は置き換える)
+// 申告内容が変わると年税額も変わるため、計算結果は未計算に戻す
+func SaveYearEndDeclaration(db *gorm.DB, adj *models.YearEndAdjustment) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing models.YearEndAdjustment
+		err := tx.Where("employee_id = ? AND year = ?", adj.EmployeeID, adj.Year).First(&existing).Error
+		if err == nil {
+			adj.ID = existing.ID
+			adj.CreatedAt = existing.CreatedAt
+			if err := tx.Where("adjustment_id = ?", existing.ID).Delete(&models.YearEndDependent{}).Error; err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		for i := range adj.Dependents {
+			adj.Dependents[i].ID = 0
+			adj.Dependents[i].AdjustmentID = adj.ID
+		}
+		adj.CalculatedAt = nil
+		return tx.Save(adj).Error
+	})
+}
+
+// GetYearEndAdjustment 扶養親族を含めて年末調整を取得
+func GetYearEndAdjustment(db *gorm.DB, employeeID uint, year int) (models.YearEndAdjustment, error) {
+	var adj models.YearEndAdjustment
+	err := db.Preload("Dependents", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("employee_id = ? AND year = ?", employeeID, year).
+		First(&adj).Error
+	return adj, err
+}
+
+// CalculateYearEndAdjustment その年の給与・賞与と申告内容から年税額を求め、源泉徴収した税額との過不足額を保存する
+// 申告内容が登録されていない場合は、基礎控除と社会保険料控除のみで計算する
+// 年末調整の対象外の従業員は ErrYearEndAdjustmentNotEligible を返し、保存しない
+func CalculateYearEndAdjustment(db *gorm.DB, employeeID uint, year int) (models.YearEndAdjustment, error) {
+	var emp models.Employee
+	if err := db.First(&emp, employeeID).Error; err != nil {
+		return models.YearEndAdjustment{}, err
+	}
+
+	adj, err := GetYearEndAdjustment(db, employeeID, year)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		adj = models.YearEndAdjustment{EmployeeID: employeeID, Year: year}
+	} else if err != nil {
+		return adj, err
+	}
+	if err := yearEndAdjustmentEligibility(emp.TaxTable, 0); err != nil {
+		return adj, err
+	}
+
+	pay, err := collectAnnualPay(db, emp, year)
+	if err != nil {
+		return adj, err
+	}
+
+	applyYearEndAdjustment(&adj, pay)
+	if err := yearEndAdjustmentEligibility(emp.TaxTable, adj.TotalPay); err != nil {
+		return adj, err
+	}
+	now := time.Now()
+	adj.CalculatedAt = &now
+
+	if err := db.Omit("Dependents").Save(&adj).Error; err != nil {
+		return adj, err
+	}
+	return adj, nil
+}
+
+// yearEndAdjustmentEligibility 年末調整の対象外であれば理由を含めた ErrYearEndAdjustmentNotEligible を返す
+// totalPay は前職分を含むその年の給与等の収入金額
+func yearEndAdjustmentEligibility(taxTable string, totalPay int) error {
+	if taxTable == models.TaxTableOtsu {
+		return fmt.Errorf("%w: the otsu column of the withholding tax table applies", ErrYearEndAdjustmentNotEligible)
+	}
+	if totalPay > yearEndAdjustmentPayLimit {
+		return fmt.Errorf("%w: annual pay exceeds 20,000,000 yen", ErrYearEndAdjustmentNotEligible)
+	}
+	return nil
+}
+
+// yearEndPayFromMonth その年の給与を合計する最初の月 (入社日の月。その年より後の入社は13)
+func yearEndPayFromMonth(hireDate *time.Time, year int) int {
+	switch {
+	case hireDate == nil || hireDate.Year() < year:
+		return 1
+	case hireDate.Year() > year:
+		return 13
+	default:
+		return int(hireDate.Month())
+	}
+}
+
+// collectAnnualPay その年の入社後の各月の給与 (締められた月は確定した給与明細) と、その年に支給した賞与を合計する
+// 給与の支払金額には非課税の手当を含めない。前職分は申告内容から applyYearEndAdjustment で加える
+func collectAnnualPay(db *gorm.DB, emp models.Employee, year int) (annualPay, error) {
+	var pay annualPay
+	for month := yearEndPayFromMonth(emp.HireDate, year); month <= 12; month++ {
+		p, err := PayrollForMonth(db, emp.ID, year, month)
+		if err != nil {
+			return pay, err
+		}
+		pay.TotalPay += p.GrossSalary - p.NonTaxableAllowance
+		pay.SocialInsurance += p.HealthInsurance + p.Pension + p.EmploymentInsurance
+		pay.WithheldTax += p.WithholdingTax
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	var bonuses []models.BonusPayment
+	if err := db.Where("employee_id = ? AND paid_on >= ? AND paid_on < ?", emp.ID, from, from.AddDate(1, 0, 0)).
+		Order("paid_on ASC").
+		Find(&bonuses).Error; err != nil {
+		return pay, err
+	}
+	for _, b := range bonuses {
+		bonus, err := CalculateBonus(db, b.ID)
+		if err != nil {
+			return pay, err
+		}
+		pay.TotalPay += bonus.BonusAmount
+		pay.SocialInsurance += bonus.HealthInsurance + bonus.Pension + bonus.EmploymentInsurance
+		pay.WithheldTax += bonus.WithholdingTax
+	}
+	return pay, nil
+}
+
+// applyYearEndAdjustment 給与所得控除後の給与等の金額から所得控除を差し引いて年調年税額を求める
+// 前職分の支払金額・社会保険料等・源泉徴収税額は、この会社の給与・賞与に合算する
+func applyYearEndAdjustment(adj *models.YearEndAdjustment, pay annualPay) {
+	year := adj.Year
+	adj.TotalPay = int(math.Round(pay.TotalPay)) + adj.PreviousEmployerPay
+	adj.SalaryIncome = salaryIncome(year, adj.TotalPay)
+	income := adj.SalaryIncome
+
+	adj.SocialInsuranceDeduction = int(math.Round(pay.SocialInsurance)) + adj.PreviousEmployerSocialInsurance + adj.OtherSocialInsurance
+	adj.LifeInsuranceDeduction = lifeInsuranceDeduction(adj.LifeInsuranceNew, adj.LifeInsuranceOld,
+		adj.CareMedicalInsurance, adj.PensionInsuranceNew, adj.PensionInsuranceOld)
+	adj.EarthquakeInsuranceDeduction = earthquakeInsuranceDeduction(adj.EarthquakeInsurance, adj.OldLongTermInsurance)
+
+	adj.SpouseDeduction = 0
+	if adj.HasSpouse && adj.SpouseDateOfBirth != nil {
+		elderly := calculateAge(*adj.SpouseDateOfBirth, year, 12) >= 70
+		adj.SpouseDeduction = spouseDeduction(year, income, adj.SpouseIncome, elderly)
+	}
+
+	adj.DependentDeduction = 0
+	for _, d := range adj.Dependents {
+		adj.DependentDeduction += dependentDeductionAmount(dependentCategory(year, d))
+	}
+
+	adj.BasicDeduction = basicDeduction(year, income)
+	adj.TotalDeductions = adj.SocialInsuranceDeduction + adj.LifeInsuranceDeduction + adj.EarthquakeInsuranceDeduction +
+		adj.SpouseDeduction + adj.DependentDeduction + adj.BasicDeduction
+
+	adj.TaxableIncome = max(income-adj.TotalDeductions, 0) / 1000 * 1000
+	adj.CalculatedTax = incomeTaxAmount(adj.TaxableIncome)
+	adj.HousingLoanDeduction = min(adj.HousingLoanCredit, adj.CalculatedTax)
+	adj.AnnualTax = annualTaxWithSurtax(adj.CalculatedTax - adj.HousingLoanDeduction)
+	adj.WithheldTax = int(math.Round(pay.WithheldTax)) + adj.PreviousEmployerWithheldTax
+	adj.Difference = adj.AnnualTax - adj.WithheldTax
+}
+
+// dependentCategory 扶養親族の12月31日時点の区分
+func dependentCategory(year int, d models.YearEndDependent) string {
+	return classifyDependent(year, calculateAge(d.DateOfBirth, year, 12), d.Income, d.CohabitingParent)
+}
+
+// yearEndAdjustmentDifference 12月の給与で精算する年末調整の過不足額 (計算されていない場合と年末調整の対象外の場合は0)
+// 計算した後に乙欄に変わった従業員や、給与等の収入金額が上限を超えた従業員は精算しない
+func yearEndAdjustmentDifference(db *gorm.DB, emp models.Employee, year int) (int, error) {
+	if emp.TaxTable == models.TaxTableOtsu {
+		return 0, nil
+	}
+	var adj models.YearEndAdjustment
+	err := db.Where("employee_id = ? AND year = ? AND calculated_at IS NOT NULL", emp.ID, year).First(&adj).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if yearEndAdjustmentEligibility(emp.TaxTable, adj.TotalPay) != nil {
+		return 0, nil
+	}
+	return adj.Difference, nil
+}
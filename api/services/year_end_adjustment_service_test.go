@@ -0,0 +1,179 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t2469/attendance-system.git/db/dbtest"
+	"github.com/t2469/attendance-system.git/models"
+	"gorm.io/gorm"
+)
+
+func TestYearEndPayFromMonth(t *testing.T) {
+	date := func(y int, m time.Month, d int) *time.Time {
+		t := localDate(y, m, d)
+		return &t
+	}
+	tests := []struct {
+		name     string
+		hireDate *time.Time
+		want     int
+	}{
+		{"前年以前の入社は1月から", date(2020, time.April, 1), 1},
+		{"入社日がない", nil, 1},
+		{"1月入社", date(2025, time.January, 6), 1},
+		{"年の中途の入社は入社月から", date(2025, time.April, 15), 4},
+		{"12月入社", date(2025, time.December, 1), 12},
+		{"翌年の入社は対象の月なし", date(2026, time.January, 5), 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yearEndPayFromMonth(tt.hireDate, 2025); got != tt.want {
+				t.Errorf("yearEndPayFromMonth = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYearEndAdjustmentEligibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		taxTable string
+		totalPay int
+		wantErr  bool
+	}{
+		{"甲欄", models.TaxTableKou, 5000000, false},
+		{"2,000万円ちょうど", models.TaxTableKou, 20000000, false},
+		{"2,000万円超", models.TaxTableKou, 20000001, true},
+		{"乙欄", models.TaxTableOtsu, 1000000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := yearEndAdjustmentEligibility(tt.taxTable, tt.totalPay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrYearEndAdjustmentNotEligible) {
+				t.Errorf("error %v is not ErrYearEndAdjustmentNotEligible", err)
+			}
+		})
+	}
+}
+
+func TestApplyYearEndAdjustmentPreviousEmployer(t *testing.T) {
+	pay := annualPay{TotalPay: 2700000, SocialInsurance: 405000, WithheldTax: 45000}
+
+	current := models.YearEndAdjustment{Year: 2025}
+	applyYearEndAdjustment(&current, pay)
+
+	withPrevious := models.YearEndAdjustment{
+		Year:                            2025,
+		PreviousEmployerPay:             900000,
+		PreviousEmployerSocialInsurance: 135000,
+		PreviousEmployerWithheldTax:     20000,
+	}
+	applyYearEndAdjustment(&withPrevious, pay)
+
+	// 前職分は支払金額・社会保険料控除・源泉徴収税額にそれぞれ合算する
+	if got, want := withPrevious.TotalPay, current.TotalPay+900000; got != want {
+		t.Errorf("total pay = %d, want %d", got, want)
+	}
+	if got, want := withPrevious.SocialInsuranceDeduction, current.SocialInsuranceDeduction+135000; got != want {
+		t.Errorf("social insurance deduction = %d, want %d", got, want)
+	}
+	if got, want := withPrevious.WithheldTax, current.WithheldTax+20000; got != want {
+		t.Errorf("withheld tax = %d, want %d", got, want)
+	}
+	if withPrevious.SalaryIncome != salaryIncome(2025, 3600000) {
+		t.Errorf("salary income = %d, want the income of 3,600,000 yen", withPrevious.SalaryIncome)
+	}
+	if withPrevious.Difference != withPrevious.AnnualTax-withPrevious.WithheldTax {
+		t.Errorf("difference = %d, want annual tax %d - withheld %d", withPrevious.Difference, withPrevious.AnnualTax, withPrevious.WithheldTax)
+	}
+}
+
+// yearEndTestDB 従業員・申告内容と、各月の確定した給与明細を返すDB
+func yearEndTestDB(t *testing.T, taxTable string, hireDate time.Time, monthly PayrollCalculationResponse) (*gorm.DB, *dbtest.Recorder) {
+	t.Helper()
+	snapshot, err := json.Marshal(monthly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, rec := dbtest.Open(t)
+	rec.On(`FROM "employees" WHERE`, dbtest.Result{
+		Columns: []string{"id", "name", "tax_table", "hire_date"},
+		Rows:    [][]any{{int64(1), "山田 太郎", taxTable, hireDate}},
+	})
+	rec.On(`FROM "year_end_adjustments"`, dbtest.Result{
+		Columns: []string{"id", "employee_id", "year", "previous_employer_pay", "previous_employer_social_insurance", "previous_employer_withheld_tax"},
+		Rows:    [][]any{{int64(4), int64(1), int64(2025), int64(900000), int64(135000), int64(20000)}},
+	})
+	rec.On(`FROM "payslips"`, dbtest.Result{
+		Columns: []string{"id", "payroll_run_id", "employee_id", "snapshot"},
+		Rows:    [][]any{{int64(3), int64(7), int64(1), string(snapshot)}},
+	})
+	rec.On(`UPDATE "year_end_adjustments"`, dbtest.Result{RowsAffected: 1})
+	return db, rec
+}
+
+func TestCalculateYearEndAdjustmentMidYearHire(t *testing.T) {
+	db, rec := yearEndTestDB(t, models.TaxTableKou, localDate(2025, time.April, 1), PayrollCalculationResponse{
+		GrossSalary: 300000, HealthInsurance: 15000, Pension: 27450, EmploymentInsurance: 1650, WithholdingTax: 6000,
+	})
+
+	adj, err := CalculateYearEndAdjustment(db, 1, 2025)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 4月入社のため4月〜12月の9か月分の給与と前職分を合計する
+	lookups := 0
+	for _, q := range rec.Queries() {
+		if strings.Contains(q.SQL, `FROM "payslips"`) {
+			lookups++
+			if month := q.Args[2]; month.(int) < 4 {
+				t.Errorf("pay for month %v before hire was collected", month)
+			}
+		}
+	}
+	if lookups != 9 {
+		t.Errorf("collected %d months, want 9", lookups)
+	}
+	if adj.TotalPay != 9*300000+900000 {
+		t.Errorf("total pay = %d, want %d", adj.TotalPay, 9*300000+900000)
+	}
+	if adj.WithheldTax != 9*6000+20000 {
+		t.Errorf("withheld tax = %d, want %d", adj.WithheldTax, 9*6000+20000)
+	}
+	if adj.CalculatedAt == nil {
+		t.Error("adjustment is not marked as calculated")
+	}
+}
+
+func TestCalculateYearEndAdjustmentNotEligible(t *testing.T) {
+	tests := []struct {
+		name     string
+		taxTable string
+		monthly  float64
+	}{
+		{"乙欄", models.TaxTableOtsu, 300000},
+		// 12か月の給与と前職分で2,000万円を超える
+		{"給与等の収入金額が2,000万円超", models.TaxTableKou, 1600000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, rec := yearEndTestDB(t, tt.taxTable, localDate(2020, time.April, 1), PayrollCalculationResponse{GrossSalary: tt.monthly})
+
+			_, err := CalculateYearEndAdjustment(db, 1, 2025)
+			if !errors.Is(err, ErrYearEndAdjustmentNotEligible) {
+				t.Fatalf("error = %v, want ErrYearEndAdjustmentNotEligible", err)
+			}
+			if _, ok := rec.Find(`UPDATE "year_end_adjustments"`); ok {
+				t.Error("ineligible adjustment was saved")
+			}
+		})
+	}
+}
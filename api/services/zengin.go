@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/t2469/attendance-system.git/models"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// zenginRecordLength 全銀フォーマットの1レコードのバイト数
+const zenginRecordLength = 120
+
+// zenginSmallKana 全銀フォーマットで使えない小書きの半角カナと、置き換える文字
+var zenginSmallKana = strings.NewReplacer(
+	"ｧ", "ｱ", "ｨ", "ｲ", "ｩ", "ｳ", "ｪ", "ｴ", "ｫ", "ｵ",
+	"ｬ", "ﾔ", "ｭ", "ﾕ", "ｮ", "ﾖ", "ｯ", "ﾂ",
+)
+
+// zenginKana 名義を全銀フォーマットで使える半角文字 (半角カナ・英大文字・数字・一部の記号) に変換する
+// ひらがな・全角カナは半角カナに、濁点・半濁点は1文字として分け、小書きのカナは大書きにする
+func zenginKana(s string) (string, error) {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case r >= 'ぁ' && r <= 'ゖ':
+			r += 'ァ' - 'ぁ'
+		case r == '゙':
+			r = 'ﾞ'
+		case r == '゚':
+			r = 'ﾟ'
+		case r == 'ー' || r == 'ｰ' || r == '－' || r == '‐':
+			r = '-'
+		}
+		sb.WriteRune(r)
+	}
+
+	converted := zenginSmallKana.Replace(strings.ToUpper(width.Narrow.String(sb.String())))
+	for _, r := range converted {
+		if !isZenginChar(r) {
+			return "", errors.New("contains a character that cannot be used in a bank transfer: " + string(r))
+		}
+	}
+	return strings.TrimSpace(converted), nil
+}
+
+// isZenginChar 全銀フォーマットの文字項目に使える文字か
+func isZenginChar(r rune) bool {
+	switch {
+	case r >= 'ｦ' && r <= 'ﾟ':
+		return true
+	case r < unicode.MaxASCII && (unicode.IsDigit(r) || unicode.IsUpper(r)):
+		return true
+	}
+	return strings.ContainsRune(" ().,-/\\", r)
+}
+
+// zenginText 文字項目を左詰めで指定した桁数にする (超える部分は切り捨て、不足分は空白)
+func zenginText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		runes = runes[:n]
+	}
+	return string(runes) + strings.Repeat(" ", n-len(runes))
+}
+
+// zenginNumber 数字項目を右詰めで指定した桁数にする (不足分は0)
+func zenginNumber(s string, n int) string {
+	if len(s) >= n {
+		return s[len(s)-n:]
+	}
+	return strings.Repeat("0", n-len(s)) + s
+}
+
+// zenginAccountType 預金種目のコード (1:普通, 2:当座, 4:貯蓄)
+func zenginAccountType(accountType string) string {
+	switch accountType {
+	case models.AccountTypeChecking:
+		return "2"
+	case models.AccountTypeSavings:
+		return "4"
+	default:
+		return "1"
+	}
+}